@@ -15,8 +15,48 @@ type AppConfig struct {
 		Connection string
 	}
 	JWTSecret string
+	SMTP      struct {
+		Host     string
+		Port     int
+		Username string
+		Password string
+		Sender   string
+	}
+	SMSGateway struct {
+		URL    string
+		APIKey string
+	}
+	Reminder struct {
+		DaysBeforeDue   uint
+		OverdueInterval uint
+	}
+	Review struct {
+		BorrowersOnly bool
+	}
+	ReviewScreening struct {
+		WordListFile string
+		MinLength    uint
+		MaxLength    uint
+		MaxLinks     uint
+	}
+	Cover struct {
+		StorageDir string
+		MaxSize    uint
+	}
+	OAI struct {
+		RepositoryName string
+		Identifier     string
+		BaseURL        string
+		AdminEmail     string
+	}
 }
 
+// loan period is 7 days, so reminder beyond it is meaningless
+const (
+	MinDaysBeforeDue = 1
+	MaxDaysBeforeDue = 6
+)
+
 var appConfig *AppConfig
 
 func loadEnv() (err error) {
@@ -84,6 +124,90 @@ func GetConfig() (*AppConfig, error) {
 		initConfig.Database.Driver = os.Getenv("DB_DRIVER")
 		initConfig.Database.Connection = os.Getenv("DB_CONNECTION_STRING")
 		initConfig.JWTSecret = os.Getenv("JWT_SECRET")
+		initConfig.SMTP.Host = os.Getenv("SMTP_HOST")
+		initConfig.SMTP.Port, _ = strconv.Atoi(os.Getenv("SMTP_PORT"))
+		initConfig.SMTP.Username = os.Getenv("SMTP_USERNAME")
+		initConfig.SMTP.Password = os.Getenv("SMTP_PASSWORD")
+		initConfig.SMTP.Sender = os.Getenv("SMTP_SENDER")
+		initConfig.SMSGateway.URL = os.Getenv("SMS_GATEWAY_URL")
+		initConfig.SMSGateway.APIKey = os.Getenv("SMS_GATEWAY_API_KEY")
+
+		// reminder defaults to 2 days before due date and every 3 days when overdue
+		daysBeforeDue, err := strconv.Atoi(os.Getenv("REMINDER_DAYS_BEFORE_DUE"))
+
+		if err != nil || daysBeforeDue < MinDaysBeforeDue || daysBeforeDue > MaxDaysBeforeDue {
+			daysBeforeDue = 2
+		}
+
+		overdueInterval, err := strconv.Atoi(os.Getenv("REMINDER_OVERDUE_INTERVAL"))
+
+		if err != nil || overdueInterval < 1 {
+			overdueInterval = 3
+		}
+
+		initConfig.Reminder.DaysBeforeDue = uint(daysBeforeDue)
+		initConfig.Reminder.OverdueInterval = uint(overdueInterval)
+
+		// any member may review unless restricted to past borrowers
+		initConfig.Review.BorrowersOnly, _ = strconv.ParseBool(os.Getenv("REVIEW_BORROWERS_ONLY"))
+
+		// review screening defaults to 10 to 2000 characters without any link
+		initConfig.ReviewScreening.WordListFile = os.Getenv("REVIEW_WORDLIST_FILE")
+
+		minLength, err := strconv.Atoi(os.Getenv("REVIEW_MIN_LENGTH"))
+
+		if err != nil || minLength < 1 {
+			minLength = 10
+		}
+
+		maxLength, err := strconv.Atoi(os.Getenv("REVIEW_MAX_LENGTH"))
+
+		if err != nil || maxLength < minLength {
+			maxLength = 2000
+		}
+
+		maxLinks, err := strconv.Atoi(os.Getenv("REVIEW_MAX_LINKS"))
+
+		if err != nil || maxLinks < 0 {
+			maxLinks = 0
+		}
+
+		initConfig.ReviewScreening.MinLength = uint(minLength)
+		initConfig.ReviewScreening.MaxLength = uint(maxLength)
+		initConfig.ReviewScreening.MaxLinks = uint(maxLinks)
+
+		// cover is stored under storage directory, upload defaults to at most 5 MB
+		initConfig.Cover.StorageDir = os.Getenv("COVER_STORAGE_DIR")
+
+		if initConfig.Cover.StorageDir == "" {
+			initConfig.Cover.StorageDir = "storage"
+		}
+
+		coverMaxSize, err := strconv.Atoi(os.Getenv("COVER_MAX_SIZE"))
+
+		if err != nil || coverMaxSize < 1 {
+			coverMaxSize = 5 << 20
+		}
+
+		initConfig.Cover.MaxSize = uint(coverMaxSize)
+
+		// OAI-PMH repository description, identifier becomes part of every record identifier
+		initConfig.OAI.RepositoryName = os.Getenv("OAI_REPOSITORY_NAME")
+		initConfig.OAI.Identifier = os.Getenv("OAI_REPOSITORY_IDENTIFIER")
+		initConfig.OAI.BaseURL = os.Getenv("OAI_BASE_URL")
+		initConfig.OAI.AdminEmail = os.Getenv("OAI_ADMIN_EMAIL")
+
+		if initConfig.OAI.RepositoryName == "" {
+			initConfig.OAI.RepositoryName = "Public Library"
+		}
+
+		if initConfig.OAI.Identifier == "" {
+			initConfig.OAI.Identifier = "public-library"
+		}
+
+		if initConfig.OAI.BaseURL == "" {
+			initConfig.OAI.BaseURL = "http://localhost:3000/oai"
+		}
 
 		appConfig = &initConfig
 	}
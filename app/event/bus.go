@@ -0,0 +1,68 @@
+package event
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	_entity "plain-go/public-library/entity"
+)
+
+type Publisher interface {
+	Publish(event _entity.Event)
+}
+
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[chan _entity.Event]struct{}
+	sequence    uint64
+}
+
+func NewBus() *Bus {
+	return &Bus{subscribers: map[chan _entity.Event]struct{}{}}
+}
+
+func (b *Bus) Publish(event _entity.Event) {
+	// complete event metadata
+	if event.Id == "" {
+		event.Id = fmt.Sprintf("%d-%d", time.Now().UnixNano(), atomic.AddUint64(&b.sequence, 1))
+	}
+
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	// slow subscriber must not block publisher, so event is dropped for it
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			log.Println("event dropped for slow subscriber:", event.Type)
+		}
+	}
+}
+
+func (b *Bus) Subscribe(buffer int) (events <-chan _entity.Event, unsubscribe func()) {
+	subscriber := make(chan _entity.Event, buffer)
+
+	b.mu.Lock()
+	b.subscribers[subscriber] = struct{}{}
+	b.mu.Unlock()
+
+	unsubscribe = func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if _, exist := b.subscribers[subscriber]; exist {
+			delete(b.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+
+	return subscriber, unsubscribe
+}
@@ -0,0 +1,101 @@
+package event
+
+import (
+	"log"
+	_outboxRepository "plain-go/public-library/datastore/outbox"
+	_entity "plain-go/public-library/entity"
+	"time"
+)
+
+type Handler func(event _entity.Event) (err error)
+
+// failed event is retried after 2s, 4s, 8s and so on, then parked after maximum attempts
+const (
+	maxAttempts  = 10
+	firstBackoff = 2 * time.Second
+)
+
+type subscriber struct {
+	name    string
+	handler Handler
+}
+
+// every subscriber is tracked separately, so failing subscriber is retried on next poll
+// without repeating the ones already succeeded (at-least-once delivery)
+type Dispatcher struct {
+	outbox      _outboxRepository.Outbox
+	subscribers []subscriber
+}
+
+func NewDispatcher(outbox _outboxRepository.Outbox) *Dispatcher {
+	return &Dispatcher{outbox: outbox}
+}
+
+func (d *Dispatcher) Subscribe(name string, handler Handler) {
+	d.subscribers = append(d.subscribers, subscriber{name: name, handler: handler})
+}
+
+func (d *Dispatcher) Run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		d.dispatch()
+	}
+}
+
+func (d *Dispatcher) dispatch() {
+	// calling repository
+	events, err := d.outbox.GetPendingEvents(100)
+
+	if err != nil {
+		return
+	}
+
+	for _, event := range events {
+		complete := true
+
+		for _, subscriber := range d.subscribers {
+			handled, err := d.outbox.IsEventHandled(event.Id, subscriber.name)
+
+			if err != nil {
+				complete = false
+				continue
+			}
+
+			if handled {
+				continue
+			}
+
+			if err = subscriber.handler(event); err != nil {
+				log.Println(subscriber.name, event.Type, event.Id, err)
+				complete = false
+				continue
+			}
+
+			if err = d.outbox.MarkEventHandled(event.Id, subscriber.name); err != nil {
+				complete = false
+			}
+		}
+
+		// event stays pending until every subscriber handled it, meanwhile later events go on
+		if !complete {
+			d.retryLater(event)
+			continue
+		}
+
+		d.outbox.MarkEventDispatched(event.Id)
+	}
+}
+
+func (d *Dispatcher) retryLater(event _entity.Event) {
+	attempts := event.Attempts + 1
+
+	if attempts >= maxAttempts {
+		log.Println("event parked after maximum attempts:", event.Type, event.Id)
+		d.outbox.ParkEvent(event.Id, attempts)
+		return
+	}
+
+	d.outbox.DelayEvent(event.Id, attempts, time.Now().Add(firstBackoff<<(attempts-1)))
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	_config "plain-go/public-library/app/config"
+	_util "plain-go/public-library/app/util"
+	_acquisitionRepository "plain-go/public-library/datastore/acquisition"
+	_bookRepository "plain-go/public-library/datastore/book"
+	_bookUseCase "plain-go/public-library/usecase/book"
+	_catalogUseCase "plain-go/public-library/usecase/catalog"
+	_duplicate "plain-go/public-library/usecase/duplicate"
+	_enrichment "plain-go/public-library/usecase/enrichment"
+	"strconv"
+)
+
+func init() {
+	os.Setenv("TZ", "Asia/Jakarta")
+	log.SetFlags(log.Llongfile | log.LstdFlags)
+}
+
+// usage: marcimport [-format marc21|marcxml] [-copies n] file...
+func main() {
+	format := flag.String("format", "", "record format, marc21 or marcxml, detected from content when empty")
+	copies := flag.Uint("copies", 1, "number of copies created for each record")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: marcimport [-format marc21|marcxml] [-copies n] file...")
+		os.Exit(2)
+	}
+
+	// get application configuration
+	config, err := _config.GetConfig()
+
+	if err != nil {
+		panic("error in application configuration")
+	}
+
+	// get database instance
+	db, err := _util.GetDBInstance(config)
+
+	if err != nil {
+		panic("error in database connection")
+	}
+
+	bookRepository := _bookRepository.New(db)
+	acquisitionRepository := _acquisitionRepository.New(db)
+	detector := _duplicate.New(bookRepository, acquisitionRepository)
+
+	// duplicates are only found among records with match key
+	if err := detector.BackfillKeys(); err != nil {
+		panic("error in match key backfill")
+	}
+
+	bookUseCase := _bookUseCase.New(bookRepository, acquisitionRepository, detector, _enrichment.NewStubProvider())
+	catalogUseCase := _catalogUseCase.New(bookRepository, bookUseCase)
+
+	query := url.Values{}
+	query.Set("format", *format)
+	query.Set("copies", strconv.Itoa(int(*copies)))
+
+	failed := false
+
+	for _, path := range flag.Args() {
+		file, err := os.Open(path)
+
+		if err != nil {
+			log.Println(err)
+			failed = true
+			continue
+		}
+
+		res, code, message := catalogUseCase.ImportBooks(query, file)
+		file.Close()
+
+		if code != http.StatusOK {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", path, message)
+			failed = true
+			continue
+		}
+
+		// print the per-record report of each file
+		report, _ := json.MarshalIndent(map[string]interface{}{"file": path, "report": res}, "", "  ")
+		fmt.Println(string(report))
+
+		if res.Failed > 0 {
+			failed = true
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
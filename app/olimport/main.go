@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	_config "plain-go/public-library/app/config"
+	_util "plain-go/public-library/app/util"
+	_openLibraryRepository "plain-go/public-library/datastore/openlibrary"
+	_enrichment "plain-go/public-library/usecase/enrichment"
+)
+
+func init() {
+	os.Setenv("TZ", "Asia/Jakarta")
+	log.SetFlags(log.Llongfile | log.LstdFlags)
+}
+
+// usage: olimport [-works file] [-authors file] editions-file
+func main() {
+	works := flag.String("works", "", "works dump, fills description, subject and authors missing on edition")
+	authors := flag.String("authors", "", "authors dump, author names are left out without it")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: olimport [-works file] [-authors file] editions-file")
+		os.Exit(2)
+	}
+
+	// get application configuration
+	config, err := _config.GetConfig()
+
+	if err != nil {
+		panic("error in application configuration")
+	}
+
+	// get database instance
+	db, err := _util.GetDBInstance(config)
+
+	if err != nil {
+		panic("error in database connection")
+	}
+
+	// records of the dumps replace those indexed before, so import can be repeated with newer dumps
+	provider := _enrichment.NewOpenLibraryProvider(_openLibraryRepository.New(db))
+
+	if err := provider.IndexDumps(flag.Arg(0), *works, *authors); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to index open library dumps:", err)
+		os.Exit(1)
+	}
+}
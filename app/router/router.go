@@ -9,12 +9,24 @@ import (
 	"time"
 
 	_mw "plain-go/public-library/app/middleware"
+	_acquisition "plain-go/public-library/controller/acquisition"
+	_author "plain-go/public-library/controller/author"
 	_book "plain-go/public-library/controller/book"
+	_catalog "plain-go/public-library/controller/catalog"
+	_citation "plain-go/public-library/controller/citation"
+	_cover "plain-go/public-library/controller/cover"
+	_event "plain-go/public-library/controller/event"
 	_favorite "plain-go/public-library/controller/favorite"
+	_notification "plain-go/public-library/controller/notification"
+	_oai "plain-go/public-library/controller/oai"
+	_readingList "plain-go/public-library/controller/readinglist"
 	_request "plain-go/public-library/controller/request"
 	_review "plain-go/public-library/controller/review"
+	_subject "plain-go/public-library/controller/subject"
 	_user "plain-go/public-library/controller/user"
+	_webhook "plain-go/public-library/controller/webhook"
 	_wish "plain-go/public-library/controller/wish"
+	_work "plain-go/public-library/controller/work"
 	_model "plain-go/public-library/model"
 )
 
@@ -39,38 +51,133 @@ func Router(
 	wish *_wish.WishController,
 	review *_review.ReviewController,
 	request *_request.RequestController,
+	notification *_notification.NotificationController,
+	event *_event.EventController,
+	webhook *_webhook.WebhookController,
+	acquisition *_acquisition.AcquisitionController,
+	author *_author.AuthorController,
+	readingList *_readingList.ReadingListController,
+	catalog *_catalog.CatalogController,
+	oai *_oai.OAIController,
+	cover *_cover.CoverController,
+	work *_work.WorkController,
+	subject *_subject.SubjectController,
+	citation *_citation.CitationController,
 ) http.HandlerFunc {
 	routes := []route{
 		NewRoute(http.MethodPost, `/login`, _mw.Do(_mw.JSONRequest).Then(user.Login()).ServeHTTP),
 		NewRoute(http.MethodPost, `/users`, _mw.Do(_mw.JSONRequest).Then(user.SignUp()).ServeHTTP),
 		NewRoute(http.MethodGet, `/users`, _mw.Do(_mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(user.GetAll()).ServeHTTP),
+		NewRoute(http.MethodGet, `/users/(.+)/notifications`, _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.AuthorizedById).Then(notification.GetAll()).ServeHTTP),
+		NewRoute(http.MethodPut, `/users/(.+)/notifications`, _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.AuthorizedById, _mw.JSONRequest).Then(notification.MarkAsRead()).ServeHTTP),
+		NewRoute(http.MethodDelete, `/users/(.+)/notifications`, _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.AuthorizedById, _mw.JSONRequest).Then(notification.Dismiss()).ServeHTTP),
+		NewRoute(http.MethodGet, `/users/(.+)/preferences`, _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.AuthorizedById).Then(notification.GetPreference()).ServeHTTP),
+		NewRoute(http.MethodPut, `/users/(.+)/preferences`, _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.AuthorizedById, _mw.JSONRequest).Then(notification.UpdatePreference()).ServeHTTP),
+		NewRoute(http.MethodGet, `/users/(.+)/lists`, _mw.Do(_mw.ValidateId).Then(readingList.GetAllByUser()).ServeHTTP),
 		NewRoute(http.MethodGet, `/users/(.+)`, _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.AuthorizedById).Then(user.Get()).ServeHTTP),
 		NewRoute(http.MethodPut, `/users/(.+)`, _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.AuthorizedById, _mw.JSONRequest).Then(user.Update()).ServeHTTP),
 		NewRoute(http.MethodDelete, `/users/(.+)`, _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.AuthorizedById).Then(user.Delete()).ServeHTTP),
 		NewRoute(http.MethodPost, `/books`, _mw.Do(_mw.JSONRequest, _mw.LibrarianOnlyAuthorization).Then(book.Create()).ServeHTTP),
+		NewRoute(http.MethodPost, `/books/isbn`, _mw.Do(_mw.JSONRequest, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(book.CreateFromISBN()).ServeHTTP),
+		NewRoute(http.MethodPost, `/books/import`, _mw.Do(_mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(catalog.Import()).ServeHTTP),
+		NewRoute(http.MethodGet, `/books/(.+)/cover`, _mw.Do(_mw.ValidateId).Then(cover.Get()).ServeHTTP),
+		NewRoute(http.MethodPut, `/books/(.+)/cover`, _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(cover.Upload()).ServeHTTP),
+		NewRoute(http.MethodDelete, `/books/(.+)/cover`, _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(cover.Delete()).ServeHTTP),
+		NewRoute(http.MethodGet, `/books/(.+)/citation`, _mw.Do(_mw.ValidateId).Then(citation.Book()).ServeHTTP),
+		NewRoute(http.MethodGet, `/books/(.+)/subjects`, _mw.Do(_mw.ValidateId).Then(subject.GetBookClassification()).ServeHTTP),
+		NewRoute(http.MethodPut, `/books/(.+)/subjects`, _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(subject.SetBookSubjects()).ServeHTTP),
+		NewRoute(http.MethodPut, `/books/(.+)/call-number`, _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(subject.SetCallNumber()).ServeHTTP),
+		NewRoute(http.MethodGet, `/books/(.+)/translations`, _mw.Do(_mw.ValidateId).Then(book.GetTranslations()).ServeHTTP),
+		NewRoute(http.MethodPut, `/books/([0-9]+)/translations/([A-Za-z0-9_-]+)`, _mw.Do(_mw.JSONRequest, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(book.SaveTranslation()).ServeHTTP),
+		NewRoute(http.MethodDelete, `/books/([0-9]+)/translations/([A-Za-z0-9_-]+)`, _mw.Do(_mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(book.DeleteTranslation()).ServeHTTP),
+		NewRoute(http.MethodPost, `/books/search-index`, _mw.Do(_mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(book.RebuildSearchIndex()).ServeHTTP),
+		NewRoute(http.MethodPost, `/books/(.+)/merge`, _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(book.Merge()).ServeHTTP),
+		NewRoute(http.MethodGet, `/books/export`, _mw.Do(_mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(catalog.Export()).ServeHTTP),
 		NewRoute(http.MethodGet, `/books?.*`, book.GetAll().ServeHTTP),
 		NewRoute(http.MethodGet, `/books/(.+)`, _mw.Do(_mw.ValidateId).Then(book.Get()).ServeHTTP),
 		NewRoute(http.MethodPut, `/books/(.+)`, _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(book.Update()).ServeHTTP),
 		NewRoute(http.MethodDelete, `/books/(.+)`, _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(book.Delete()).ServeHTTP),
+		NewRoute(http.MethodGet, "/authors", author.GetAll().ServeHTTP),
+		NewRoute(http.MethodPost, "/authors/(.+)/merge", _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(author.Merge()).ServeHTTP),
+		NewRoute(http.MethodGet, "/authors/(.+)", _mw.Do(_mw.ValidateId).Then(author.Get()).ServeHTTP),
+		NewRoute(http.MethodPut, "/authors/(.+)", _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(author.Update()).ServeHTTP),
+		NewRoute(http.MethodGet, "/oai", oai.Handle().ServeHTTP),
+		NewRoute(http.MethodPost, "/oai", oai.Handle().ServeHTTP),
+		NewRoute(http.MethodGet, "/lists", readingList.GetAll().ServeHTTP),
+		NewRoute(http.MethodPost, "/lists", _mw.Do(_mw.JSONRequest, _mw.Authentication).Then(readingList.Create()).ServeHTTP),
+		NewRoute(http.MethodPut, "/lists/(.+)/books/(.+)", _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication).Then(readingList.SaveBook()).ServeHTTP),
+		NewRoute(http.MethodDelete, "/lists/(.+)/books/(.+)", _mw.Do(_mw.ValidateId, _mw.Authentication).Then(readingList.RemoveBook()).ServeHTTP),
+		NewRoute(http.MethodPut, "/lists/(.+)/order", _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication).Then(readingList.Reorder()).ServeHTTP),
+		NewRoute(http.MethodPut, "/lists/(.+)/featured", _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(readingList.Feature()).ServeHTTP),
+		NewRoute(http.MethodGet, "/lists/(.+)/citation", _mw.Do(_mw.ValidateId).Then(citation.ReadingList()).ServeHTTP),
+		NewRoute(http.MethodGet, "/lists/(.+)", _mw.Do(_mw.ValidateId).Then(readingList.Get()).ServeHTTP),
+		NewRoute(http.MethodPut, "/lists/(.+)", _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication).Then(readingList.Update()).ServeHTTP),
+		NewRoute(http.MethodDelete, "/lists/(.+)", _mw.Do(_mw.ValidateId, _mw.Authentication).Then(readingList.Delete()).ServeHTTP),
+		NewRoute(http.MethodGet, "/works", work.GetAll().ServeHTTP),
+		NewRoute(http.MethodPost, "/works", _mw.Do(_mw.JSONRequest, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(work.Create()).ServeHTTP),
+		NewRoute(http.MethodGet, "/works/(.+)/reviews", _mw.Do(_mw.ValidateId).Then(work.GetReviews()).ServeHTTP),
+		NewRoute(http.MethodPut, "/works/(.+)/editions/(.+)", _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(work.AddEdition()).ServeHTTP),
+		NewRoute(http.MethodDelete, "/works/(.+)/editions/(.+)", _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(work.RemoveEdition()).ServeHTTP),
+		NewRoute(http.MethodGet, "/works/(.+)", _mw.Do(_mw.ValidateId).Then(work.Get()).ServeHTTP),
+		NewRoute(http.MethodPut, "/works/(.+)", _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(work.Update()).ServeHTTP),
+		NewRoute(http.MethodGet, "/series", work.GetAllSeries().ServeHTTP),
+		NewRoute(http.MethodPost, "/series", _mw.Do(_mw.JSONRequest, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(work.CreateSeries()).ServeHTTP),
+		NewRoute(http.MethodPut, "/series/(.+)/works/(.+)", _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(work.SaveSeriesWork()).ServeHTTP),
+		NewRoute(http.MethodDelete, "/series/(.+)/works/(.+)", _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(work.RemoveSeriesWork()).ServeHTTP),
+		NewRoute(http.MethodGet, "/series/(.+)", _mw.Do(_mw.ValidateId).Then(work.GetSeries()).ServeHTTP),
+		NewRoute(http.MethodPut, "/series/(.+)", _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(work.UpdateSeries()).ServeHTTP),
+		NewRoute(http.MethodGet, "/subjects", subject.GetAll().ServeHTTP),
+		NewRoute(http.MethodPost, "/subjects", _mw.Do(_mw.JSONRequest, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(subject.Create()).ServeHTTP),
+		NewRoute(http.MethodPost, "/subjects/dewey", _mw.Do(_mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(subject.SeedDewey()).ServeHTTP),
+		NewRoute(http.MethodGet, "/subjects/(.+)/books", _mw.Do(_mw.ValidateId).Then(subject.GetBooks()).ServeHTTP),
+		NewRoute(http.MethodGet, "/subjects/(.+)", _mw.Do(_mw.ValidateId).Then(subject.Get()).ServeHTTP),
+		NewRoute(http.MethodPut, "/subjects/(.+)", _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(subject.Update()).ServeHTTP),
+		NewRoute(http.MethodDelete, "/subjects/(.+)", _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(subject.Delete()).ServeHTTP),
 		NewRoute(http.MethodPost, `/favorites/(.+)`, _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.AuthorizedById, _mw.JSONRequest).Then(favorite.AddBook()).ServeHTTP),
 		NewRoute(http.MethodDelete, `/favorites/(.+)`, _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.AuthorizedById, _mw.JSONRequest).Then(favorite.RemoveBook()).ServeHTTP),
+		NewRoute(http.MethodGet, `/favorites/(.+)/citation`, _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.AuthorizedById).Then(citation.Favorites()).ServeHTTP),
 		NewRoute(http.MethodGet, `/favorites/(.+)`, _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.AuthorizedById).Then(favorite.GetAllByUserId()).ServeHTTP),
 		NewRoute(http.MethodGet, `/wishes\?.*$`, _mw.Do(_mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(wish.GetAll()).ServeHTTP),
 		NewRoute(http.MethodPost, `/wishes/(.+)`, _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.AuthorizedById, _mw.JSONRequest).Then(wish.AddBook()).ServeHTTP),
 		NewRoute(http.MethodGet, `/wishes/(.+)`, _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.AuthorizedById, _mw.MemberOnlyAuthorization).Then(wish.GetAllByUser()).ServeHTTP),
 		NewRoute(http.MethodPut, `/wishes/(.+)/(.+)`, _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.AuthorizedById, _mw.MemberOnlyAuthorization).Then(wish.Update()).ServeHTTP),
 		NewRoute(http.MethodDelete, `/wishes/(.+)/(.+)`, _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.AuthorizedById, _mw.MemberOnlyAuthorization).Then(wish.RemoveBook()).ServeHTTP),
+		NewRoute(http.MethodGet, "/acquisitions", _mw.Do(_mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(acquisition.GetAll()).ServeHTTP),
+		NewRoute(http.MethodGet, "/acquisitions/(.+)", _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(acquisition.Get()).ServeHTTP),
+		NewRoute(http.MethodPut, "/acquisitions/(.+)", _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(acquisition.Update()).ServeHTTP),
+		NewRoute(http.MethodGet, "/suggestions", _mw.Do(_mw.Authentication).Then(acquisition.GetSuggestions()).ServeHTTP),
+		NewRoute(http.MethodPut, "/suggestions/(.+)/votes", _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.MemberOnlyAuthorization).Then(acquisition.Vote()).ServeHTTP),
+		NewRoute(http.MethodDelete, "/suggestions/(.+)/votes", _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.MemberOnlyAuthorization).Then(acquisition.RemoveVote()).ServeHTTP),
 		NewRoute(http.MethodGet, `/reviews\?.*$`, _mw.Do(_mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(review.GetAll()).ServeHTTP),
+		NewRoute(http.MethodPost, `/reviews/(.+)/(.+)/replies/(.+)/reports`, _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication, _mw.MemberOnlyAuthorization).Then(review.ReportReply()).ServeHTTP),
+		NewRoute(http.MethodPut, `/reviews/(.+)/(.+)/replies/(.+)`, _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication).Then(review.UpdateReply()).ServeHTTP),
+		NewRoute(http.MethodDelete, `/reviews/(.+)/(.+)/replies/(.+)`, _mw.Do(_mw.ValidateId, _mw.Authentication).Then(review.DeleteReply()).ServeHTTP),
+		NewRoute(http.MethodPost, `/reviews/(.+)/(.+)/replies`, _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication).Then(review.CreateReply()).ServeHTTP),
+		NewRoute(http.MethodPut, `/reviews/(.+)/(.+)/votes`, _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication, _mw.MemberOnlyAuthorization).Then(review.Vote()).ServeHTTP),
+		NewRoute(http.MethodDelete, `/reviews/(.+)/(.+)/votes`, _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.MemberOnlyAuthorization).Then(review.RemoveVote()).ServeHTTP),
+		NewRoute(http.MethodPost, `/reviews/(.+)/(.+)/reports`, _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication, _mw.MemberOnlyAuthorization).Then(review.Report()).ServeHTTP),
 		NewRoute(http.MethodPost, `/reviews/(.+)`, _mw.Do(_mw.JSONRequest, _mw.ValidateId, _mw.Authentication).Then(review.Create()).ServeHTTP),
 		NewRoute(http.MethodGet, `/reviews/(.+)`, _mw.Do(_mw.ValidateId).Then(review.GetAllByBook()).ServeHTTP),
 		NewRoute(http.MethodGet, `/reviews/(.+)/(.+)`, _mw.Do(_mw.ValidateId).Then(review.Get()).ServeHTTP),
 		NewRoute(http.MethodPut, `/reviews/(.+)/(.+)`, _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication).Then(review.Update()).ServeHTTP),
 		NewRoute(http.MethodDelete, `/reviews/(.+)/(.+)`, _mw.Do(_mw.ValidateId, _mw.Authentication).Then(review.Delete()).ServeHTTP),
+		NewRoute(http.MethodGet, "/moderation/reviews", _mw.Do(_mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(review.GetModerationQueue()).ServeHTTP),
+		NewRoute(http.MethodPost, "/moderation/reviews/(.+)", _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(review.Moderate()).ServeHTTP),
+		NewRoute(http.MethodGet, "/moderation/replies", _mw.Do(_mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(review.GetReplyModerationQueue()).ServeHTTP),
+		NewRoute(http.MethodPost, "/moderation/replies/(.+)", _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(review.ModerateReply()).ServeHTTP),
 		NewRoute(http.MethodGet, "/requests", _mw.Do(_mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(request.GetAll()).ServeHTTP),
+		NewRoute(http.MethodGet, "/requests/events", _mw.Do(_mw.Authentication).Then(event.Stream()).ServeHTTP),
 		NewRoute(http.MethodGet, "/requests/(.+)", _mw.Do(_mw.ValidateId, _mw.Authentication).Then(request.GetAllByUser()).ServeHTTP),
 		NewRoute(http.MethodPost, "/requests/(.+)", _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication, _mw.AuthorizedById).Then(request.Create()).ServeHTTP),
 		NewRoute(http.MethodGet, "/requests/(.+)/(.+)", _mw.Do(_mw.ValidateId, _mw.Authentication).Then(request.Get()).ServeHTTP),
 		NewRoute(http.MethodPut, "/requests/(.+)/(.+)", _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication).Then(request.Update()).ServeHTTP),
+		NewRoute(http.MethodPost, "/webhooks", _mw.Do(_mw.JSONRequest, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(webhook.Create()).ServeHTTP),
+		NewRoute(http.MethodGet, "/webhooks", _mw.Do(_mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(webhook.GetAll()).ServeHTTP),
+		NewRoute(http.MethodGet, "/webhooks/(.+)/deliveries", _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(webhook.GetAllDeliveries()).ServeHTTP),
+		NewRoute(http.MethodPost, "/webhooks/(.+)/deliveries/(.+)", _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(webhook.Replay()).ServeHTTP),
+		NewRoute(http.MethodGet, "/webhooks/(.+)", _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(webhook.Get()).ServeHTTP),
+		NewRoute(http.MethodPut, "/webhooks/(.+)", _mw.Do(_mw.ValidateId, _mw.JSONRequest, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(webhook.Update()).ServeHTTP),
+		NewRoute(http.MethodDelete, "/webhooks/(.+)", _mw.Do(_mw.ValidateId, _mw.Authentication, _mw.LibrarianOnlyAuthorization).Then(webhook.Delete()).ServeHTTP),
 	}
 
 	return func(rw http.ResponseWriter, r *http.Request) {
@@ -6,23 +6,62 @@ import (
 	"net/http"
 	"os"
 	_config "plain-go/public-library/app/config"
+	_event "plain-go/public-library/app/event"
 	_router "plain-go/public-library/app/router"
 	_util "plain-go/public-library/app/util"
+	_acquisitionController "plain-go/public-library/controller/acquisition"
+	_authorController "plain-go/public-library/controller/author"
 	_bookController "plain-go/public-library/controller/book"
+	_catalogController "plain-go/public-library/controller/catalog"
+	_citationController "plain-go/public-library/controller/citation"
+	_coverController "plain-go/public-library/controller/cover"
+	_eventController "plain-go/public-library/controller/event"
 	_favoriteController "plain-go/public-library/controller/favorite"
+	_notificationController "plain-go/public-library/controller/notification"
+	_oaiController "plain-go/public-library/controller/oai"
+	_readingListController "plain-go/public-library/controller/readinglist"
 	_requestController "plain-go/public-library/controller/request"
 	_reviewController "plain-go/public-library/controller/review"
+	_subjectController "plain-go/public-library/controller/subject"
 	_userController "plain-go/public-library/controller/user"
+	_webhookController "plain-go/public-library/controller/webhook"
 	_wishController "plain-go/public-library/controller/wish"
+	_workController "plain-go/public-library/controller/work"
+	_acquisitionRepository "plain-go/public-library/datastore/acquisition"
+	_authorRepository "plain-go/public-library/datastore/author"
+	_blobRepository "plain-go/public-library/datastore/blob"
 	_bookRepository "plain-go/public-library/datastore/book"
+	_moderationRepository "plain-go/public-library/datastore/moderation"
+	_notificationRepository "plain-go/public-library/datastore/notification"
+	_openLibraryRepository "plain-go/public-library/datastore/openlibrary"
+	_outboxRepository "plain-go/public-library/datastore/outbox"
+	_readingListRepository "plain-go/public-library/datastore/readinglist"
 	_requestRepository "plain-go/public-library/datastore/request"
+	_subjectRepository "plain-go/public-library/datastore/subject"
 	_userRepository "plain-go/public-library/datastore/user"
+	_webhookRepository "plain-go/public-library/datastore/webhook"
+	_workRepository "plain-go/public-library/datastore/work"
+	_acquisitionUseCase "plain-go/public-library/usecase/acquisition"
+	_authorUseCase "plain-go/public-library/usecase/author"
 	_bookUseCase "plain-go/public-library/usecase/book"
+	_catalogUseCase "plain-go/public-library/usecase/catalog"
+	_citationUseCase "plain-go/public-library/usecase/citation"
+	_coverUseCase "plain-go/public-library/usecase/cover"
+	_duplicate "plain-go/public-library/usecase/duplicate"
+	_enrichment "plain-go/public-library/usecase/enrichment"
 	_favoriteUseCase "plain-go/public-library/usecase/favorite"
+	_notificationUseCase "plain-go/public-library/usecase/notification"
+	_oaiUseCase "plain-go/public-library/usecase/oai"
+	_readingListUseCase "plain-go/public-library/usecase/readinglist"
 	_requestUseCase "plain-go/public-library/usecase/request"
 	_reviewUseCase "plain-go/public-library/usecase/review"
+	_searchUseCase "plain-go/public-library/usecase/search"
+	_subjectUseCase "plain-go/public-library/usecase/subject"
 	_userUseCase "plain-go/public-library/usecase/user"
+	_webhookUseCase "plain-go/public-library/usecase/webhook"
 	_wishUseCase "plain-go/public-library/usecase/wish"
+	_workUseCase "plain-go/public-library/usecase/work"
+	"time"
 )
 
 func init() {
@@ -45,27 +84,157 @@ func main() {
 		panic("error in database connection")
 	}
 
+	// in-process event bus
+	bus := _event.NewBus()
+	eventController := _eventController.New(bus)
+
 	userRepository := _userRepository.New(db)
 	userUseCase := _userUseCase.New(userRepository)
 	userController := _userController.New(userUseCase)
 
 	bookRepository := _bookRepository.New(db)
-	bookUseCase := _bookUseCase.New(bookRepository)
+	requestRepository := _requestRepository.New(db)
+	acquisitionRepository := _acquisitionRepository.New(db)
+
+	// notification channels, email and sms are only enabled when configured
+	notificationRepository := _notificationRepository.New(db)
+	channels := []_notificationUseCase.Channel{_notificationUseCase.NewInboxChannel(notificationRepository)}
+
+	if config.SMTP.Host != "" {
+		channels = append(channels, _notificationUseCase.NewEmailChannel(config.SMTP.Host, config.SMTP.Port, config.SMTP.Username, config.SMTP.Password, config.SMTP.Sender))
+	}
+
+	if config.SMSGateway.URL != "" {
+		channels = append(channels, _notificationUseCase.NewSMSChannel(_notificationUseCase.NewHTTPSMSGateway(config.SMSGateway.URL, config.SMSGateway.APIKey)))
+	}
+
+	notificationUseCase := _notificationUseCase.New(notificationRepository, userRepository, bookRepository, requestRepository, acquisitionRepository, channels...)
+	notificationController := _notificationController.New(notificationUseCase)
+
+	// near-duplicate books, authors and suggestions are detected on creation
+	detector := _duplicate.New(bookRepository, acquisitionRepository)
+
+	if err := detector.BackfillKeys(); err != nil {
+		log.Println("failed to backfill match keys:", err)
+	}
+
+	// book metadata is looked up by ISBN in Open Library dump indexed by olimport, nothing is found before
+	openLibraryRepository := _openLibraryRepository.New(db)
+	provider := _enrichment.NewOpenLibraryProvider(openLibraryRepository)
+
+	bookUseCase := _bookUseCase.New(bookRepository, acquisitionRepository, detector, provider)
 	bookController := _bookController.New(bookUseCase)
 
+	// cover images are kept on local filesystem
+	blobRepository := _blobRepository.NewLocal(config.Cover.StorageDir)
+	coverUseCase := _coverUseCase.New(bookRepository, blobRepository, config.Cover.MaxSize)
+	coverController := _coverController.New(coverUseCase)
+
+	// bulk import of MARC records goes through book creation
+	catalogUseCase := _catalogUseCase.New(bookRepository, bookUseCase)
+	catalogController := _catalogController.New(catalogUseCase)
+
+	// metadata harvesting over OAI-PMH
+	oaiUseCase := _oaiUseCase.New(bookRepository, _oaiUseCase.Repository{
+		Name:       config.OAI.RepositoryName,
+		Identifier: config.OAI.Identifier,
+		BaseURL:    config.OAI.BaseURL,
+		AdminEmail: config.OAI.AdminEmail,
+	})
+	oaiController := _oaiController.New(oaiUseCase)
+
+	authorRepository := _authorRepository.New(db)
+	authorUseCase := _authorUseCase.New(authorRepository, bookRepository)
+	authorController := _authorController.New(authorUseCase)
+
 	favoriteUseCase := _favoriteUseCase.New(bookRepository, userRepository)
 	favoriteController := _favoriteController.New(favoriteUseCase)
 
-	wishUseCase := _wishUseCase.New(bookRepository, userRepository)
+	readingListRepository := _readingListRepository.New(db)
+	readingListUseCase := _readingListUseCase.New(readingListRepository, bookRepository, userRepository)
+	readingListController := _readingListController.New(readingListUseCase)
+
+	// citations of a single book, a reading list or favorites
+	citationUseCase := _citationUseCase.New(bookRepository, readingListUseCase, favoriteUseCase)
+	citationController := _citationController.New(citationUseCase)
+
+	wishUseCase := _wishUseCase.New(bookRepository, userRepository, acquisitionRepository, detector)
 	wishController := _wishController.New(wishUseCase)
 
-	reviewUseCase := _reviewUseCase.New(bookRepository, userRepository)
+	// wishes older than acquisition candidates are grouped once
+	go wishUseCase.GroupWishes()
+
+	acquisitionUseCase := _acquisitionUseCase.New(acquisitionRepository, bookRepository)
+	acquisitionController := _acquisitionController.New(acquisitionUseCase)
+
+	moderationRepository := _moderationRepository.New(db)
+	// review screening, blocked words from file extend the default list
+	wordList := _reviewUseCase.DefaultWordList
+
+	if config.ReviewScreening.WordListFile != "" {
+		words, err := _reviewUseCase.LoadWordList(config.ReviewScreening.WordListFile)
+
+		if err != nil {
+			panic("error in review word list")
+		}
+
+		wordList = append(wordList, words...)
+	}
+
+	screener := _reviewUseCase.NewPipeline(
+		_reviewUseCase.NewLengthScreener(config.ReviewScreening.MinLength, config.ReviewScreening.MaxLength),
+		_reviewUseCase.NewWordListScreener(wordList...),
+		_reviewUseCase.NewLinkScreener(config.ReviewScreening.MaxLinks),
+		_reviewUseCase.NewSpamScreener(),
+	)
+
+	reviewUseCase := _reviewUseCase.New(bookRepository, userRepository, requestRepository, moderationRepository, screener)
 	reviewController := _reviewController.New(reviewUseCase)
 
-	requestRepository := _requestRepository.New(db)
-	requestUseCase := _requestUseCase.New(bookRepository, userRepository, requestRepository)
+	// classification tree of dewey classes and local subject headings
+	subjectRepository := _subjectRepository.New(db)
+	subjectUseCase := _subjectUseCase.New(subjectRepository, bookRepository)
+	subjectController := _subjectController.New(subjectUseCase)
+
+	// works group editions of a title, holds may be placed on any edition
+	workRepository := _workRepository.New(db)
+	workUseCase := _workUseCase.New(workRepository, bookRepository, userRepository)
+	workController := _workController.New(workUseCase)
+
+	requestUseCase := _requestUseCase.New(bookRepository, userRepository, requestRepository, workRepository, bus)
 	requestController := _requestController.New(requestUseCase)
 
+	// deliver subscribed events to registered webhooks
+	webhookRepository := _webhookRepository.New(db)
+	webhookUseCase := _webhookUseCase.New(webhookRepository)
+	webhookController := _webhookController.New(webhookUseCase)
+
+	// keep book search index in step with books, translations and authors
+	searchUseCase := _searchUseCase.New(bookRepository, authorRepository)
+
+	if err := searchUseCase.BackfillIndex(); err != nil {
+		log.Println("failed to backfill search index:", err)
+	}
+
+	// deliver events recorded in the outbox to in-process subscribers
+	outboxRepository := _outboxRepository.New(db)
+	dispatcher := _event.NewDispatcher(outboxRepository)
+	dispatcher.Subscribe("notification", notificationUseCase.HandleEvent)
+	dispatcher.Subscribe("audit", outboxRepository.CreateAuditLog)
+	dispatcher.Subscribe("webhook", webhookUseCase.HandleEvent)
+	dispatcher.Subscribe("search", searchUseCase.HandleEvent)
+	go dispatcher.Run(time.Second)
+
+	// send webhook deliveries queued by the dispatcher
+	go webhookUseCase.Run(time.Second)
+
+	// send due date and overdue reminders periodically
+	go func() {
+		for now := range time.Tick(time.Hour) {
+			notificationUseCase.SendDueReminders(now)
+		}
+	}()
+
 	// register handlers
 	router := http.HandlerFunc(
 		_router.Router(
@@ -75,6 +244,18 @@ func main() {
 			wishController,
 			reviewController,
 			requestController,
+			notificationController,
+			eventController,
+			webhookController,
+			acquisitionController,
+			authorController,
+			readingListController,
+			catalogController,
+			oaiController,
+			coverController,
+			workController,
+			subjectController,
+			citationController,
 		),
 	)
 
@@ -0,0 +1,133 @@
+package acquisition
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	_mw "plain-go/public-library/app/middleware"
+	_helper "plain-go/public-library/helper"
+	_model "plain-go/public-library/model"
+	_acquisitionUseCase "plain-go/public-library/usecase/acquisition"
+	"strconv"
+	"strings"
+)
+
+type AcquisitionController struct {
+	usecase _acquisitionUseCase.Acquisition
+}
+
+func New(acquisition _acquisitionUseCase.Acquisition) *AcquisitionController {
+	return &AcquisitionController{usecase: acquisition}
+}
+
+func (ac AcquisitionController) GetAll() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		res, code, message := ac.usecase.GetAllCandidates(r.URL.Query())
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (ac AcquisitionController) Get() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		candidateId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		res, code, message := ac.usecase.GetCandidateById(uint(candidateId))
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (ac AcquisitionController) Update() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		candidateId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "Bearer ")
+		librarianId, _, _ := _helper.ExtractToken(token)
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "failed to read request body", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.UpdateAcquisitionCandidateRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := ac.usecase.UpdateCandidate(uint(librarianId), uint(candidateId), req)
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (ac AcquisitionController) GetSuggestions() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "Bearer ")
+		userId, _, _ := _helper.ExtractToken(token)
+
+		res, code, message := ac.usecase.GetSuggestions(uint(userId), r.URL.Query())
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (ac AcquisitionController) Vote() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "Bearer ")
+		userId, _, _ := _helper.ExtractToken(token)
+
+		candidateId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		res, code, message := ac.usecase.VoteSuggestion(uint(userId), uint(candidateId))
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (ac AcquisitionController) RemoveVote() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "Bearer ")
+		userId, _, _ := _helper.ExtractToken(token)
+
+		candidateId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		code, message := ac.usecase.RemoveSuggestionVote(uint(userId), uint(candidateId))
+
+		_model.CreateResponse(rw, code, message, nil)
+	}
+}
@@ -0,0 +1,119 @@
+package author
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	_mw "plain-go/public-library/app/middleware"
+	_helper "plain-go/public-library/helper"
+	_model "plain-go/public-library/model"
+	_authorUseCase "plain-go/public-library/usecase/author"
+	"strconv"
+	"strings"
+)
+
+type AuthorController struct {
+	usecase _authorUseCase.Author
+}
+
+func New(author _authorUseCase.Author) *AuthorController {
+	return &AuthorController{usecase: author}
+}
+
+func (ac AuthorController) GetAll() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		res, code, message := ac.usecase.GetAllAuthors(r.URL.Query())
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (ac AuthorController) Get() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		authorId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		res, code, message := ac.usecase.GetAuthorById(uint(authorId))
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (ac AuthorController) Update() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		authorId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "failed to read request body", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.UpdateAuthorRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := ac.usecase.UpdateAuthor(req, uint(authorId))
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (ac AuthorController) Merge() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		authorId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "Bearer ")
+		librarianId, _, _ := _helper.ExtractToken(token)
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "failed to read request body", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.MergeAuthorRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := ac.usecase.MergeAuthor(uint(librarianId), uint(authorId), req)
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"log"
 	"net/http"
 	_mw "plain-go/public-library/app/middleware"
+	_helper "plain-go/public-library/helper"
 	_model "plain-go/public-library/model"
 	_bookUseCase "plain-go/public-library/usecase/book"
 	"strconv"
+	"strings"
 )
 
 type BookController struct {
@@ -50,17 +52,52 @@ func (bc BookController) Create() http.HandlerFunc {
 	}
 }
 
+func (bc BookController) CreateFromISBN() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "internal server error", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.CreateBookRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := bc.usecase.CreateBookFromISBN(req)
+
+		if code != http.StatusCreated {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
 func (bc BookController) GetAll() http.HandlerFunc {
 	return func(rw http.ResponseWriter, r *http.Request) {
 		query := r.URL.Query()
+		languages := _helper.ParseAcceptLanguage(r.Header.Get("accept-language"))
 
-		res, code, message := bc.usecase.GetAllBooks(query)
+		res, code, message := bc.usecase.GetAllBooks(query, languages)
 
 		if code != http.StatusOK {
 			_model.CreateResponse(rw, code, message, nil)
 			return
 		}
 
+		// books may be served in different languages, each tells its own locale
+		rw.Header().Set("Vary", "Accept-Language")
+
 		_model.CreateResponse(rw, code, message, res)
 	}
 }
@@ -69,13 +106,21 @@ func (bc BookController) Get() http.HandlerFunc {
 	return func(rw http.ResponseWriter, r *http.Request) {
 		bookId, _ := strconv.Atoi(_mw.GetParam(r)[0])
 
-		existing, code, message := bc.usecase.GetBookById(uint(bookId))
+		languages := _helper.ParseAcceptLanguage(r.Header.Get("accept-language"))
+
+		existing, code, message := bc.usecase.GetBookById(uint(bookId), languages)
 
 		if code != http.StatusOK {
 			_model.CreateResponse(rw, code, message, nil)
 			return
 		}
 
+		rw.Header().Set("Vary", "Accept-Language")
+
+		if existing.Book.Locale != "" {
+			rw.Header().Set("Content-Language", existing.Book.Locale)
+		}
+
 		_model.CreateResponse(rw, code, message, existing.Book)
 	}
 }
@@ -122,3 +167,107 @@ func (bc BookController) Delete() http.HandlerFunc {
 		_model.CreateResponse(rw, code, message, nil)
 	}
 }
+
+func (bc BookController) Merge() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		bookId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "Bearer ")
+		librarianId, _, _ := _helper.ExtractToken(token)
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "failed to read request body", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.MergeBookRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := bc.usecase.MergeBook(uint(librarianId), uint(bookId), req)
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (bc BookController) GetTranslations() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		bookId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		res, code, message := bc.usecase.GetTranslations(uint(bookId))
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (bc BookController) SaveTranslation() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		params := _mw.GetParam(r)
+		bookId, _ := strconv.Atoi(params[0])
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "failed to read request body", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.SaveBookTranslationRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := bc.usecase.SaveTranslation(uint(bookId), params[1], req)
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (bc BookController) DeleteTranslation() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		params := _mw.GetParam(r)
+		bookId, _ := strconv.Atoi(params[0])
+
+		code, message := bc.usecase.DeleteTranslation(uint(bookId), params[1])
+
+		_model.CreateResponse(rw, code, message, nil)
+	}
+}
+
+func (bc BookController) RebuildSearchIndex() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		code, message := bc.usecase.RebuildSearchIndex()
+
+		_model.CreateResponse(rw, code, message, nil)
+	}
+}
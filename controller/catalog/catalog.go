@@ -0,0 +1,52 @@
+package catalog
+
+import (
+	"log"
+	"net/http"
+	_model "plain-go/public-library/model"
+	_catalogUseCase "plain-go/public-library/usecase/catalog"
+)
+
+type CatalogController struct {
+	usecase _catalogUseCase.Catalog
+}
+
+func New(catalog _catalogUseCase.Catalog) *CatalogController {
+	return &CatalogController{usecase: catalog}
+}
+
+func (cc CatalogController) Import() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+
+		// records are read from the body as they come, the whole file is never held in memory
+		res, code, message := cc.usecase.ImportBooks(r.URL.Query(), r.Body)
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (cc CatalogController) Export() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		export, contentType, code, message := cc.usecase.ExportBooks(r.URL.Query())
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		rw.Header().Set("Content-Type", contentType)
+		rw.Header().Set("Content-Disposition", "attachment")
+		rw.WriteHeader(code)
+
+		// rows are written as they are read, failure after this point can only be logged
+		if err := export(rw); err != nil {
+			log.Println(err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package citation
+
+import (
+	"net/http"
+	_mw "plain-go/public-library/app/middleware"
+	_helper "plain-go/public-library/helper"
+	_model "plain-go/public-library/model"
+	_citationUseCase "plain-go/public-library/usecase/citation"
+	"strconv"
+	"strings"
+)
+
+type CitationController struct {
+	usecase _citationUseCase.Citation
+}
+
+func New(citation _citationUseCase.Citation) *CitationController {
+	return &CitationController{usecase: citation}
+}
+
+func write(rw http.ResponseWriter, res []byte, contentType string, code int, message string) {
+	if code != http.StatusOK {
+		_model.CreateResponse(rw, code, message, nil)
+		return
+	}
+
+	rw.Header().Set("Content-Type", contentType)
+	rw.WriteHeader(code)
+	rw.Write(res)
+}
+
+func (cc CitationController) Book() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		bookId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		res, contentType, code, message := cc.usecase.CiteBook(uint(bookId), r.URL.Query())
+
+		write(rw, res, contentType, code, message)
+	}
+}
+
+func (cc CitationController) ReadingList() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		// viewer is optional, link only list is opened with its share token
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "Bearer ")
+		viewerId, _, _ := _helper.ExtractToken(token)
+
+		listId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		res, contentType, code, message := cc.usecase.CiteReadingList(uint(viewerId), uint(listId), r.URL.Query())
+
+		write(rw, res, contentType, code, message)
+	}
+}
+
+func (cc CitationController) Favorites() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		userId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		res, contentType, code, message := cc.usecase.CiteFavorites(uint(userId), r.URL.Query())
+
+		write(rw, res, contentType, code, message)
+	}
+}
@@ -0,0 +1,112 @@
+package cover
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"mime"
+	"net/http"
+	_mw "plain-go/public-library/app/middleware"
+	_model "plain-go/public-library/model"
+	_coverUseCase "plain-go/public-library/usecase/cover"
+	"strconv"
+	"time"
+)
+
+type CoverController struct {
+	usecase _coverUseCase.Cover
+}
+
+func New(cover _coverUseCase.Cover) *CoverController {
+	return &CoverController{usecase: cover}
+}
+
+// hard limit of request body, size limit of cover itself is checked by use case
+const maxUploadSize = 32 << 20
+
+func (cc CoverController) Upload() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		bookId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+		r.Body = http.MaxBytesReader(rw, r.Body, maxUploadSize)
+		defer r.Body.Close()
+
+		var data []byte
+
+		// cover is either sent as form file named cover or as raw request body
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("content-type")); mediaType == "multipart/form-data" {
+			file, _, err := r.FormFile("cover")
+
+			if err != nil {
+				log.Println(err)
+				_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+				return
+			}
+
+			defer file.Close()
+
+			if data, err = ioutil.ReadAll(file); err != nil {
+				log.Println(err)
+				_model.CreateResponse(rw, http.StatusInternalServerError, "failed to read request body", nil)
+				return
+			}
+		} else {
+			var err error
+
+			if data, err = ioutil.ReadAll(r.Body); err != nil {
+				log.Println(err)
+				_model.CreateResponse(rw, http.StatusRequestEntityTooLarge, "file too large", nil)
+				return
+			}
+		}
+
+		res, code, message := cc.usecase.UploadCover(uint(bookId), data)
+
+		if code != http.StatusOK && code != http.StatusCreated {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (cc CoverController) Get() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		bookId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+		size := r.URL.Query().Get("size")
+
+		data, cover, code, message := cc.usecase.GetCover(uint(bookId), size)
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		if size == "" {
+			size = "original"
+		}
+
+		// versioned URL never changes content, plain URL is revalidated daily
+		rw.Header().Set("Content-Type", cover.ContentType)
+		rw.Header().Set("ETag", `"`+cover.ETag+"-"+size+`"`)
+
+		if r.URL.Query().Get("v") == cover.ETag {
+			rw.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		} else {
+			rw.Header().Set("Cache-Control", "public, max-age=86400")
+		}
+
+		// conditional and range request are answered by ServeContent
+		http.ServeContent(rw, r, "", cover.UpdatedAt.Truncate(time.Second), bytes.NewReader(data))
+	}
+}
+
+func (cc CoverController) Delete() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		bookId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		code, message := cc.usecase.DeleteCover(uint(bookId))
+
+		_model.CreateResponse(rw, code, message, nil)
+	}
+}
@@ -0,0 +1,84 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	_event "plain-go/public-library/app/event"
+	_helper "plain-go/public-library/helper"
+	_model "plain-go/public-library/model"
+)
+
+type EventController struct {
+	bus *_event.Bus
+}
+
+func New(bus *_event.Bus) *EventController {
+	return &EventController{bus: bus}
+}
+
+func (ec EventController) Stream() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "Bearer ")
+		userId, role, _ := _helper.ExtractToken(token)
+
+		flusher, ok := rw.(http.Flusher)
+
+		if !ok {
+			log.Println("streaming unsupported")
+			_model.CreateResponse(rw, http.StatusInternalServerError, "streaming unsupported", nil)
+			return
+		}
+
+		events, unsubscribe := ec.bus.Subscribe(16)
+		defer unsubscribe()
+
+		rw.Header().Set("Content-Type", "text/event-stream")
+		rw.Header().Set("Cache-Control", "no-cache")
+		rw.Header().Set("Connection", "keep-alive")
+		rw.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		// keep connection alive through proxies
+		heartbeat := time.NewTicker(30 * time.Second)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-heartbeat.C:
+				fmt.Fprint(rw, ": ping\n\n")
+				flusher.Flush()
+			case event, open := <-events:
+				if !open {
+					return
+				}
+
+				// only request lifecycle events are streamed
+				if !strings.HasPrefix(event.Type, "request.") {
+					continue
+				}
+
+				// member only receives their own events, librarian receives all
+				if role != "Librarian" && event.UserId != uint(userId) {
+					continue
+				}
+
+				data, err := json.Marshal(event)
+
+				if err != nil {
+					log.Println(err)
+					continue
+				}
+
+				fmt.Fprintf(rw, "id: %s\nevent: %s\ndata: %s\n\n", event.Id, event.Type, data)
+				flusher.Flush()
+			}
+		}
+	}
+}
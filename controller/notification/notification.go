@@ -0,0 +1,140 @@
+package notification
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	_mw "plain-go/public-library/app/middleware"
+	_model "plain-go/public-library/model"
+	_notificationUseCase "plain-go/public-library/usecase/notification"
+	"strconv"
+)
+
+type NotificationController struct {
+	usecase _notificationUseCase.Notification
+}
+
+func New(notification _notificationUseCase.Notification) *NotificationController {
+	return &NotificationController{usecase: notification}
+}
+
+func (nc NotificationController) GetPreference() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		userId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		res, code, message := nc.usecase.GetPreference(uint(userId))
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (nc NotificationController) UpdatePreference() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		userId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "failed to read request body", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.UpdateNotificationPreferenceRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := nc.usecase.UpdatePreference(uint(userId), req)
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (nc NotificationController) GetAll() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		userId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+		query := r.URL.Query()
+
+		res, code, message := nc.usecase.GetAllNotifications(uint(userId), query)
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (nc NotificationController) MarkAsRead() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		userId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "failed to read request body", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.UpdateNotificationsRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		code, message := nc.usecase.MarkNotificationsAsRead(uint(userId), req)
+
+		_model.CreateResponse(rw, code, message, nil)
+	}
+}
+
+func (nc NotificationController) Dismiss() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		userId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "failed to read request body", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.UpdateNotificationsRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		code, message := nc.usecase.DismissNotifications(uint(userId), req)
+
+		_model.CreateResponse(rw, code, message, nil)
+	}
+}
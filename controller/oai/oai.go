@@ -0,0 +1,39 @@
+package oai
+
+import (
+	"log"
+	"net/http"
+	_model "plain-go/public-library/model"
+	_oaiUseCase "plain-go/public-library/usecase/oai"
+)
+
+type OAIController struct {
+	usecase _oaiUseCase.OAI
+}
+
+func New(oai _oaiUseCase.OAI) *OAIController {
+	return &OAIController{usecase: oai}
+}
+
+// Handle serves both GET and form encoded POST as required by OAI-PMH
+func (oc OAIController) Handle() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := oc.usecase.Handle(r.Form)
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		// protocol errors are part of the XML document, so status is always OK
+		rw.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		rw.WriteHeader(code)
+		rw.Write(res)
+	}
+}
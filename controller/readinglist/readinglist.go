@@ -0,0 +1,276 @@
+package readinglist
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	_mw "plain-go/public-library/app/middleware"
+	_helper "plain-go/public-library/helper"
+	_model "plain-go/public-library/model"
+	_readingListUseCase "plain-go/public-library/usecase/readinglist"
+	"strconv"
+	"strings"
+)
+
+type ReadingListController struct {
+	usecase _readingListUseCase.ReadingList
+}
+
+func New(list _readingListUseCase.ReadingList) *ReadingListController {
+	return &ReadingListController{usecase: list}
+}
+
+func (rlc ReadingListController) Create() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "Bearer ")
+		userId, _, _ := _helper.ExtractToken(token)
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "failed to read request body", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.CreateReadingListRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := rlc.usecase.CreateList(uint(userId), req)
+
+		if code != http.StatusCreated {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (rlc ReadingListController) GetAll() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		res, code, message := rlc.usecase.GetAllPublicLists(r.URL.Query())
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (rlc ReadingListController) GetAllByUser() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		// viewer is optional, anonymous patron only sees public lists
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "Bearer ")
+		viewerId, _, _ := _helper.ExtractToken(token)
+
+		userId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		res, code, message := rlc.usecase.GetListsByUserId(uint(viewerId), uint(userId))
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (rlc ReadingListController) Get() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		// viewer is optional, link only list is opened with its share token
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "Bearer ")
+		viewerId, _, _ := _helper.ExtractToken(token)
+
+		listId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		res, code, message := rlc.usecase.GetListById(uint(viewerId), uint(listId), r.URL.Query().Get("token"))
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (rlc ReadingListController) Update() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "Bearer ")
+		userId, _, _ := _helper.ExtractToken(token)
+
+		listId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "failed to read request body", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.UpdateReadingListRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := rlc.usecase.UpdateList(uint(userId), uint(listId), req)
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (rlc ReadingListController) Delete() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "Bearer ")
+		userId, _, _ := _helper.ExtractToken(token)
+
+		listId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		code, message := rlc.usecase.DeleteList(uint(userId), uint(listId))
+
+		_model.CreateResponse(rw, code, message, nil)
+	}
+}
+
+func (rlc ReadingListController) SaveBook() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "Bearer ")
+		userId, _, _ := _helper.ExtractToken(token)
+
+		listId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+		bookId, _ := strconv.Atoi(_mw.GetParam(r)[1])
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "failed to read request body", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.SaveReadingListItemRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := rlc.usecase.SaveItem(uint(userId), uint(listId), uint(bookId), req)
+
+		if code != http.StatusOK && code != http.StatusCreated {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (rlc ReadingListController) RemoveBook() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "Bearer ")
+		userId, _, _ := _helper.ExtractToken(token)
+
+		listId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+		bookId, _ := strconv.Atoi(_mw.GetParam(r)[1])
+
+		code, message := rlc.usecase.RemoveItem(uint(userId), uint(listId), uint(bookId))
+
+		_model.CreateResponse(rw, code, message, nil)
+	}
+}
+
+func (rlc ReadingListController) Reorder() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "Bearer ")
+		userId, _, _ := _helper.ExtractToken(token)
+
+		listId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "failed to read request body", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.ReorderReadingListRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := rlc.usecase.ReorderItems(uint(userId), uint(listId), req)
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (rlc ReadingListController) Feature() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		listId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "failed to read request body", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.FeatureReadingListRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := rlc.usecase.FeatureList(uint(listId), req)
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
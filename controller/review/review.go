@@ -74,7 +74,7 @@ func (rc ReviewController) GetAllByBook() http.HandlerFunc {
 	return func(rw http.ResponseWriter, r *http.Request) {
 		bookId, _ := strconv.Atoi(_mw.GetParam(r)[0])
 
-		res, code, message := rc.usecase.GetAllReviewsByBookId(uint(bookId))
+		res, code, message := rc.usecase.GetAllReviewsByBookId(uint(bookId), r.URL.Query())
 
 		if code != http.StatusOK {
 			_model.CreateResponse(rw, code, message, nil)
@@ -157,3 +157,317 @@ func (rc ReviewController) Delete() http.HandlerFunc {
 		_model.CreateResponse(rw, code, message, nil)
 	}
 }
+
+func (rc ReviewController) Report() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "Bearer ")
+		userId, _, _ := _helper.ExtractToken(token)
+
+		bookId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+		reviewId, _ := strconv.Atoi(_mw.GetParam(r)[1])
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "internal server error", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.ReportReviewRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := rc.usecase.ReportReview(uint(userId), uint(bookId), uint(reviewId), req)
+
+		if code != http.StatusCreated {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (rc ReviewController) GetModerationQueue() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		res, code, message := rc.usecase.GetModerationQueue()
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (rc ReviewController) Moderate() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "Bearer ")
+		librarianId, _, _ := _helper.ExtractToken(token)
+
+		reviewId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "internal server error", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.ModerateReviewRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := rc.usecase.ModerateReview(uint(librarianId), uint(reviewId), req)
+
+		if code != http.StatusCreated {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (rc ReviewController) Vote() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "Bearer ")
+		userId, _, _ := _helper.ExtractToken(token)
+
+		bookId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+		reviewId, _ := strconv.Atoi(_mw.GetParam(r)[1])
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "internal server error", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.VoteReviewRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := rc.usecase.VoteReview(uint(userId), uint(bookId), uint(reviewId), req)
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (rc ReviewController) RemoveVote() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "Bearer ")
+		userId, _, _ := _helper.ExtractToken(token)
+
+		bookId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+		reviewId, _ := strconv.Atoi(_mw.GetParam(r)[1])
+
+		code, message := rc.usecase.RemoveVote(uint(userId), uint(bookId), uint(reviewId))
+
+		_model.CreateResponse(rw, code, message, nil)
+	}
+}
+
+func (rc ReviewController) CreateReply() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "Bearer ")
+		userId, _, _ := _helper.ExtractToken(token)
+
+		bookId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+		reviewId, _ := strconv.Atoi(_mw.GetParam(r)[1])
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "internal server error", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.CreateReplyRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := rc.usecase.CreateReply(uint(userId), uint(bookId), uint(reviewId), req)
+
+		if code != http.StatusCreated {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (rc ReviewController) UpdateReply() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "Bearer ")
+		userId, _, _ := _helper.ExtractToken(token)
+
+		bookId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+		reviewId, _ := strconv.Atoi(_mw.GetParam(r)[1])
+		replyId, _ := strconv.Atoi(_mw.GetParam(r)[2])
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "internal server error", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.UpdateReplyRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := rc.usecase.UpdateReply(uint(userId), uint(bookId), uint(reviewId), uint(replyId), req)
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (rc ReviewController) DeleteReply() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "Bearer ")
+		userId, _, _ := _helper.ExtractToken(token)
+
+		bookId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+		reviewId, _ := strconv.Atoi(_mw.GetParam(r)[1])
+		replyId, _ := strconv.Atoi(_mw.GetParam(r)[2])
+
+		code, message := rc.usecase.DeleteReply(uint(userId), uint(bookId), uint(reviewId), uint(replyId))
+
+		_model.CreateResponse(rw, code, message, nil)
+	}
+}
+
+func (rc ReviewController) ReportReply() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "Bearer ")
+		userId, _, _ := _helper.ExtractToken(token)
+
+		bookId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+		reviewId, _ := strconv.Atoi(_mw.GetParam(r)[1])
+		replyId, _ := strconv.Atoi(_mw.GetParam(r)[2])
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "internal server error", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.ReportReviewRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := rc.usecase.ReportReply(uint(userId), uint(bookId), uint(reviewId), uint(replyId), req)
+
+		if code != http.StatusCreated {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (rc ReviewController) GetReplyModerationQueue() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		res, code, message := rc.usecase.GetReplyModerationQueue()
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (rc ReviewController) ModerateReply() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "Bearer ")
+		librarianId, _, _ := _helper.ExtractToken(token)
+
+		replyId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "internal server error", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.ModerateReviewRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := rc.usecase.ModerateReply(uint(librarianId), uint(replyId), req)
+
+		if code != http.StatusCreated {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
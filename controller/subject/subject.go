@@ -0,0 +1,231 @@
+package subject
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	_mw "plain-go/public-library/app/middleware"
+	_model "plain-go/public-library/model"
+	_subjectUseCase "plain-go/public-library/usecase/subject"
+	"strconv"
+)
+
+type SubjectController struct {
+	usecase _subjectUseCase.Subject
+}
+
+func New(subject _subjectUseCase.Subject) *SubjectController {
+	return &SubjectController{usecase: subject}
+}
+
+func (sc SubjectController) GetAll() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		res, code, message := sc.usecase.GetAllSubjects(r.URL.Query())
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (sc SubjectController) Create() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "failed to read request body", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.CreateSubjectRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := sc.usecase.CreateSubject(req)
+
+		if code != http.StatusCreated {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (sc SubjectController) SeedDewey() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		res, code, message := sc.usecase.SeedDewey()
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (sc SubjectController) Get() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		subjectId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		res, code, message := sc.usecase.GetSubjectById(uint(subjectId))
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (sc SubjectController) Update() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		subjectId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "failed to read request body", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.UpdateSubjectRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := sc.usecase.UpdateSubject(uint(subjectId), req)
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (sc SubjectController) Delete() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		subjectId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		code, message := sc.usecase.DeleteSubject(uint(subjectId))
+
+		_model.CreateResponse(rw, code, message, nil)
+	}
+}
+
+func (sc SubjectController) GetBooks() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		subjectId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		res, code, message := sc.usecase.GetBooksBySubject(uint(subjectId), r.URL.Query())
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (sc SubjectController) GetBookClassification() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		bookId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		res, code, message := sc.usecase.GetBookClassification(uint(bookId))
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (sc SubjectController) SetBookSubjects() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		bookId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "failed to read request body", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.SetBookSubjectsRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := sc.usecase.SetBookSubjects(uint(bookId), req)
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (sc SubjectController) SetCallNumber() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		bookId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "failed to read request body", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.SetCallNumberRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := sc.usecase.SetCallNumber(uint(bookId), req)
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
@@ -0,0 +1,153 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	_mw "plain-go/public-library/app/middleware"
+	_model "plain-go/public-library/model"
+	_webhookUseCase "plain-go/public-library/usecase/webhook"
+	"strconv"
+)
+
+type WebhookController struct {
+	usecase _webhookUseCase.Webhook
+}
+
+func New(webhook _webhookUseCase.Webhook) *WebhookController {
+	return &WebhookController{usecase: webhook}
+}
+
+func (wc WebhookController) Create() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "failed to read request body", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.CreateWebhookRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := wc.usecase.CreateWebhook(req)
+
+		if code != http.StatusCreated {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (wc WebhookController) GetAll() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		res, code, message := wc.usecase.GetAllWebhooks()
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (wc WebhookController) Get() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		webhookId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		res, code, message := wc.usecase.GetWebhookById(uint(webhookId))
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (wc WebhookController) Update() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		webhookId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "failed to read request body", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.UpdateWebhookRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := wc.usecase.UpdateWebhook(uint(webhookId), req)
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (wc WebhookController) Delete() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		webhookId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		code, message := wc.usecase.DeleteWebhook(uint(webhookId))
+
+		_model.CreateResponse(rw, code, message, nil)
+	}
+}
+
+func (wc WebhookController) GetAllDeliveries() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		webhookId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		res, code, message := wc.usecase.GetAllDeliveries(uint(webhookId))
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (wc WebhookController) Replay() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		webhookId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+		deliveryId, _ := strconv.Atoi(_mw.GetParam(r)[1])
+
+		res, code, message := wc.usecase.ReplayDelivery(uint(webhookId), uint(deliveryId))
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
@@ -0,0 +1,291 @@
+package work
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	_mw "plain-go/public-library/app/middleware"
+	_model "plain-go/public-library/model"
+	_workUseCase "plain-go/public-library/usecase/work"
+	"strconv"
+)
+
+type WorkController struct {
+	usecase _workUseCase.Work
+}
+
+func New(work _workUseCase.Work) *WorkController {
+	return &WorkController{usecase: work}
+}
+
+func (wc WorkController) GetAll() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		res, code, message := wc.usecase.GetAllWorks(r.URL.Query())
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (wc WorkController) Create() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "failed to read request body", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.CreateWorkRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := wc.usecase.CreateWork(req)
+
+		if code != http.StatusCreated {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (wc WorkController) Get() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		workId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		res, code, message := wc.usecase.GetWorkById(uint(workId))
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (wc WorkController) Update() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		workId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "failed to read request body", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.UpdateWorkRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := wc.usecase.UpdateWork(uint(workId), req)
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (wc WorkController) AddEdition() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		workId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+		bookId, _ := strconv.Atoi(_mw.GetParam(r)[1])
+
+		res, code, message := wc.usecase.AddEdition(uint(workId), uint(bookId))
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (wc WorkController) RemoveEdition() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		workId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+		bookId, _ := strconv.Atoi(_mw.GetParam(r)[1])
+
+		code, message := wc.usecase.RemoveEdition(uint(workId), uint(bookId))
+
+		_model.CreateResponse(rw, code, message, nil)
+	}
+}
+
+func (wc WorkController) GetReviews() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		workId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		res, code, message := wc.usecase.GetWorkReviews(uint(workId), r.URL.Query())
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (wc WorkController) GetAllSeries() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		res, code, message := wc.usecase.GetAllSeries()
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (wc WorkController) CreateSeries() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "failed to read request body", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.CreateSeriesRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := wc.usecase.CreateSeries(req)
+
+		if code != http.StatusCreated {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (wc WorkController) GetSeries() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		seriesId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		res, code, message := wc.usecase.GetSeriesById(uint(seriesId))
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (wc WorkController) UpdateSeries() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		seriesId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "failed to read request body", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.UpdateSeriesRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := wc.usecase.UpdateSeries(uint(seriesId), req)
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (wc WorkController) SaveSeriesWork() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		seriesId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+		workId, _ := strconv.Atoi(_mw.GetParam(r)[1])
+
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusInternalServerError, "failed to read request body", nil)
+			return
+		}
+
+		defer r.Body.Close()
+
+		req := _model.SaveSeriesWorkRequest{}
+
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Println(err)
+			_model.CreateResponse(rw, http.StatusBadRequest, "failed to bind request body", nil)
+			return
+		}
+
+		res, code, message := wc.usecase.SaveSeriesWork(uint(seriesId), uint(workId), req)
+
+		if code != http.StatusOK {
+			_model.CreateResponse(rw, code, message, nil)
+			return
+		}
+
+		_model.CreateResponse(rw, code, message, res)
+	}
+}
+
+func (wc WorkController) RemoveSeriesWork() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		seriesId, _ := strconv.Atoi(_mw.GetParam(r)[0])
+		workId, _ := strconv.Atoi(_mw.GetParam(r)[1])
+
+		code, message := wc.usecase.RemoveSeriesWork(uint(seriesId), uint(workId))
+
+		_model.CreateResponse(rw, code, message, nil)
+	}
+}
@@ -0,0 +1,567 @@
+package acquisition
+
+import (
+	"database/sql"
+	"log"
+	_transaction "plain-go/public-library/datastore/transaction"
+	_entity "plain-go/public-library/entity"
+	_helper "plain-go/public-library/helper"
+	_model "plain-go/public-library/model"
+	"strings"
+	"time"
+)
+
+type AcquisitionRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *AcquisitionRepository {
+	return &AcquisitionRepository{db: db}
+}
+
+func (ar *AcquisitionRepository) GetCandidateByKey(key string) (candidate _entity.AcquisitionCandidate, err error) {
+	// prepare statement before execution
+	// candidate closed by a created book is never reused
+	stmt, err := ar.db.Prepare(`
+		SELECT id, normalized_key, title, authors, category, status, reject_reason, budget, vendor, book_id, created_at, updated_at
+		FROM acquisition_candidates
+		WHERE normalized_key = ?
+		  AND book_id = 0
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(key)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		if err = row.Scan(&candidate.Id, &candidate.Key, &candidate.Title, &candidate.Authors, &candidate.Category, &candidate.Status, &candidate.RejectReason, &candidate.Budget, &candidate.Vendor, &candidate.BookId, &candidate.CreatedAt, &candidate.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
+func (ar *AcquisitionRepository) CreateCandidate(newCandidate _entity.AcquisitionCandidate) (candidate _entity.AcquisitionCandidate, err error) {
+	// prepare statement before execution
+	stmt, err := ar.db.Prepare(`
+		INSERT INTO acquisition_candidates (normalized_key, title_key, title, authors, category, status, reject_reason, budget, vendor, book_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, '', 0, '', 0, ?, ?)
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	res, err := stmt.Exec(newCandidate.Key, _helper.MatchKey(_helper.NormalizeTitle(newCandidate.Title)), newCandidate.Title, newCandidate.Authors, newCandidate.Category, newCandidate.Status, newCandidate.CreatedAt, newCandidate.UpdatedAt)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	// get new candidate id
+	id, err := res.LastInsertId()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	candidate = newCandidate
+	candidate.Id = uint(id)
+
+	return
+}
+
+func (ar *AcquisitionRepository) AttachWish(wishId uint, candidateId uint) (err error) {
+	// prepare statement before execution
+	stmt, err := ar.db.Prepare(`
+		UPDATE wishlists
+		SET candidate_id = ?
+		WHERE id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(candidateId, wishId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+func (ar *AcquisitionRepository) GetUngroupedWishes() (wishes []_entity.SimplifiedWish, err error) {
+	// prepare statement before execution
+	stmt, err := ar.db.Prepare(`
+		SELECT id, user_id, title, category, note, created_at, updated_at
+		FROM wishlists
+		WHERE candidate_id IS NULL
+		  AND closed_at IS NULL
+		  AND deleted_at IS NULL
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		wish := _entity.SimplifiedWish{}
+
+		if err = row.Scan(&wish.Id, &wish.User.Id, &wish.Title, &wish.Category, &wish.Note, &wish.CreatedAt, &wish.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+
+		wishes = append(wishes, wish)
+	}
+
+	return
+}
+
+// popularity counts every wish and every upvote
+var candidateOrders = map[string]string{
+	"votes":    "s.wish_count + s.vote_count DESC, s.created_at DESC",
+	"recent":   "s.created_at DESC",
+	"category": "s.category, s.wish_count + s.vote_count DESC",
+}
+
+func (ar *AcquisitionRepository) GetAllCandidates(params _model.GetAllAcquisitionCandidatesRequest) (candidates []_entity.AcquisitionCandidate, err error) {
+	// basic query
+	query := (`
+		SELECT s.id, s.normalized_key, s.title, s.authors, s.category, s.status, s.reject_reason, s.budget, s.vendor, s.book_id, s.created_at, s.updated_at, s.wish_count, s.vote_count
+		FROM (
+			SELECT c.*,
+				(SELECT COUNT(*) FROM wishlists w WHERE w.candidate_id = c.id AND w.deleted_at IS NULL) AS wish_count,
+				(SELECT COUNT(*) FROM acquisition_votes v WHERE v.candidate_id = c.id) AS vote_count
+			FROM acquisition_candidates c
+			WHERE (? = '' OR c.status = ?)
+			  AND (? = '' OR c.category = ?)
+		) s
+	`)
+
+	// sort by
+	order, exist := candidateOrders[params.SortBy]
+
+	if !exist {
+		order = candidateOrders["votes"]
+	}
+
+	query += ` ORDER BY ` + order
+
+	// prepare statement before execution
+	stmt, err := ar.db.Prepare(query)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(params.Status, params.Status, params.Category, params.Category)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		candidate := _entity.AcquisitionCandidate{}
+
+		if err = row.Scan(&candidate.Id, &candidate.Key, &candidate.Title, &candidate.Authors, &candidate.Category, &candidate.Status, &candidate.RejectReason, &candidate.Budget, &candidate.Vendor, &candidate.BookId, &candidate.CreatedAt, &candidate.UpdatedAt, &candidate.WishCount, &candidate.VoteCount); err != nil {
+			log.Println(err)
+			return
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	return
+}
+
+func (ar *AcquisitionRepository) GetCandidateById(candidateId uint) (candidate _entity.AcquisitionCandidate, err error) {
+	// prepare statement before execution
+	stmt, err := ar.db.Prepare(`
+		SELECT c.id, c.normalized_key, c.title, c.authors, c.category, c.status, c.reject_reason, c.budget, c.vendor, c.book_id, c.created_at, c.updated_at,
+			(SELECT COUNT(*) FROM wishlists w WHERE w.candidate_id = c.id AND w.deleted_at IS NULL) AS wish_count,
+			(SELECT COUNT(*) FROM acquisition_votes v WHERE v.candidate_id = c.id) AS vote_count
+		FROM acquisition_candidates c
+		WHERE c.id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(candidateId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		if err = row.Scan(&candidate.Id, &candidate.Key, &candidate.Title, &candidate.Authors, &candidate.Category, &candidate.Status, &candidate.RejectReason, &candidate.Budget, &candidate.Vendor, &candidate.BookId, &candidate.CreatedAt, &candidate.UpdatedAt, &candidate.WishCount, &candidate.VoteCount); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
+// GetOpenCandidatesByTitleKey shortlists candidates still waiting for purchase whose stored title key
+// has the prefix and a length in range
+func (ar *AcquisitionRepository) GetOpenCandidatesByTitleKey(prefix string, minLength int, maxLength int) (candidates []_entity.AcquisitionCandidate, err error) {
+	// prepare statement before execution
+	stmt, err := ar.db.Prepare(`
+		SELECT id, title, status
+		FROM acquisition_candidates
+		WHERE status IN ('under_review', 'ordered')
+		  AND title_key LIKE ?
+		  AND CHAR_LENGTH(title_key) BETWEEN ? AND ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(prefix+"%", minLength, maxLength)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		candidate := _entity.AcquisitionCandidate{}
+
+		if err = row.Scan(&candidate.Id, &candidate.Title, &candidate.Status); err != nil {
+			log.Println(err)
+			return
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	return
+}
+
+func (ar *AcquisitionRepository) GetSuggestions(userId uint, params _model.GetAllAcquisitionCandidatesRequest) (suggestions []_entity.Suggestion, err error) {
+	// basic query
+	// only candidate still waiting for purchase is open for vote
+	query := (`
+		SELECT s.id, s.title, s.authors, s.category, s.status, s.wish_count, s.vote_count, s.voted, s.created_at
+		FROM (
+			SELECT c.id, c.title, c.authors, c.category, c.status, c.created_at,
+				(SELECT COUNT(*) FROM wishlists w WHERE w.candidate_id = c.id AND w.deleted_at IS NULL) AS wish_count,
+				(SELECT COUNT(*) FROM acquisition_votes v WHERE v.candidate_id = c.id) AS vote_count,
+				EXISTS (SELECT 1 FROM acquisition_votes v WHERE v.candidate_id = c.id AND v.user_id = ?) AS voted
+			FROM acquisition_candidates c
+			WHERE c.status IN ('under_review', 'ordered')
+			  AND (? = '' OR c.category = ?)
+			  AND (? = '' OR UPPER(c.title) LIKE ?)
+		) s
+	`)
+
+	// sort by
+	order, exist := candidateOrders[params.SortBy]
+
+	if !exist {
+		order = candidateOrders["votes"]
+	}
+
+	query += ` ORDER BY ` + order
+
+	// prepare statement before execution
+	stmt, err := ar.db.Prepare(query)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	keyword := "%" + strings.ToUpper(params.Keyword) + "%"
+	row, err := stmt.Query(userId, params.Category, params.Category, params.Keyword, keyword)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		suggestion := _entity.Suggestion{}
+
+		if err = row.Scan(&suggestion.Id, &suggestion.Title, &suggestion.Authors, &suggestion.Category, &suggestion.Status, &suggestion.WishCount, &suggestion.VoteCount, &suggestion.Voted, &suggestion.CreatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+
+		suggestions = append(suggestions, suggestion)
+	}
+
+	return
+}
+
+func (ar *AcquisitionRepository) HasVoted(candidateId uint, userId uint) (voted bool, err error) {
+	// prepare statement before execution
+	stmt, err := ar.db.Prepare(`
+		SELECT COUNT(*)
+		FROM acquisition_votes
+		WHERE candidate_id = ?
+		  AND user_id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(candidateId, userId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	count := 0
+
+	if row.Next() {
+		if err = row.Scan(&count); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	voted = count > 0
+
+	return
+}
+
+func (ar *AcquisitionRepository) Vote(candidateId uint, userId uint) (err error) {
+	// prepare statement before execution
+	stmt, err := ar.db.Prepare(`
+		INSERT INTO acquisition_votes (candidate_id, user_id, created_at)
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE candidate_id = candidate_id
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	if _, err = stmt.Exec(candidateId, userId, time.Now()); err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+func (ar *AcquisitionRepository) RemoveVote(candidateId uint, userId uint) (err error) {
+	// prepare statement before execution
+	stmt, err := ar.db.Prepare(`
+		DELETE FROM acquisition_votes
+		WHERE candidate_id = ?
+		  AND user_id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	if _, err = stmt.Exec(candidateId, userId); err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+func (ar *AcquisitionRepository) HasWished(candidateId uint, userId uint) (wished bool, err error) {
+	// prepare statement before execution
+	stmt, err := ar.db.Prepare(`
+		SELECT COUNT(*)
+		FROM wishlists
+		WHERE candidate_id = ?
+		  AND user_id = ?
+		  AND deleted_at IS NULL
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(candidateId, userId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	count := 0
+
+	if row.Next() {
+		if err = row.Scan(&count); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	wished = count > 0
+
+	return
+}
+
+func (ar *AcquisitionRepository) GetWishesByCandidateId(candidateId uint) (wishes []_entity.SimplifiedWish, err error) {
+	// prepare statement before execution
+	stmt, err := ar.db.Prepare(`
+		SELECT id, user_id, title, category, note, created_at, updated_at
+		FROM wishlists
+		WHERE candidate_id = ?
+		  AND deleted_at IS NULL
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(candidateId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		wish := _entity.SimplifiedWish{}
+
+		if err = row.Scan(&wish.Id, &wish.User.Id, &wish.Title, &wish.Category, &wish.Note, &wish.CreatedAt, &wish.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+
+		wishes = append(wishes, wish)
+	}
+
+	return
+}
+
+func (ar *AcquisitionRepository) UpdateCandidate(updatedCandidate _entity.AcquisitionCandidate, events ..._entity.Event) (candidate _entity.AcquisitionCandidate, err error) {
+	steps := []_transaction.Step{{
+		Query: `
+			UPDATE acquisition_candidates
+			SET status = ?, reject_reason = ?, budget = ?, vendor = ?, updated_at = ?
+			WHERE id = ?
+		`,
+		Args: []interface{}{updatedCandidate.Status, updatedCandidate.RejectReason, updatedCandidate.Budget, updatedCandidate.Vendor, updatedCandidate.UpdatedAt, updatedCandidate.Id},
+	}}
+
+	// candidate and its events are committed together
+	if err = _transaction.Commit(ar.db, steps, updatedCandidate.Id, events...); err != nil {
+		return
+	}
+
+	candidate = updatedCandidate
+
+	return
+}
+
+func (ar *AcquisitionRepository) CloseCandidate(candidateId uint, bookId uint, events ..._entity.Event) (err error) {
+	now := time.Now()
+
+	steps := []_transaction.Step{
+		{Query: `UPDATE acquisition_candidates SET status = 'received', book_id = ?, updated_at = ? WHERE id = ?`, Args: []interface{}{bookId, now, candidateId}},
+		// close every matching wish
+		{Query: `UPDATE wishlists SET closed_at = ? WHERE candidate_id = ? AND closed_at IS NULL`, Args: []interface{}{now, candidateId}},
+	}
+
+	// candidate, its wishes and events are committed together
+	return _transaction.Commit(ar.db, steps, candidateId, events...)
+}
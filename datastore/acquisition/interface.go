@@ -0,0 +1,24 @@
+package acquisition
+
+import (
+	_entity "plain-go/public-library/entity"
+	_model "plain-go/public-library/model"
+)
+
+type Acquisition interface {
+	GetCandidateByKey(key string) (candidate _entity.AcquisitionCandidate, err error)
+	CreateCandidate(newCandidate _entity.AcquisitionCandidate) (candidate _entity.AcquisitionCandidate, err error)
+	AttachWish(wishId uint, candidateId uint) (err error)
+	GetUngroupedWishes() (wishes []_entity.SimplifiedWish, err error)
+	GetAllCandidates(params _model.GetAllAcquisitionCandidatesRequest) (candidates []_entity.AcquisitionCandidate, err error)
+	GetCandidateById(candidateId uint) (candidate _entity.AcquisitionCandidate, err error)
+	GetWishesByCandidateId(candidateId uint) (wishes []_entity.SimplifiedWish, err error)
+	UpdateCandidate(updatedCandidate _entity.AcquisitionCandidate, events ..._entity.Event) (candidate _entity.AcquisitionCandidate, err error)
+	CloseCandidate(candidateId uint, bookId uint, events ..._entity.Event) (err error)
+	GetOpenCandidatesByTitleKey(prefix string, minLength int, maxLength int) (candidates []_entity.AcquisitionCandidate, err error)
+	GetSuggestions(userId uint, params _model.GetAllAcquisitionCandidatesRequest) (suggestions []_entity.Suggestion, err error)
+	HasVoted(candidateId uint, userId uint) (voted bool, err error)
+	Vote(candidateId uint, userId uint) (err error)
+	RemoveVote(candidateId uint, userId uint) (err error)
+	HasWished(candidateId uint, userId uint) (wished bool, err error)
+}
@@ -0,0 +1,361 @@
+package author
+
+import (
+	"database/sql"
+	"log"
+	_transaction "plain-go/public-library/datastore/transaction"
+	_entity "plain-go/public-library/entity"
+	_helper "plain-go/public-library/helper"
+	_model "plain-go/public-library/model"
+	"strings"
+	"time"
+)
+
+type AuthorRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *AuthorRepository {
+	return &AuthorRepository{db: db}
+}
+
+func (ar *AuthorRepository) GetAllAuthors(params _model.GetAllAuthorsRequest) (authors []_entity.AuthorDetail, err error) {
+	// prepare statement before execution
+	stmt, err := ar.db.Prepare(`
+		SELECT a.id, a.name, a.biography, a.birth_year, a.death_year,
+			(
+				SELECT COUNT(b.id)
+				FROM book_author_junction ba
+				JOIN books b
+				ON ba.book_id = b.id
+				WHERE ba.author_id = a.id
+				  AND ba.deleted_at IS NULL
+				  AND b.deleted_at IS NULL
+			) AS book_count
+		FROM authors a
+		WHERE ? = ''
+		   OR UPPER(a.name) LIKE ?
+		ORDER BY a.name
+		LIMIT ? OFFSET ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	keyword := "%" + strings.ToUpper(params.Keyword) + "%"
+	row, err := stmt.Query(params.Keyword, keyword, params.Records, (params.Page-1)*params.Records)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		author := _entity.AuthorDetail{}
+
+		if err = row.Scan(&author.Id, &author.Name, &author.Biography, &author.BirthYear, &author.DeathYear, &author.BookCount); err != nil {
+			log.Println(err)
+			return
+		}
+
+		authors = append(authors, author)
+	}
+
+	return
+}
+
+func (ar *AuthorRepository) CountAuthors(keyword string) (count uint, err error) {
+	// prepare statement before execution
+	stmt, err := ar.db.Prepare(`
+		SELECT COUNT(id)
+		FROM authors
+		WHERE ? = ''
+		   OR UPPER(name) LIKE ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(keyword, "%"+strings.ToUpper(keyword)+"%")
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		if err = row.Scan(&count); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
+func (ar *AuthorRepository) GetAuthorById(authorId uint) (author _entity.AuthorDetail, err error) {
+	// prepare statement before execution
+	stmt, err := ar.db.Prepare(`
+		SELECT a.id, a.name, a.biography, a.birth_year, a.death_year,
+			(
+				SELECT COUNT(b.id)
+				FROM book_author_junction ba
+				JOIN books b
+				ON ba.book_id = b.id
+				WHERE ba.author_id = a.id
+				  AND ba.deleted_at IS NULL
+				  AND b.deleted_at IS NULL
+			) AS book_count
+		FROM authors a
+		WHERE a.id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(authorId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		if err = row.Scan(&author.Id, &author.Name, &author.Biography, &author.BirthYear, &author.DeathYear, &author.BookCount); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
+func (ar *AuthorRepository) GetIdentifiers(authorId uint) (identifiers map[string]string, err error) {
+	// prepare statement before execution
+	stmt, err := ar.db.Prepare(`
+		SELECT scheme, value
+		FROM author_identifiers
+		WHERE author_id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(authorId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	identifiers = map[string]string{}
+
+	for row.Next() {
+		var scheme, value string
+
+		if err = row.Scan(&scheme, &value); err != nil {
+			log.Println(err)
+			return
+		}
+
+		identifiers[scheme] = value
+	}
+
+	return
+}
+
+func (ar *AuthorRepository) GetBibliography(authorId uint) (books []_entity.Book, err error) {
+	// prepare statement before execution
+	stmt, err := ar.db.Prepare(`
+		SELECT b.id, b.title, b.publisher, b.language, b.pages, b.category, b.isbn13, b.description, b.created_at, b.updated_at
+		FROM books b
+		JOIN book_author_junction ba
+		ON b.id = ba.book_id
+		WHERE ba.author_id = ?
+		  AND ba.deleted_at IS NULL
+		  AND b.deleted_at IS NULL
+		ORDER BY b.title
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(authorId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		book := _entity.Book{}
+
+		if err = row.Scan(&book.Id, &book.Title, &book.Publisher, &book.Language, &book.Pages, &book.Category, &book.ISBN13, &book.Description, &book.CreatedAt, &book.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+
+		books = append(books, book)
+	}
+
+	return
+}
+
+func (ar *AuthorRepository) GetBorrowStats(authorId uint) (stats _entity.AuthorBorrowStats, err error) {
+	// prepare statement before execution
+	// request from status 5 (borrowed) to 9 (returned late) is counted as borrow
+	stmt, err := ar.db.Prepare(`
+		SELECT COUNT(r.id),
+			COALESCE(SUM(r.status_id IN (5, 6, 7)), 0),
+			COUNT(DISTINCT r.user_id)
+		FROM requests r
+		JOIN book_items bi
+		ON r.book_item_id = bi.id
+		JOIN book_author_junction ba
+		ON bi.book_id = ba.book_id
+		WHERE ba.author_id = ?
+		  AND ba.deleted_at IS NULL
+		  AND r.status_id BETWEEN 5 AND 9
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(authorId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		if err = row.Scan(&stats.BorrowCount, &stats.ActiveBorrowCount, &stats.BorrowerCount); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
+func (ar *AuthorRepository) UpdateAuthor(updatedAuthor _entity.AuthorDetail, events ..._entity.Event) (author _entity.AuthorDetail, err error) {
+	steps := []_transaction.Step{
+		{
+			Query: `
+				UPDATE authors
+				SET name = ?, name_key = ?, biography = ?, birth_year = ?, death_year = ?
+				WHERE id = ?
+			`,
+			Args: []interface{}{updatedAuthor.Name, _helper.MatchKey(_helper.NormalizeName(updatedAuthor.Name)), updatedAuthor.Biography, updatedAuthor.BirthYear, updatedAuthor.DeathYear, updatedAuthor.Id},
+		},
+		// identifiers are replaced as a whole
+		{Query: `DELETE FROM author_identifiers WHERE author_id = ?`, Args: []interface{}{updatedAuthor.Id}},
+	}
+
+	for scheme, value := range updatedAuthor.ExternalIds {
+		steps = append(steps, _transaction.Step{Query: `INSERT INTO author_identifiers (author_id, scheme, value) VALUES (?, ?, ?)`, Args: []interface{}{updatedAuthor.Id, scheme, value}})
+	}
+
+	// author, its identifiers and its events are saved together
+	if err = _transaction.Commit(ar.db, steps, updatedAuthor.Id, events...); err != nil {
+		return
+	}
+
+	author = updatedAuthor
+
+	return
+}
+
+// statements moving everything that belongs to merged author, executed in order,
+// junction of a book or wish already attached to target author is dropped instead
+var mergeStatements = []string{
+	`UPDATE book_author_junction
+	SET author_id = ?
+	WHERE author_id = ?
+	  AND deleted_at IS NULL
+	  AND book_id NOT IN (
+		SELECT book_id FROM (SELECT book_id FROM book_author_junction WHERE author_id = ? AND deleted_at IS NULL) t
+	  )`,
+	`UPDATE wish_author_junction
+	SET author_id = ?
+	WHERE author_id = ?
+	  AND deleted_at IS NULL
+	  AND wish_id NOT IN (
+		SELECT wish_id FROM (SELECT wish_id FROM wish_author_junction WHERE author_id = ? AND deleted_at IS NULL) t
+	  )`,
+	`UPDATE author_identifiers
+	SET author_id = ?
+	WHERE author_id = ?
+	  AND scheme NOT IN (
+		SELECT scheme FROM (SELECT scheme FROM author_identifiers WHERE author_id = ?) t
+	  )`,
+}
+
+// statements dropping what is left on merged author
+var mergeCleanupStatements = []string{
+	`UPDATE book_author_junction SET deleted_at = ? WHERE author_id = ? AND deleted_at IS NULL`,
+	`UPDATE wish_author_junction SET deleted_at = ? WHERE author_id = ? AND deleted_at IS NULL`,
+}
+
+func (ar *AuthorRepository) MergeAuthors(sourceId uint, targetId uint, events ..._entity.Event) (err error) {
+	now := time.Now()
+
+	steps := []_transaction.Step{}
+
+	for _, query := range mergeStatements {
+		steps = append(steps, _transaction.Step{Query: query, Args: []interface{}{targetId, sourceId, targetId}})
+	}
+
+	for _, query := range mergeCleanupStatements {
+		steps = append(steps, _transaction.Step{Query: query, Args: []interface{}{now, sourceId}})
+	}
+
+	// identifiers and the author itself have no history to keep
+	steps = append(steps, _transaction.Step{Query: `DELETE FROM author_identifiers WHERE author_id = ?`, Args: []interface{}{sourceId}})
+	steps = append(steps, _transaction.Step{Query: `DELETE FROM authors WHERE id = ?`, Args: []interface{}{sourceId}})
+
+	// merge and its events are applied completely or not at all
+	return _transaction.Commit(ar.db, steps, targetId, events...)
+}
@@ -0,0 +1,17 @@
+package author
+
+import (
+	_entity "plain-go/public-library/entity"
+	_model "plain-go/public-library/model"
+)
+
+type Author interface {
+	GetAllAuthors(params _model.GetAllAuthorsRequest) (authors []_entity.AuthorDetail, err error)
+	CountAuthors(keyword string) (count uint, err error)
+	GetAuthorById(authorId uint) (author _entity.AuthorDetail, err error)
+	GetIdentifiers(authorId uint) (identifiers map[string]string, err error)
+	GetBibliography(authorId uint) (books []_entity.Book, err error)
+	GetBorrowStats(authorId uint) (stats _entity.AuthorBorrowStats, err error)
+	UpdateAuthor(updatedAuthor _entity.AuthorDetail, events ..._entity.Event) (author _entity.AuthorDetail, err error)
+	MergeAuthors(sourceId uint, targetId uint, events ..._entity.Event) (err error)
+}
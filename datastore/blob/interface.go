@@ -0,0 +1,8 @@
+package blob
+
+// blob store keeps binary content under slash separated key, missing key reports os.ErrNotExist
+type Blob interface {
+	Put(key string, data []byte) (err error)
+	Get(key string) (data []byte, err error)
+	Delete(key string) (err error)
+}
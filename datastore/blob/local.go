@@ -0,0 +1,102 @@
+package blob
+
+import (
+	"errors"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type LocalRepository struct {
+	root string
+}
+
+func NewLocal(root string) *LocalRepository {
+	return &LocalRepository{root: root}
+}
+
+// path keeps key inside root directory
+func (lr *LocalRepository) path(key string) (path string, err error) {
+	clean := filepath.Clean("/" + key)
+
+	if clean == "/" || strings.Contains(key, "..") {
+		err = errors.New("invalid key")
+		log.Println(err)
+		return
+	}
+
+	path = filepath.Join(lr.root, filepath.FromSlash(clean))
+	return
+}
+
+func (lr *LocalRepository) Put(key string, data []byte) (err error) {
+	path, err := lr.path(key)
+
+	if err != nil {
+		return
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		log.Println(err)
+		return
+	}
+
+	// write to temporary file first so that reader never sees partial content
+	file, err := ioutil.TempFile(filepath.Dir(path), ".upload-*")
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer os.Remove(file.Name())
+
+	if _, err = file.Write(data); err != nil {
+		log.Println(err)
+		file.Close()
+		return
+	}
+
+	if err = file.Close(); err != nil {
+		log.Println(err)
+		return
+	}
+
+	if err = os.Rename(file.Name(), path); err != nil {
+		log.Println(err)
+	}
+
+	return
+}
+
+func (lr *LocalRepository) Get(key string) (data []byte, err error) {
+	path, err := lr.path(key)
+
+	if err != nil {
+		return
+	}
+
+	if data, err = ioutil.ReadFile(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Println(err)
+	}
+
+	return
+}
+
+func (lr *LocalRepository) Delete(key string) (err error) {
+	path, err := lr.path(key)
+
+	if err != nil {
+		return
+	}
+
+	if err = os.Remove(path); errors.Is(err, os.ErrNotExist) {
+		err = nil
+	} else if err != nil {
+		log.Println(err)
+	}
+
+	return
+}
@@ -2,18 +2,46 @@ package book
 
 import (
 	"database/sql"
+	"errors"
 	"log"
 	"strings"
 	"time"
+	"unicode"
 
+	_requestRepository "plain-go/public-library/datastore/request"
+	_transaction "plain-go/public-library/datastore/transaction"
 	_entity "plain-go/public-library/entity"
+	_helper "plain-go/public-library/helper"
 	_model "plain-go/public-library/model"
+
+	"github.com/go-sql-driver/mysql"
 )
 
 type BookRepository struct {
 	db *sql.DB
 }
 
+// ErrDuplicateISBN is returned when another book already has the ISBN, unique key of books on isbn13_key
+// and active, a column which is NULL once the book is deleted, settles books created at the same time
+var ErrDuplicateISBN = errors.New("isbn already exist")
+
+// isbnKey is ISBN-13 of the book without hyphen, legacy ISBN-10 included, empty when isbn is missing or invalid
+func isbnKey(isbn string) string {
+	key, _ := _helper.ParseISBN(isbn)
+	return key
+}
+
+// duplicateISBN tells violation of the ISBN unique key apart from other failures
+func duplicateISBN(err error) error {
+	var mysqlErr *mysql.MySQLError
+
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 && strings.Contains(mysqlErr.Message, "isbn13_key") {
+		return ErrDuplicateISBN
+	}
+
+	return err
+}
+
 func New(db *sql.DB) *BookRepository {
 	return &BookRepository{db: db}
 }
@@ -54,6 +82,43 @@ func (br *BookRepository) GetBookByTitle(title string) (book _entity.Book, err e
 	return
 }
 
+// GetBookByISBN looks up normalized ISBN-13, so that book stored with hyphen or as ISBN-10 is still found
+func (br *BookRepository) GetBookByISBN(isbn13 string) (book _entity.Book, err error) {
+	// prepare statement before execution
+	stmt, err := br.db.Prepare(`
+		SELECT id, title, publisher, language, pages, category, isbn13, description, created_at, updated_at
+		FROM books
+		WHERE isbn13_key = ?
+		  AND deleted_at IS NULL
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(isbnKey(isbn13))
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		if err = row.Scan(&book.Id, &book.Title, &book.Publisher, &book.Language, &book.Pages, &book.Category, &book.ISBN13, &book.Description, &book.CreatedAt, &book.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
 func (br *BookRepository) GetAuthorByName(name string) (author _entity.Author, err error) {
 	// prepare statement before execution
 	stmt, err := br.db.Prepare(`
@@ -127,38 +192,37 @@ func (br *BookRepository) GetAllAuthors() (authors []_entity.Author, err error)
 	return
 }
 
-func (br *BookRepository) CreateNewBook(newBook _entity.Book) (book _entity.Book, err error) {
-	// prepare statement
-	stmt, err := br.db.Prepare(`
-		INSERT INTO books (title, publisher, language, pages, category, isbn13, description, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`)
+func (br *BookRepository) CreateNewBook(newBook _entity.Book, events ..._entity.Event) (book _entity.Book, err error) {
+	var id uint
 
-	if err != nil {
-		log.Println(err)
-		return
-	}
+	// book, its authors and its events are committed together
+	err = _transaction.Run(br.db, func(tx *sql.Tx) (uint, error) {
+		id, err = _transaction.Insert(tx, `
+			INSERT INTO books (title, title_key, publisher, language, pages, category, isbn13, isbn13_key, description, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), ?, ?, ?)
+		`, newBook.Title, _helper.MatchKey(_helper.NormalizeTitle(newBook.Title)), newBook.Publisher, newBook.Language, newBook.Pages, newBook.Category, newBook.ISBN13, isbnKey(newBook.ISBN13), newBook.Description, newBook.CreatedAt, newBook.UpdatedAt)
 
-	defer stmt.Close()
+		if err != nil {
+			return id, err
+		}
 
-	// execute statement
-	res, err := stmt.Exec(newBook.Title, newBook.Publisher, newBook.Language, newBook.Pages, newBook.Category, newBook.ISBN13, newBook.Description, newBook.CreatedAt, newBook.UpdatedAt)
+		// subscriber of the event sees the book with its authors
+		steps := []_transaction.Step{}
 
-	if err != nil {
-		log.Println(err)
-		return
-	}
+		for _, author := range newBook.Author {
+			steps = append(steps, _transaction.Step{Query: `INSERT INTO book_author_junction (book_id, author_id) VALUES (?, ?)`, Args: []interface{}{id, author.Id}})
+		}
 
-	// get new book id
-	id, err := res.LastInsertId()
+		return id, _transaction.Exec(tx, steps)
+	}, events...)
 
 	if err != nil {
-		log.Println(err)
+		err = duplicateISBN(err)
 		return
 	}
 
 	book = newBook
-	book.Id = uint(id)
+	book.Id = id
 
 	return
 }
@@ -166,8 +230,8 @@ func (br *BookRepository) CreateNewBook(newBook _entity.Book) (book _entity.Book
 func (br *BookRepository) CreateNewAuthor(newAuthor _entity.Author) (author _entity.Author, err error) {
 	// prepare statement before execution
 	stmt, err := br.db.Prepare(`
-		INSERT INTO authors (name)
-		VALUES (?)
+		INSERT INTO authors (name, name_key)
+		VALUES (?, ?)
 	`)
 
 	if err != nil {
@@ -178,7 +242,7 @@ func (br *BookRepository) CreateNewAuthor(newAuthor _entity.Author) (author _ent
 	defer stmt.Close()
 
 	// execute statement
-	res, err := stmt.Exec(newAuthor.Name)
+	res, err := stmt.Exec(newAuthor.Name, _helper.MatchKey(_helper.NormalizeName(newAuthor.Name)))
 
 	if err != nil {
 		log.Println(err)
@@ -408,26 +472,19 @@ func (br *BookRepository) CountBookById(bookId uint) (count uint, err error) {
 	return
 }
 
-func (br *BookRepository) UpdateBook(updatedBook _entity.Book) (book _entity.Book, err error) {
-	// prepare statement before execution
-	stmt, err := br.db.Prepare(`
-		UPDATE books
-		SET title = ?, publisher = ?, language = ?, pages = ?, category = ?, isbn13 = ?, description = ?, updated_at = ?
-		WHERE id = ?
-	`)
-
-	if err != nil {
-		log.Println(err)
-		return
-	}
-
-	defer stmt.Close()
-
-	// execute statement
-	_, err = stmt.Exec(updatedBook.Title, updatedBook.Publisher, updatedBook.Language, updatedBook.Pages, updatedBook.Category, updatedBook.ISBN13, updatedBook.Description, updatedBook.UpdatedAt, updatedBook.Id)
+func (br *BookRepository) UpdateBook(updatedBook _entity.Book, events ..._entity.Event) (book _entity.Book, err error) {
+	steps := []_transaction.Step{{
+		Query: `
+			UPDATE books
+			SET title = ?, title_key = ?, publisher = ?, language = ?, pages = ?, category = ?, isbn13 = ?, isbn13_key = NULLIF(?, ''), description = ?, updated_at = ?
+			WHERE id = ?
+		`,
+		Args: []interface{}{updatedBook.Title, _helper.MatchKey(_helper.NormalizeTitle(updatedBook.Title)), updatedBook.Publisher, updatedBook.Language, updatedBook.Pages, updatedBook.Category, updatedBook.ISBN13, isbnKey(updatedBook.ISBN13), updatedBook.Description, updatedBook.UpdatedAt, updatedBook.Id},
+	}}
 
-	if err != nil {
-		log.Println(err)
+	// book and its events are committed together
+	if err = _transaction.Commit(br.db, steps, updatedBook.Id, events...); err != nil {
+		err = duplicateISBN(err)
 		return
 	}
 
@@ -463,30 +520,11 @@ func (br *BookRepository) DeleteBookAuthorJunction(book _entity.Book, author _en
 	return
 }
 
-func (br *BookRepository) DeleteBook(bookId uint) (err error) {
-	// prepare statement before execution
-	stmt, err := br.db.Prepare(`
-		UPDATE books
-		SET deleted_at = ?
-		WHERE id = ?
-	`)
-
-	if err != nil {
-		log.Println(err)
-		return
-	}
-
-	defer stmt.Close()
-
-	// execute statement
-	_, err = stmt.Exec(time.Now(), bookId)
-
-	if err != nil {
-		log.Println(err)
-		return
-	}
+func (br *BookRepository) DeleteBook(bookId uint, events ..._entity.Event) (err error) {
+	steps := []_transaction.Step{{Query: `UPDATE books SET deleted_at = ? WHERE id = ?`, Args: []interface{}{time.Now(), bookId}}}
 
-	return
+	// book and its events are committed together
+	return _transaction.Commit(br.db, steps, bookId, events...)
 }
 
 func (br *BookRepository) AddBookToFavorite(userId uint, bookId uint) (favorite _entity.Favorite, err error) {
@@ -667,38 +705,25 @@ func (br *BookRepository) GetAllWishes() (wishes []_entity.SimplifiedWish, err e
 	return
 }
 
-func (br *BookRepository) AddBookToWishlist(userId uint, newWish _entity.Wish) (wish _entity.Wish, err error) {
-	// prepare statement before execution
-	stmt, err := br.db.Prepare(`
-		INSERT INTO wishlists (user_id, title, category, note, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?)
-	`)
-
-	if err != nil {
-		log.Println(err)
-		return
-	}
-
-	defer stmt.Close()
-
-	// execute statement
-	res, err := stmt.Exec(userId, newWish.Title, newWish.Category, newWish.Note, newWish.CreatedAt, newWish.UpdatedAt)
+func (br *BookRepository) AddBookToWishlist(userId uint, newWish _entity.Wish, events ..._entity.Event) (wish _entity.Wish, err error) {
+	var id uint
 
-	if err != nil {
-		log.Println(err)
-		return
-	}
+	// wish and its events are committed together
+	err = _transaction.Run(br.db, func(tx *sql.Tx) (uint, error) {
+		id, err = _transaction.Insert(tx, `
+			INSERT INTO wishlists (user_id, title, category, note, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, userId, newWish.Title, newWish.Category, newWish.Note, newWish.CreatedAt, newWish.UpdatedAt)
 
-	// get new book id
-	id, err := res.LastInsertId()
+		return id, err
+	}, events...)
 
 	if err != nil {
-		log.Println(err)
 		return
 	}
 
 	wish = newWish
-	wish.Id = uint(id)
+	wish.Id = id
 
 	return
 }
@@ -931,7 +956,7 @@ func (br *BookRepository) DeleteWishAuthorJunction(wish _entity.Wish, author _en
 func (br *BookRepository) GetAllReviews() (reviews []_entity.SimplifiedReview, err error) {
 	// prepare statment before execution
 	stmt, err := br.db.Prepare(`
-		SELECT id, user_id, book_id, star, content, flag, created_at, updated_at
+		SELECT id, user_id, book_id, star, content, flag, status, created_at, updated_at
 		FROM reviews
 		WHERE deleted_at IS NULL
 	`)
@@ -956,7 +981,7 @@ func (br *BookRepository) GetAllReviews() (reviews []_entity.SimplifiedReview, e
 	for row.Next() {
 		review := _entity.SimplifiedReview{}
 
-		if err = row.Scan(&review.Id, &review.User.Id, &review.Book.Id, &review.Star, &review.Content, &review.Flag, &review.CreatedAt, &review.UpdatedAt); err != nil {
+		if err = row.Scan(&review.Id, &review.User.Id, &review.Book.Id, &review.Star, &review.Content, &review.Flag, &review.Status, &review.CreatedAt, &review.UpdatedAt); err != nil {
 			log.Println(err)
 			return
 		}
@@ -967,11 +992,38 @@ func (br *BookRepository) GetAllReviews() (reviews []_entity.SimplifiedReview, e
 	return
 }
 
-func (br *BookRepository) CreateReview(newReview _entity.SimplifiedReview) (review _entity.SimplifiedReview, err error) {
+func (br *BookRepository) CreateReview(newReview _entity.SimplifiedReview, events ..._entity.Event) (review _entity.SimplifiedReview, err error) {
+	var id uint
+
+	// review and its events are committed together
+	err = _transaction.Run(br.db, func(tx *sql.Tx) (uint, error) {
+		flag := 0
+		id, err = _transaction.Insert(tx, `
+			INSERT INTO reviews (user_id, book_id, star, content, flag, status, screening_reason, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, newReview.User.Id, newReview.Book.Id, newReview.Star, newReview.Content, flag, newReview.Status, newReview.ScreeningReason, newReview.CreatedAt, newReview.UpdatedAt)
+
+		return id, err
+	}, events...)
+
+	if err != nil {
+		return
+	}
+
+	review = newReview
+	review.Id = id
+
+	return
+}
+
+func (br *BookRepository) GetReviewByReviewId(reviewId uint) (review _entity.SimplifiedReview, err error) {
 	// prepare statement before execution
 	stmt, err := br.db.Prepare(`
-		INSERT INTO reviews (user_id, book_id, star, content, flag, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?)
+		SELECT r.id, r.user_id, r.book_id, r.star, r.content, r.flag, r.status, r.created_at, r.updated_at,
+			` + _requestRepository.VerifiedBorrower + `
+		FROM reviews r
+		WHERE r.id = ?
+		  AND r.deleted_at IS NULL
 	`)
 
 	if err != nil {
@@ -982,34 +1034,33 @@ func (br *BookRepository) CreateReview(newReview _entity.SimplifiedReview) (revi
 	defer stmt.Close()
 
 	// execute statement
-	flag := 0
-	res, err := stmt.Exec(newReview.User.Id, newReview.Book.Id, newReview.Star, newReview.Content, flag, newReview.CreatedAt, newReview.UpdatedAt)
+	row, err := stmt.Query(reviewId)
 
 	if err != nil {
 		log.Println(err)
 		return
 	}
 
-	// get new book id
-	id, err := res.LastInsertId()
+	defer row.Close()
 
-	if err != nil {
-		log.Println(err)
-		return
+	if row.Next() {
+		if err = row.Scan(&review.Id, &review.User.Id, &review.Book.Id, &review.Star, &review.Content, &review.Flag, &review.Status, &review.CreatedAt, &review.UpdatedAt, &review.VerifiedBorrower); err != nil {
+			log.Println(err)
+			return
+		}
 	}
 
-	review = newReview
-	review.Id = uint(id)
-
 	return
 }
 
-func (br *BookRepository) GetReviewByReviewId(reviewId uint) (review _entity.SimplifiedReview, err error) {
+func (br *BookRepository) GetReviewByUserId(userId uint, bookId uint) (review _entity.SimplifiedReview, err error) {
 	// prepare statement before execution
 	stmt, err := br.db.Prepare(`
-		SELECT id, user_id, book_id, star, content, flag, created_at, updated_at
+		SELECT id, user_id, book_id, star, content, flag, status, created_at, updated_at
 		FROM reviews
-		WHERE id = ?
+		WHERE user_id = ?
+		  AND book_id = ?
+		  AND deleted_at IS NULL
 	`)
 
 	if err != nil {
@@ -1020,7 +1071,7 @@ func (br *BookRepository) GetReviewByReviewId(reviewId uint) (review _entity.Sim
 	defer stmt.Close()
 
 	// execute statement
-	row, err := stmt.Query(reviewId)
+	row, err := stmt.Query(userId, bookId)
 
 	if err != nil {
 		log.Println(err)
@@ -1030,7 +1081,7 @@ func (br *BookRepository) GetReviewByReviewId(reviewId uint) (review _entity.Sim
 	defer row.Close()
 
 	if row.Next() {
-		if err = row.Scan(&review.Id, &review.User.Id, &review.Book.Id, &review.Star, &review.Content, &review.Flag, &review.CreatedAt, &review.UpdatedAt); err != nil {
+		if err = row.Scan(&review.Id, &review.User.Id, &review.Book.Id, &review.Star, &review.Content, &review.Flag, &review.Status, &review.CreatedAt, &review.UpdatedAt); err != nil {
 			log.Println(err)
 			return
 		}
@@ -1039,14 +1090,45 @@ func (br *BookRepository) GetReviewByReviewId(reviewId uint) (review _entity.Sim
 	return
 }
 
-func (br *BookRepository) GetAllReviewsByBookId(bookId uint) (reviews []_entity.Review, err error) {
-	// prepare statement before execution
-	stmt, err := br.db.Prepare(`
-		SELECT id, user_id, star, content, created_at, updated_at
-		FROM reviews
-		WHERE book_id = ?
+// ReviewOrders are orderings of a review listing, of a book or of a work, ties go to the newest review
+var ReviewOrders = map[string]string{
+	"helpful": "helpful_count DESC, unhelpful_count ASC, r.created_at DESC",
+	"newest":  "r.created_at DESC",
+	"highest": "r.star DESC, r.created_at DESC",
+	"lowest":  "r.star ASC, r.created_at DESC",
+}
+
+func (br *BookRepository) GetAllReviewsByBookId(bookId uint, params _model.GetAllReviewsByBookIdRequest) (reviews []_entity.Review, err error) {
+	// basic query
+	query := (`
+		SELECT r.id, r.user_id, r.star, r.content, r.created_at, r.updated_at,
+			` + _requestRepository.VerifiedBorrower + ` AS verified_borrower,
+			COALESCE(SUM(v.helpful = 1), 0) AS helpful_count,
+			COALESCE(SUM(v.helpful = 0), 0) AS unhelpful_count
+		FROM reviews r
+		LEFT JOIN review_votes v
+		ON r.id = v.review_id
+		WHERE r.book_id = ?
+		  AND r.status = 'published'
+		  AND r.deleted_at IS NULL
+		GROUP BY r.id, r.user_id, r.star, r.content, r.created_at, r.updated_at
 	`)
 
+	// sort by
+	order, exist := ReviewOrders[params.SortBy]
+
+	if !exist {
+		order = ReviewOrders["newest"]
+	}
+
+	query += ` ORDER BY ` + order
+
+	// page and records
+	query += ` LIMIT ? OFFSET ?`
+
+	// prepare statement before execution
+	stmt, err := br.db.Prepare(query)
+
 	if err != nil {
 		log.Println(err)
 		return
@@ -1054,7 +1136,8 @@ func (br *BookRepository) GetAllReviewsByBookId(bookId uint) (reviews []_entity.
 
 	defer stmt.Close()
 
-	row, err := stmt.Query(bookId)
+	// execute statement
+	row, err := stmt.Query(bookId, params.Records, (params.Page-1)*params.Records)
 
 	if err != nil {
 		log.Println(err)
@@ -1066,7 +1149,7 @@ func (br *BookRepository) GetAllReviewsByBookId(bookId uint) (reviews []_entity.
 	for row.Next() {
 		review := _entity.Review{}
 
-		if err = row.Scan(&review.Id, &review.User.Id, &review.Star, &review.Content, &review.CreatedAt, &review.UpdatedAt); err != nil {
+		if err = row.Scan(&review.Id, &review.User.Id, &review.Star, &review.Content, &review.CreatedAt, &review.UpdatedAt, &review.VerifiedBorrower, &review.HelpfulCount, &review.UnhelpfulCount); err != nil {
 			log.Println(err)
 			return
 		}
@@ -1077,13 +1160,15 @@ func (br *BookRepository) GetAllReviewsByBookId(bookId uint) (reviews []_entity.
 	return
 }
 
-func (br *BookRepository) UpdateReview(updatedReview _entity.SimplifiedReview) (review _entity.SimplifiedReview, err error) {
+func (br *BookRepository) CountReviewsByStar(bookId uint) (histogram map[uint]uint, err error) {
 	// prepare statement before execution
 	stmt, err := br.db.Prepare(`
-		UPDATE reviews
-		SET star = ?, content = ?, flag = ?, updated_at = ?
-		WHERE id = ?
+		SELECT star, COUNT(id)
+		FROM reviews
+		WHERE book_id = ?
+		  AND status = 'published'
 		  AND deleted_at IS NULL
+		GROUP BY star
 	`)
 
 	if err != nil {
@@ -1093,25 +1178,39 @@ func (br *BookRepository) UpdateReview(updatedReview _entity.SimplifiedReview) (
 
 	defer stmt.Close()
 
-	_, err = stmt.Exec(updatedReview.Star, updatedReview.Content, &updatedReview.Flag, updatedReview.UpdatedAt, updatedReview.Id)
+	// execute statement
+	row, err := stmt.Query(bookId)
 
 	if err != nil {
 		log.Println(err)
 		return
 	}
 
-	review = updatedReview
+	defer row.Close()
+
+	// every star is present even without review
+	histogram = map[uint]uint{1: 0, 2: 0, 3: 0, 4: 0, 5: 0}
+
+	for row.Next() {
+		var star, count uint
+
+		if err = row.Scan(&star, &count); err != nil {
+			log.Println(err)
+			return
+		}
+
+		histogram[star] = count
+	}
 
 	return
 }
 
-func (br *BookRepository) UpdateReviewStatus(flag uint, reviewId uint) (err error) {
+func (br *BookRepository) VoteReview(newVote _entity.ReviewVote) (vote _entity.ReviewVote, err error) {
 	// prepare statement before execution
 	stmt, err := br.db.Prepare(`
-		UPDATE reviews
-		SET flag = ?
-		WHERE id = ?
-	  	  AND deleted_at IS NULL
+		INSERT INTO review_votes (review_id, user_id, helpful, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE helpful = VALUES(helpful), updated_at = VALUES(updated_at)
 	`)
 
 	if err != nil {
@@ -1121,22 +1220,25 @@ func (br *BookRepository) UpdateReviewStatus(flag uint, reviewId uint) (err erro
 
 	defer stmt.Close()
 
-	_, err = stmt.Exec(flag, reviewId)
+	// execute statement
+	_, err = stmt.Exec(newVote.ReviewId, newVote.UserId, newVote.Helpful, newVote.CreatedAt, newVote.UpdatedAt)
 
 	if err != nil {
 		log.Println(err)
 		return
 	}
 
+	vote = newVote
+
 	return
 }
 
-func (br *BookRepository) DeleteReview(reviewId uint) (err error) {
+func (br *BookRepository) RemoveReviewVote(userId uint, reviewId uint) (err error) {
 	// prepare statement before execution
 	stmt, err := br.db.Prepare(`
-		UPDATE reviews
-		SET deleted_at = ?
-		WHERE id = ?
+		DELETE FROM review_votes
+		WHERE user_id = ?
+		  AND review_id = ?
 	`)
 
 	if err != nil {
@@ -1147,7 +1249,7 @@ func (br *BookRepository) DeleteReview(reviewId uint) (err error) {
 	defer stmt.Close()
 
 	// execute statement
-	_, err = stmt.Exec(reviewId)
+	_, err = stmt.Exec(userId, reviewId)
 
 	if err != nil {
 		log.Println(err)
@@ -1157,13 +1259,12 @@ func (br *BookRepository) DeleteReview(reviewId uint) (err error) {
 	return
 }
 
-func (br *BookRepository) CountStarsByBookId(bookId uint) (averageStar float64, err error) {
+func (br *BookRepository) CountVotesByReviewId(reviewId uint) (helpful uint, unhelpful uint, err error) {
 	// prepare statement before execution
 	stmt, err := br.db.Prepare(`
-		SELECT AVG(star)
-		FROM reviews
-		WHERE book_id = ?
-		GROUP BY book_id
+		SELECT COALESCE(SUM(helpful = 1), 0), COALESCE(SUM(helpful = 0), 0)
+		FROM review_votes
+		WHERE review_id = ?
 	`)
 
 	if err != nil {
@@ -1174,7 +1275,7 @@ func (br *BookRepository) CountStarsByBookId(bookId uint) (averageStar float64,
 	defer stmt.Close()
 
 	// execute statement
-	row, err := stmt.Query(bookId)
+	row, err := stmt.Query(reviewId)
 
 	if err != nil {
 		log.Println(err)
@@ -1184,7 +1285,7 @@ func (br *BookRepository) CountStarsByBookId(bookId uint) (averageStar float64,
 	defer row.Close()
 
 	if row.Next() {
-		if err = row.Scan(&averageStar); err != nil {
+		if err = row.Scan(&helpful, &unhelpful); err != nil {
 			log.Println(err)
 			return
 		}
@@ -1193,16 +1294,13 @@ func (br *BookRepository) CountStarsByBookId(bookId uint) (averageStar float64,
 	return
 }
 
-func (br *BookRepository) GetBookByItemId(itemId uint) (book _entity.Book, err error) {
+func (br *BookRepository) UpdateReview(updatedReview _entity.SimplifiedReview) (review _entity.SimplifiedReview, err error) {
 	// prepare statement before execution
 	stmt, err := br.db.Prepare(`
-		SELECT b.id, b.title, b.publisher, b.language, b.pages, b.category, b.isbn13, b.description, b.created_at, b.updated_at
-		FROM books b
-		JOIN book_items bi
-		ON b.id = bi.book_id
-		WHERE bi.id = ?
-		  AND b.deleted_at IS NULL
-		LIMIT 1
+		UPDATE reviews
+		SET star = ?, content = ?, flag = ?, status = ?, screening_reason = ?, updated_at = ?
+		WHERE id = ?
+		  AND deleted_at IS NULL
 	`)
 
 	if err != nil {
@@ -1212,38 +1310,31 @@ func (br *BookRepository) GetBookByItemId(itemId uint) (book _entity.Book, err e
 
 	defer stmt.Close()
 
-	// execute statement
-	row, err := stmt.Query(itemId)
+	_, err = stmt.Exec(updatedReview.Star, updatedReview.Content, &updatedReview.Flag, updatedReview.Status, updatedReview.ScreeningReason, updatedReview.UpdatedAt, updatedReview.Id)
 
 	if err != nil {
 		log.Println(err)
 		return
 	}
 
-	defer row.Close()
-
-	if row.Next() {
-		if err = row.Scan(&book.Id, &book.Title, &book.Publisher, &book.Language, &book.Pages, &book.Category, &book.ISBN13, &book.Description, &book.CreatedAt, &book.UpdatedAt); err != nil {
-			log.Println(err)
-			return
-		}
-	}
+	review = updatedReview
 
 	return
 }
 
-func (br *BookRepository) GetAvailableBookByBookId(bookId uint) (bookItemId uint, err error) {
+func (br *BookRepository) UpdateReviewStatus(flag uint, reviewId uint, events ..._entity.Event) (err error) {
+	steps := []_transaction.Step{{Query: `UPDATE reviews SET flag = ? WHERE id = ? AND deleted_at IS NULL`, Args: []interface{}{flag, reviewId}}}
+
+	// review status and its events are committed together
+	return _transaction.Commit(br.db, steps, reviewId, events...)
+}
+
+func (br *BookRepository) DeleteReview(reviewId uint) (err error) {
 	// prepare statement before execution
 	stmt, err := br.db.Prepare(`
-		SELECT bi.id
-		FROM book_items bi
-		JOIN books b
-		ON bi.book_id = b.id
-		WHERE b.id = ?
-		  AND b.deleted_at IS NULL
-		  AND bi.status = 'available'
-		ORDER BY bi.id ASC
-		LIMIT 1
+		UPDATE reviews
+		SET deleted_at = ?
+		WHERE id = ?
 	`)
 
 	if err != nil {
@@ -1254,18 +1345,1210 @@ func (br *BookRepository) GetAvailableBookByBookId(bookId uint) (bookItemId uint
 	defer stmt.Close()
 
 	// execute statement
-	row, err := stmt.Query(bookId)
+	_, err = stmt.Exec(reviewId)
 
 	if err != nil {
 		log.Println(err)
 		return
 	}
 
-	if row.Next() {
-		if err = row.Scan(&bookItemId); err != nil {
-			log.Println(err)
-			return
-		}
+	return
+}
+
+func (br *BookRepository) CreateReply(newReply _entity.ReviewReply, events ..._entity.Event) (reply _entity.ReviewReply, err error) {
+	var id uint
+
+	// reply and its events are committed together, events belong to the review
+	err = _transaction.Run(br.db, func(tx *sql.Tx) (uint, error) {
+		id, err = _transaction.Insert(tx, `
+			INSERT INTO review_replies (review_id, user_id, content, status, screening_reason, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, newReply.ReviewId, newReply.User.Id, newReply.Content, newReply.Status, newReply.ScreeningReason, newReply.CreatedAt, newReply.UpdatedAt)
+
+		return newReply.ReviewId, err
+	}, events...)
+
+	if err != nil {
+		return
+	}
+
+	reply = newReply
+	reply.Id = id
+
+	return
+}
+
+func (br *BookRepository) GetReplyById(replyId uint) (reply _entity.ReviewReply, err error) {
+	// prepare statement before execution
+	stmt, err := br.db.Prepare(`
+		SELECT id, review_id, user_id, content, status, screening_reason, created_at, updated_at
+		FROM review_replies
+		WHERE id = ?
+		  AND deleted_at IS NULL
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(replyId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		if err = row.Scan(&reply.Id, &reply.ReviewId, &reply.User.Id, &reply.Content, &reply.Status, &reply.ScreeningReason, &reply.CreatedAt, &reply.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
+func (br *BookRepository) GetRepliesByReviewId(reviewId uint) (replies []_entity.ReviewReply, err error) {
+	// prepare statement before execution
+	stmt, err := br.db.Prepare(`
+		SELECT id, review_id, user_id, content, status, created_at, updated_at
+		FROM review_replies
+		WHERE review_id = ?
+		  AND status = 'published'
+		  AND deleted_at IS NULL
+		ORDER BY created_at
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(reviewId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		reply := _entity.ReviewReply{}
+
+		if err = row.Scan(&reply.Id, &reply.ReviewId, &reply.User.Id, &reply.Content, &reply.Status, &reply.CreatedAt, &reply.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+
+		replies = append(replies, reply)
+	}
+
+	return
+}
+
+func (br *BookRepository) UpdateReply(updatedReply _entity.ReviewReply) (reply _entity.ReviewReply, err error) {
+	// prepare statement before execution
+	stmt, err := br.db.Prepare(`
+		UPDATE review_replies
+		SET content = ?, status = ?, screening_reason = ?, updated_at = ?
+		WHERE id = ?
+		  AND deleted_at IS NULL
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(updatedReply.Content, updatedReply.Status, updatedReply.ScreeningReason, updatedReply.UpdatedAt, updatedReply.Id)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	reply = updatedReply
+
+	return
+}
+
+func (br *BookRepository) DeleteReply(replyId uint) (err error) {
+	// prepare statement before execution
+	stmt, err := br.db.Prepare(`
+		UPDATE review_replies
+		SET deleted_at = ?
+		WHERE id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(time.Now(), replyId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+func (br *BookRepository) CountStarsByBookId(bookId uint) (averageStar float64, err error) {
+	// prepare statement before execution
+	stmt, err := br.db.Prepare(`
+		SELECT AVG(star)
+		FROM reviews
+		WHERE book_id = ?
+		  AND status = 'published'
+		  AND deleted_at IS NULL
+		GROUP BY book_id
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(bookId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		if err = row.Scan(&averageStar); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
+func (br *BookRepository) GetBookByItemId(itemId uint) (book _entity.Book, err error) {
+	// prepare statement before execution
+	stmt, err := br.db.Prepare(`
+		SELECT b.id, b.title, b.publisher, b.language, b.pages, b.category, b.isbn13, b.description, b.created_at, b.updated_at
+		FROM books b
+		JOIN book_items bi
+		ON b.id = bi.book_id
+		WHERE bi.id = ?
+		  AND b.deleted_at IS NULL
+		LIMIT 1
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(itemId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		if err = row.Scan(&book.Id, &book.Title, &book.Publisher, &book.Language, &book.Pages, &book.Category, &book.ISBN13, &book.Description, &book.CreatedAt, &book.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
+func (br *BookRepository) GetAvailableBookByBookId(bookId uint) (bookItemId uint, err error) {
+	// prepare statement before execution
+	stmt, err := br.db.Prepare(`
+		SELECT bi.id
+		FROM book_items bi
+		JOIN books b
+		ON bi.book_id = b.id
+		WHERE b.id = ?
+		  AND b.deleted_at IS NULL
+		  AND bi.status = 'available'
+		ORDER BY bi.id ASC
+		LIMIT 1
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(bookId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	if row.Next() {
+		if err = row.Scan(&bookItemId); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
+// GetBooksByTitleKey shortlists books whose stored title key has the prefix and a length in range
+func (br *BookRepository) GetBooksByTitleKey(prefix string, minLength int, maxLength int) (books []_entity.Book, err error) {
+	// prepare statement before execution
+	stmt, err := br.db.Prepare(`
+		SELECT id, title
+		FROM books
+		WHERE deleted_at IS NULL
+		  AND title_key LIKE ?
+		  AND CHAR_LENGTH(title_key) BETWEEN ? AND ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(prefix+"%", minLength, maxLength)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		book := _entity.Book{}
+
+		if err = row.Scan(&book.Id, &book.Title); err != nil {
+			log.Println(err)
+			return
+		}
+
+		books = append(books, book)
+	}
+
+	return
+}
+
+// GetAuthorsByNameKey shortlists authors whose stored name key has the prefix and a length in range
+func (br *BookRepository) GetAuthorsByNameKey(prefix string, minLength int, maxLength int) (authors []_entity.Author, err error) {
+	// prepare statement before execution
+	stmt, err := br.db.Prepare(`
+		SELECT id, name
+		FROM authors
+		WHERE name_key LIKE ?
+		  AND CHAR_LENGTH(name_key) BETWEEN ? AND ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(prefix+"%", minLength, maxLength)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		author := _entity.Author{}
+
+		if err = row.Scan(&author.Id, &author.Name); err != nil {
+			log.Println(err)
+			return
+		}
+
+		authors = append(authors, author)
+	}
+
+	return
+}
+
+// BackfillMatchKeys stores title and name key of books, authors and acquisition candidates created before the keys existed,
+// and ISBN key of books stored with hyphen or as ISBN-10
+func (br *BookRepository) BackfillMatchKeys() (err error) {
+	steps := []struct {
+		query  string
+		update string
+		key    func(s string) string
+	}{
+		{`SELECT id, title FROM books WHERE title_key IS NULL`, `UPDATE books SET title_key = ? WHERE id = ?`, func(s string) string {
+			return _helper.MatchKey(_helper.NormalizeTitle(s))
+		}},
+		{`SELECT id, name FROM authors WHERE name_key IS NULL`, `UPDATE authors SET name_key = ? WHERE id = ?`, func(s string) string {
+			return _helper.MatchKey(_helper.NormalizeName(s))
+		}},
+		{`SELECT id, title FROM acquisition_candidates WHERE title_key IS NULL`, `UPDATE acquisition_candidates SET title_key = ? WHERE id = ?`, func(s string) string {
+			return _helper.MatchKey(_helper.NormalizeTitle(s))
+		}},
+		{`SELECT id, isbn13 FROM books WHERE isbn13_key IS NULL AND isbn13 <> ''`, `UPDATE books SET isbn13_key = NULLIF(?, '') WHERE id = ?`, isbnKey},
+	}
+
+	for _, step := range steps {
+		// prepare statement before execution
+		stmt, err := br.db.Prepare(step.query)
+
+		if err != nil {
+			log.Println(err)
+			return err
+		}
+
+		// execute statement, rows are read first so update does not run while the result is open
+		row, err := stmt.Query()
+
+		if err != nil {
+			log.Println(err)
+			stmt.Close()
+			return err
+		}
+
+		keys := map[uint]string{}
+
+		for row.Next() {
+			id, text := uint(0), ""
+
+			if err = row.Scan(&id, &text); err != nil {
+				log.Println(err)
+				break
+			}
+
+			keys[id] = step.key(text)
+		}
+
+		row.Close()
+		stmt.Close()
+
+		if err != nil {
+			return err
+		}
+
+		updateStmt, err := br.db.Prepare(step.update)
+
+		if err != nil {
+			log.Println(err)
+			return err
+		}
+
+		for id, key := range keys {
+			if _, err = updateStmt.Exec(key, id); duplicateISBN(err) == ErrDuplicateISBN {
+				// legacy book sharing ISBN with another one keeps no key until the books are merged
+				log.Println("book", id, "shares isbn", key, "with another book")
+				err = nil
+			} else if err != nil {
+				log.Println(err)
+				break
+			}
+		}
+
+		updateStmt.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// statements moving everything that belongs to merged book, executed in order,
+// row already attached to target book, by the same author, user, list or locale, is dropped instead
+var mergeStatements = []string{
+	`UPDATE book_author_junction
+	SET book_id = ?
+	WHERE book_id = ?
+	  AND deleted_at IS NULL
+	  AND author_id NOT IN (
+		SELECT author_id FROM (SELECT author_id FROM book_author_junction WHERE book_id = ? AND deleted_at IS NULL) t
+	  )`,
+	`UPDATE favorites
+	SET book_id = ?
+	WHERE book_id = ?
+	  AND deleted_at IS NULL
+	  AND user_id NOT IN (
+		SELECT user_id FROM (SELECT user_id FROM favorites WHERE book_id = ? AND deleted_at IS NULL) t
+	  )`,
+	`UPDATE reviews
+	SET book_id = ?
+	WHERE book_id = ?
+	  AND deleted_at IS NULL
+	  AND user_id NOT IN (
+		SELECT user_id FROM (SELECT user_id FROM reviews WHERE book_id = ? AND deleted_at IS NULL) t
+	  )`,
+	`INSERT INTO book_translations (book_id, locale, title, description, created_at, updated_at)
+	SELECT ?, locale, title, description, created_at, updated_at
+	FROM book_translations
+	WHERE book_id = ?
+	  AND locale NOT IN (
+		SELECT locale FROM (SELECT locale FROM book_translations WHERE book_id = ?) t
+	  )`,
+	`UPDATE reading_list_items
+	SET book_id = ?
+	WHERE book_id = ?
+	  AND list_id NOT IN (
+		SELECT list_id FROM (SELECT list_id FROM reading_list_items WHERE book_id = ?) t
+	  )`,
+	// subjects decide call number and primary subject, so they move only to a book without classification
+	`UPDATE book_subjects
+	SET book_id = ?
+	WHERE book_id = ?
+	  AND NOT EXISTS (
+		SELECT 1 FROM (SELECT book_id FROM book_subjects WHERE book_id = ?) t
+	  )`,
+	`UPDATE books t
+	JOIN books s
+	ON t.id = ?
+	  AND s.id = ?
+	SET t.call_number = s.call_number
+	WHERE t.id = ?
+	  AND t.call_number IS NULL`,
+	`UPDATE books t
+	JOIN books s
+	ON t.id = ?
+	  AND s.id = ?
+	SET t.work_id = s.work_id
+	WHERE t.id = ?
+	  AND t.work_id IS NULL`,
+	`UPDATE book_covers
+	SET book_id = ?
+	WHERE book_id = ?
+	  AND NOT EXISTS (
+		SELECT 1 FROM (SELECT book_id FROM book_covers WHERE book_id = ?) t
+	  )`,
+	// waiting holds keep their place in the queue of target book
+	`UPDATE requests
+	SET book_id = ?
+	WHERE book_id = ?
+	  AND status_id = 1
+	  AND cancel_at IS NULL
+	  AND user_id NOT IN (
+		SELECT user_id FROM (SELECT user_id FROM requests WHERE book_id = ? AND status_id = 1 AND cancel_at IS NULL) t
+	  )`,
+}
+
+// statements moving rows that are never duplicated on target book
+var mergeMoveStatements = []string{
+	// copies, and the requests made on them, follow the target book
+	`UPDATE book_items SET book_id = ? WHERE book_id = ?`,
+	`UPDATE acquisition_candidates SET book_id = ? WHERE book_id = ?`,
+}
+
+// statements removing rows of merged book that have no history to keep
+var mergeDeleteStatements = []string{
+	`DELETE FROM book_translations WHERE book_id = ?`,
+	`DELETE FROM book_search_index WHERE book_id = ?`,
+	`DELETE FROM reading_list_items WHERE book_id = ?`,
+}
+
+// statements dropping what is left on merged book
+var mergeCleanupStatements = []string{
+	`UPDATE book_author_junction SET deleted_at = ? WHERE book_id = ? AND deleted_at IS NULL`,
+	`UPDATE favorites SET deleted_at = ? WHERE book_id = ? AND deleted_at IS NULL`,
+	`UPDATE reviews SET deleted_at = ? WHERE book_id = ? AND deleted_at IS NULL`,
+	// hold of a user already waiting for target book is cancelled
+	`UPDATE requests SET status_id = 3, cancel_at = ? WHERE book_id = ? AND status_id = 1 AND cancel_at IS NULL`,
+	`UPDATE books SET deleted_at = ? WHERE id = ?`,
+}
+
+func (br *BookRepository) MergeBooks(sourceId uint, targetId uint, events ..._entity.Event) (err error) {
+	now := time.Now()
+
+	steps := []_transaction.Step{}
+
+	for _, query := range mergeStatements {
+		steps = append(steps, _transaction.Step{Query: query, Args: []interface{}{targetId, sourceId, targetId}})
+	}
+
+	for _, query := range mergeMoveStatements {
+		steps = append(steps, _transaction.Step{Query: query, Args: []interface{}{targetId, sourceId}})
+	}
+
+	for _, query := range mergeDeleteStatements {
+		steps = append(steps, _transaction.Step{Query: query, Args: []interface{}{sourceId}})
+	}
+
+	for _, query := range mergeCleanupStatements {
+		steps = append(steps, _transaction.Step{Query: query, Args: []interface{}{now, sourceId}})
+	}
+
+	// merge and its events are applied completely or not at all
+	return _transaction.Commit(br.db, steps, targetId, events...)
+}
+
+func (br *BookRepository) ExportBooks(params _model.ExportBooksRequest, handle func(book _entity.ExportedBook) error) (err error) {
+	// basic query, a book comes in as many rows as its authors
+	query := (`
+		SELECT b.id, b.title, b.publisher, b.language, b.pages, b.category, b.isbn13, b.description, b.created_at, b.updated_at,
+		       (SELECT COUNT(*) FROM book_items bi WHERE bi.book_id = b.id),
+		       (SELECT COUNT(*) FROM book_items bi WHERE bi.book_id = b.id AND bi.status = 'available'),
+		       COALESCE(a.id, 0), COALESCE(a.name, '')
+		FROM books b
+		LEFT JOIN book_author_junction ba
+		ON b.id = ba.book_id
+		AND ba.deleted_at IS NULL
+		LEFT JOIN authors a
+		ON ba.author_id = a.id
+		WHERE b.deleted_at IS NULL
+	`)
+
+	args := []interface{}{}
+
+	if params.Category != "" {
+		query += ` AND b.category = ?`
+		args = append(args, params.Category)
+	}
+
+	if !params.From.IsZero() {
+		query += ` AND b.updated_at >= ?`
+		args = append(args, params.From)
+	}
+
+	if !params.To.IsZero() {
+		query += ` AND b.updated_at < ?`
+		args = append(args, params.To)
+	}
+
+	query += ` ORDER BY b.id ASC, ba.id ASC`
+
+	// prepare statement before execution
+	stmt, err := br.db.Prepare(query)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(args...)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	// rows are handed over one book at a time instead of collected
+	current := _entity.ExportedBook{}
+
+	for row.Next() {
+		book := _entity.ExportedBook{}
+		author := _entity.Author{}
+
+		if err = row.Scan(&book.Id, &book.Title, &book.Publisher, &book.Language, &book.Pages, &book.Category, &book.ISBN13, &book.Description, &book.CreatedAt, &book.UpdatedAt, &book.Quantity, &book.Available, &author.Id, &author.Name); err != nil {
+			log.Println(err)
+			return
+		}
+
+		if book.Id != current.Id {
+			if current.Id != 0 {
+				if err = handle(current); err != nil {
+					log.Println(err)
+					return
+				}
+			}
+
+			current = book
+		}
+
+		if author.Id != 0 {
+			current.Author = append(current.Author, author)
+		}
+	}
+
+	if err = row.Err(); err != nil {
+		log.Println(err)
+		return
+	}
+
+	if current.Id != 0 {
+		if err = handle(current); err != nil {
+			log.Println(err)
+		}
+	}
+
+	return
+}
+
+func (br *BookRepository) GetAllCategories() (categories []string, err error) {
+	// prepare statement before execution
+	stmt, err := br.db.Prepare(`
+		SELECT DISTINCT category
+		FROM books
+		WHERE deleted_at IS NULL
+		ORDER BY category ASC
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		category := ""
+
+		if err = row.Scan(&category); err != nil {
+			log.Println(err)
+			return
+		}
+
+		categories = append(categories, category)
+	}
+
+	return
+}
+
+func (br *BookRepository) GetEarliestDatestamp() (datestamp time.Time, err error) {
+	// prepare statement before execution
+	stmt, err := br.db.Prepare(`
+		SELECT COALESCE(MIN(created_at), NOW())
+		FROM books
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		if err = row.Scan(&datestamp); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
+func (br *BookRepository) HarvestBooks(params _model.HarvestBooksRequest) (books []_entity.HarvestedBook, err error) {
+	// basic query, deleted book is kept so that harvester learns about the deletion
+	query := (`
+		SELECT id, title, publisher, language, pages, category, isbn13, description, created_at, updated_at,
+		       (SELECT COUNT(*) FROM book_items bi WHERE bi.book_id = b.id),
+		       (SELECT COUNT(*) FROM book_items bi WHERE bi.book_id = b.id AND bi.status = 'available'),
+		       deleted_at IS NOT NULL, COALESCE(deleted_at, updated_at) AS datestamp
+		FROM books b
+		WHERE id > ?
+	`)
+
+	args := []interface{}{params.AfterId}
+
+	if params.Id != 0 {
+		query += ` AND id = ?`
+		args = append(args, params.Id)
+	}
+
+	if len(params.Categories) > 0 {
+		query += ` AND category IN (?` + strings.Repeat(`, ?`, len(params.Categories)-1) + `)`
+
+		for _, category := range params.Categories {
+			args = append(args, category)
+		}
+	}
+
+	if !params.From.IsZero() {
+		query += ` AND COALESCE(deleted_at, updated_at) >= ?`
+		args = append(args, params.From)
+	}
+
+	if !params.Until.IsZero() {
+		query += ` AND COALESCE(deleted_at, updated_at) < ?`
+		args = append(args, params.Until)
+	}
+
+	query += ` ORDER BY id ASC LIMIT ?`
+	args = append(args, params.Limit)
+
+	// prepare statement before execution
+	stmt, err := br.db.Prepare(query)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(args...)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		book := _entity.HarvestedBook{}
+
+		if err = row.Scan(&book.Id, &book.Title, &book.Publisher, &book.Language, &book.Pages, &book.Category, &book.ISBN13, &book.Description, &book.CreatedAt, &book.UpdatedAt, &book.Quantity, &book.Available, &book.Deleted, &book.Datestamp); err != nil {
+			log.Println(err)
+			return
+		}
+
+		books = append(books, book)
+	}
+
+	return
+}
+
+func (br *BookRepository) GetCover(bookId uint) (cover _entity.BookCover, err error) {
+	// prepare statement before execution
+	stmt, err := br.db.Prepare(`
+		SELECT book_id, content_type, size, width, height, etag, COALESCE(blob_prefix, CONCAT('covers/', book_id)), created_at, updated_at
+		FROM book_covers
+		WHERE book_id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(bookId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		if err = row.Scan(&cover.BookId, &cover.ContentType, &cover.Size, &cover.Width, &cover.Height, &cover.ETag, &cover.BlobPrefix, &cover.CreatedAt, &cover.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
+func (br *BookRepository) SaveCover(newCover _entity.BookCover) (cover _entity.BookCover, err error) {
+	// prepare statement before execution, one cover per book
+	stmt, err := br.db.Prepare(`
+		INSERT INTO book_covers (book_id, content_type, size, width, height, etag, blob_prefix, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE content_type = VALUES(content_type), size = VALUES(size), width = VALUES(width),
+		                        height = VALUES(height), etag = VALUES(etag), blob_prefix = VALUES(blob_prefix), updated_at = VALUES(updated_at)
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(newCover.BookId, newCover.ContentType, newCover.Size, newCover.Width, newCover.Height, newCover.ETag, newCover.BlobPrefix, newCover.CreatedAt, newCover.UpdatedAt)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	return br.GetCover(newCover.BookId)
+}
+
+func (br *BookRepository) DeleteCover(bookId uint) (err error) {
+	// prepare statement before execution
+	stmt, err := br.db.Prepare(`
+		DELETE FROM book_covers
+		WHERE book_id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(bookId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+func (br *BookRepository) GetBookTranslations(bookId uint) (translations []_entity.BookTranslation, err error) {
+	// prepare statement before execution
+	stmt, err := br.db.Prepare(`
+		SELECT book_id, locale, title, description, created_at, updated_at
+		FROM book_translations
+		WHERE book_id = ?
+		ORDER BY locale
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(bookId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		translation := _entity.BookTranslation{}
+
+		if err = row.Scan(&translation.BookId, &translation.Locale, &translation.Title, &translation.Description, &translation.CreatedAt, &translation.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+
+		translations = append(translations, translation)
+	}
+
+	return
+}
+
+func (br *BookRepository) SaveBookTranslation(newTranslation _entity.BookTranslation, events ..._entity.Event) (translation _entity.BookTranslation, err error) {
+	// one translation per locale
+	steps := []_transaction.Step{{
+		Query: `
+			INSERT INTO book_translations (book_id, locale, title, description, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE title = VALUES(title), description = VALUES(description), updated_at = VALUES(updated_at)
+		`,
+		Args: []interface{}{newTranslation.BookId, newTranslation.Locale, newTranslation.Title, newTranslation.Description, newTranslation.CreatedAt, newTranslation.UpdatedAt},
+	}}
+
+	// translation and its events are committed together
+	if err = _transaction.Commit(br.db, steps, newTranslation.BookId, events...); err != nil {
+		return
+	}
+
+	translations, err := br.GetBookTranslations(newTranslation.BookId)
+
+	for _, saved := range translations {
+		if saved.Locale == newTranslation.Locale {
+			translation = saved
+		}
+	}
+
+	return
+}
+
+func (br *BookRepository) DeleteBookTranslation(bookId uint, locale string, events ..._entity.Event) (err error) {
+	steps := []_transaction.Step{{Query: `DELETE FROM book_translations WHERE book_id = ? AND locale = ?`, Args: []interface{}{bookId, locale}}}
+
+	// translation and its events are committed together
+	return _transaction.Commit(br.db, steps, bookId, events...)
+}
+
+// authors are indexed with every language of the book, so search by author works in any language
+const indexedAuthors = `(
+	SELECT GROUP_CONCAT(a.name SEPARATOR ' ')
+	FROM authors a
+	JOIN book_author_junction ba
+	ON a.id = ba.author_id
+	WHERE ba.book_id = b.id
+	  AND ba.deleted_at IS NULL
+)`
+
+// indexBooks writes one row per language of every book matched by condition on b, original metadata has empty locale,
+// both languages are written by a single statement so condition sees the index as it was before
+func indexBooks(condition string) string {
+	return `INSERT INTO book_search_index (book_id, locale, content)
+	SELECT b.id, '', CONCAT_WS(' ', b.title, b.description, ` + indexedAuthors + `)
+	FROM books b
+	WHERE b.deleted_at IS NULL
+	  AND ` + condition + `
+	UNION ALL
+	SELECT b.id, t.locale, CONCAT_WS(' ', t.title, t.description, ` + indexedAuthors + `)
+	FROM book_translations t
+	JOIN books b
+	ON t.book_id = b.id
+	WHERE b.deleted_at IS NULL
+	  AND ` + condition
+}
+
+// RebuildSearchIndex writes index rows of the book again, book id 0 rebuilds the whole catalog
+func (br *BookRepository) RebuildSearchIndex(bookId uint) (err error) {
+	// begin transaction, search never sees a half built index
+	tx, err := br.db.Begin()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer tx.Rollback()
+
+	steps := []_transaction.Step{
+		{Query: `DELETE FROM book_search_index WHERE (? = 0 OR book_id = ?)`, Args: []interface{}{bookId, bookId}},
+		{Query: indexBooks(`(? = 0 OR b.id = ?)`), Args: []interface{}{bookId, bookId, bookId, bookId}},
+	}
+
+	if err = _transaction.Exec(tx, steps); err != nil {
+		return
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+// BackfillSearchIndex indexes books which have no index row yet, such as books created before the index
+func (br *BookRepository) BackfillSearchIndex() (err error) {
+	// prepare statement before execution
+	stmt, err := br.db.Prepare(indexBooks(`NOT EXISTS (SELECT 1 FROM book_search_index i WHERE i.book_id = b.id)`))
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+// searchTerms turns keyword into boolean mode terms, every word also matches as prefix of longer word
+func searchTerms(keyword string) string {
+	terms := []string{}
+
+	for _, word := range strings.FieldsFunc(keyword, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		terms = append(terms, word+"*")
+	}
+
+	return strings.Join(terms, " ")
+}
+
+// likeEscaper keeps wildcards typed by user literal in LIKE pattern, backslash is the default escape character
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// hasSearchMatch tells whether fulltext index finds the terms anywhere, lookup uses the index instead of scanning it
+func (br *BookRepository) hasSearchMatch(terms string) (matched bool, err error) {
+	// prepare statement before execution
+	stmt, err := br.db.Prepare(`
+		SELECT 1
+		FROM book_search_index
+		WHERE MATCH(content) AGAINST (? IN BOOLEAN MODE)
+		LIMIT 1
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(terms)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	matched = row.Next()
+
+	return
+}
+
+// sorting criteria of search, books of the same value keep their relevance order
+var searchOrders = map[string]string{
+	"star":   `(SELECT COALESCE(AVG(r.star), 0) FROM reviews r WHERE r.book_id = b.id AND r.status = 'published' AND r.deleted_at IS NULL)`,
+	"review": `(SELECT COUNT(*) FROM reviews r WHERE r.book_id = b.id AND r.status = 'published' AND r.deleted_at IS NULL)`,
+	"read":   `(SELECT COUNT(*) FROM requests rq JOIN book_items bi ON rq.book_item_id = bi.id WHERE bi.book_id = b.id AND rq.status_id IN (8, 9))`,
+}
+
+// SearchBooks ranks books by the best matching language, locale limits search to one language
+// including books originally written in it, part of a word or word shorter than fulltext token
+// is found only when no whole word matches
+func (br *BookRepository) SearchBooks(params _model.GetAllBooksRequest) (books []_entity.Book, err error) {
+	terms := searchTerms(params.Keyword)
+
+	matched, err := br.hasSearchMatch(terms)
+
+	if err != nil {
+		return
+	}
+
+	// LIKE scans the whole index, so it is the fallback and never combined with MATCH
+	condition, pattern := `MATCH(i.content) AGAINST (? IN BOOLEAN MODE)`, interface{}(terms)
+
+	if !matched {
+		condition, pattern = `i.content LIKE ?`, "%"+likeEscaper.Replace(strings.TrimSpace(params.Keyword))+"%"
+	}
+
+	// basic query
+	query := (`
+		SELECT b.id, b.title, b.publisher, b.language, b.pages, b.category, b.isbn13, b.description, b.created_at, b.updated_at,
+		       MAX(MATCH(i.content) AGAINST (? IN BOOLEAN MODE)) AS score
+		FROM book_search_index i
+		JOIN books b
+		ON i.book_id = b.id
+		WHERE b.deleted_at IS NULL
+		  AND ` + condition + `
+	`)
+
+	args := []interface{}{terms, pattern}
+
+	if params.Category != "*" {
+		query += ` AND b.category = ?`
+		args = append(args, params.Category)
+	}
+
+	// region of the locale is optional, pt finds pt-br as well
+	if params.Locale != "" {
+		query += ` AND (i.locale = ? OR i.locale LIKE ? OR (i.locale = '' AND b.language = ?))`
+		args = append(args, params.Locale, params.Locale+"-%", params.Language)
+	}
+
+	query += ` GROUP BY b.id ORDER BY `
+
+	// sort by, relevance when no criteria is given
+	if order, exist := searchOrders[params.SortBy]; exist {
+		mode := ` DESC`
+
+		if params.SortMode == "asc" {
+			mode = ` ASC`
+		}
+
+		query += order + mode + `, `
+	}
+
+	query += `score DESC, b.id`
+
+	// page and records
+	query += ` LIMIT ? OFFSET ?`
+	args = append(args, params.Records, (params.Page-1)*params.Records)
+
+	// prepare statement before execution
+	stmt, err := br.db.Prepare(query)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(args...)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		book := _entity.Book{}
+		score := 0.0
+
+		if err = row.Scan(&book.Id, &book.Title, &book.Publisher, &book.Language, &book.Pages, &book.Category, &book.ISBN13, &book.Description, &book.CreatedAt, &book.UpdatedAt, &score); err != nil {
+			log.Println(err)
+			return
+		}
+
+		books = append(books, book)
 	}
 
 	return
@@ -0,0 +1,77 @@
+package book
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// every table referring to a book, and every column of books taken over from merged book
+var mergedReferences = []string{
+	"book_author_junction",
+	"favorites",
+	"reviews",
+	"book_translations",
+	"book_items",
+	"acquisition_candidates",
+	"book_search_index",
+	"reading_list_items",
+	"book_subjects",
+	"book_covers",
+	"requests",
+	"call_number",
+	"work_id",
+}
+
+func TestMergeBooksHandlesEveryReference(t *testing.T) {
+	statements := strings.Join(append(append(append(append([]string{}, mergeStatements...), mergeMoveStatements...), mergeDeleteStatements...), mergeCleanupStatements...), "\n")
+
+	for _, reference := range mergedReferences {
+		if !strings.Contains(statements, reference) {
+			t.Errorf("MergeBooks leaves %s of merged book behind", reference)
+		}
+	}
+}
+
+func TestMergedReferencesListEveryBookTable(t *testing.T) {
+	files, err := filepath.Glob("../*/*.go")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	insert := regexp.MustCompile(`INSERT INTO (\w+) \(([^)]*)\)`)
+	bookId := regexp.MustCompile(`\bbook_id\b`)
+
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+
+		source, err := os.ReadFile(file)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, match := range insert.FindAllStringSubmatch(string(source), -1) {
+			table, columns := match[1], match[2]
+
+			if !bookId.MatchString(columns) {
+				continue
+			}
+
+			listed := false
+
+			for _, reference := range mergedReferences {
+				listed = listed || reference == table
+			}
+
+			if !listed {
+				t.Errorf("table %s in %s has book_id but is not in mergedReferences", table, file)
+			}
+		}
+	}
+}
@@ -3,13 +3,15 @@ package book
 import (
 	_entity "plain-go/public-library/entity"
 	_model "plain-go/public-library/model"
+	"time"
 )
 
 type Book interface {
 	GetBookByTitle(title string) (book _entity.Book, err error)
+	GetBookByISBN(isbn13 string) (book _entity.Book, err error)
 	GetAuthorByName(name string) (author _entity.Author, err error)
 	GetAllAuthors() (authors []_entity.Author, err error)
-	CreateNewBook(newBook _entity.Book) (book _entity.Book, err error)
+	CreateNewBook(newBook _entity.Book, events ..._entity.Event) (book _entity.Book, err error)
 	CreateNewAuthor(newAuthor _entity.Author) (author _entity.Author, err error)
 	CreateBookAuthorJunction(book _entity.Book, author _entity.Author) (err error)
 	CreateBookItem(book _entity.Book) (err error)
@@ -17,15 +19,15 @@ type Book interface {
 	GetBookAuthors(bookId uint) (authors []_entity.Author, err error)
 	GetBookById(bookId uint) (book _entity.Book, err error)
 	CountBookById(bookId uint) (count uint, err error)
-	UpdateBook(updatedBook _entity.Book) (book _entity.Book, err error)
+	UpdateBook(updatedBook _entity.Book, events ..._entity.Event) (book _entity.Book, err error)
 	DeleteBookAuthorJunction(book _entity.Book, author _entity.Author) (err error)
-	DeleteBook(bookId uint) (err error)
+	DeleteBook(bookId uint, events ..._entity.Event) (err error)
 	AddBookToFavorite(userId uint, bookId uint) (favorite _entity.Favorite, err error)
 	RemoveBookFromFavorite(userId uint, bookId uint) (err error)
 	GetAllFavoritesByUserId(userId uint) (favorites []_entity.Favorite, err error)
 	CountFavoritesByBookId(bookId uint) (count uint, err error)
 	GetAllWishes() (wishses []_entity.SimplifiedWish, err error)
-	AddBookToWishlist(userId uint, newWish _entity.Wish) (wish _entity.Wish, err error)
+	AddBookToWishlist(userId uint, newWish _entity.Wish, events ..._entity.Event) (wish _entity.Wish, err error)
 	RemoveBookFromWishlist(wishId uint) (err error)
 	GetWishesByUserId(userId uint) (wishes []_entity.Wish, err error)
 	GetWishById(userId uint, wishId uint) (wish _entity.Wish, err error)
@@ -34,13 +36,40 @@ type Book interface {
 	UpdateWish(updatedWish _entity.Wish) (wish _entity.Wish, err error)
 	DeleteWishAuthorJunction(wish _entity.Wish, author _entity.Author) (err error)
 	GetAllReviews() (reviews []_entity.SimplifiedReview, err error)
-	CreateReview(newReview _entity.SimplifiedReview) (review _entity.SimplifiedReview, err error)
+	CreateReview(newReview _entity.SimplifiedReview, events ..._entity.Event) (review _entity.SimplifiedReview, err error)
 	GetReviewByReviewId(reviewId uint) (review _entity.SimplifiedReview, err error)
-	GetAllReviewsByBookId(bookId uint) (reviews []_entity.Review, err error)
+	GetReviewByUserId(userId uint, bookId uint) (review _entity.SimplifiedReview, err error)
+	GetAllReviewsByBookId(bookId uint, params _model.GetAllReviewsByBookIdRequest) (reviews []_entity.Review, err error)
+	CountReviewsByStar(bookId uint) (histogram map[uint]uint, err error)
+	VoteReview(newVote _entity.ReviewVote) (vote _entity.ReviewVote, err error)
+	RemoveReviewVote(userId uint, reviewId uint) (err error)
+	CountVotesByReviewId(reviewId uint) (helpful uint, unhelpful uint, err error)
 	UpdateReview(updatedReview _entity.SimplifiedReview) (review _entity.SimplifiedReview, err error)
-	UpdateReviewStatus(flag uint, reviewId uint) (err error)
+	UpdateReviewStatus(flag uint, reviewId uint, events ..._entity.Event) (err error)
 	DeleteReview(reviewId uint) (err error)
+	CreateReply(newReply _entity.ReviewReply, events ..._entity.Event) (reply _entity.ReviewReply, err error)
+	GetReplyById(replyId uint) (reply _entity.ReviewReply, err error)
+	GetRepliesByReviewId(reviewId uint) (replies []_entity.ReviewReply, err error)
+	UpdateReply(updatedReply _entity.ReviewReply) (reply _entity.ReviewReply, err error)
+	DeleteReply(replyId uint) (err error)
 	CountStarsByBookId(bookId uint) (averageStar float64, err error)
 	GetBookByItemId(itemId uint) (book _entity.Book, err error)
 	GetAvailableBookByBookId(bookId uint) (bookItemId uint, err error)
+	GetBooksByTitleKey(prefix string, minLength int, maxLength int) (books []_entity.Book, err error)
+	GetAuthorsByNameKey(prefix string, minLength int, maxLength int) (authors []_entity.Author, err error)
+	BackfillMatchKeys() (err error)
+	MergeBooks(sourceId uint, targetId uint, events ..._entity.Event) (err error)
+	ExportBooks(params _model.ExportBooksRequest, handle func(book _entity.ExportedBook) error) (err error)
+	GetAllCategories() (categories []string, err error)
+	GetEarliestDatestamp() (datestamp time.Time, err error)
+	HarvestBooks(params _model.HarvestBooksRequest) (books []_entity.HarvestedBook, err error)
+	GetCover(bookId uint) (cover _entity.BookCover, err error)
+	SaveCover(newCover _entity.BookCover) (cover _entity.BookCover, err error)
+	DeleteCover(bookId uint) (err error)
+	GetBookTranslations(bookId uint) (translations []_entity.BookTranslation, err error)
+	SaveBookTranslation(newTranslation _entity.BookTranslation, events ..._entity.Event) (translation _entity.BookTranslation, err error)
+	DeleteBookTranslation(bookId uint, locale string, events ..._entity.Event) (err error)
+	RebuildSearchIndex(bookId uint) (err error)
+	BackfillSearchIndex() (err error)
+	SearchBooks(params _model.GetAllBooksRequest) (books []_entity.Book, err error)
 }
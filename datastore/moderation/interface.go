@@ -0,0 +1,15 @@
+package moderation
+
+import (
+	_entity "plain-go/public-library/entity"
+)
+
+type Moderation interface {
+	CreateReport(newReport _entity.ReviewReport, events ..._entity.Event) (report _entity.ReviewReport, err error)
+	GetOpenReportByUserId(userId uint, reviewId uint, replyId uint) (report _entity.ReviewReport, err error)
+	GetOpenReportsByReviewId(reviewId uint) (reports []_entity.ReviewReport, err error)
+	GetModerationQueue() (items []_entity.ModerationQueueItem, err error)
+	GetOpenReportsByReplyId(replyId uint) (reports []_entity.ReviewReport, err error)
+	GetReplyModerationQueue() (items []_entity.ReplyModerationQueueItem, err error)
+	CreateAction(newAction _entity.ModerationAction, events ..._entity.Event) (action _entity.ModerationAction, err error)
+}
@@ -0,0 +1,309 @@
+package moderation
+
+import (
+	"database/sql"
+	"log"
+	_transaction "plain-go/public-library/datastore/transaction"
+	_entity "plain-go/public-library/entity"
+)
+
+type ModerationRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *ModerationRepository {
+	return &ModerationRepository{db: db}
+}
+
+// review changes applied by each moderation action, moderation works on status and leaves flag alone
+var actionStatements = map[string]string{
+	"approve": `UPDATE reviews SET status = 'published', screening_reason = '', updated_at = ? WHERE id = ? AND deleted_at IS NULL`,
+	"hide":    `UPDATE reviews SET status = 'hidden', updated_at = ? WHERE id = ? AND deleted_at IS NULL`,
+	"delete":  `UPDATE reviews SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`,
+	"warn":    `UPDATE reviews SET updated_at = ? WHERE id = ? AND deleted_at IS NULL`,
+}
+
+// reply changes applied by each moderation action
+var replyActionStatements = map[string]string{
+	"approve": `UPDATE review_replies SET status = 'published', screening_reason = '', updated_at = ? WHERE id = ? AND deleted_at IS NULL`,
+	"hide":    `UPDATE review_replies SET status = 'hidden', updated_at = ? WHERE id = ? AND deleted_at IS NULL`,
+	"delete":  `UPDATE review_replies SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`,
+	"warn":    `UPDATE review_replies SET updated_at = ? WHERE id = ? AND deleted_at IS NULL`,
+}
+
+func (mr *ModerationRepository) CreateReport(newReport _entity.ReviewReport, events ..._entity.Event) (report _entity.ReviewReport, err error) {
+	var id uint
+
+	// report and its events are committed together, events belong to the review
+	err = _transaction.Run(mr.db, func(tx *sql.Tx) (uint, error) {
+		id, err = _transaction.Insert(tx, `
+			INSERT INTO review_reports (review_id, reply_id, user_id, reason, note, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, newReport.ReviewId, newReport.ReplyId, newReport.User.Id, newReport.Reason, newReport.Note, newReport.CreatedAt)
+
+		return newReport.ReviewId, err
+	}, events...)
+
+	if err != nil {
+		return
+	}
+
+	report = newReport
+	report.Id = id
+
+	return
+}
+
+func (mr *ModerationRepository) GetOpenReportByUserId(userId uint, reviewId uint, replyId uint) (report _entity.ReviewReport, err error) {
+	// prepare statement before execution
+	stmt, err := mr.db.Prepare(`
+		SELECT id, review_id, reply_id, user_id, reason, note, created_at
+		FROM review_reports
+		WHERE user_id = ?
+		  AND review_id = ?
+		  AND reply_id = ?
+		  AND resolved_at IS NULL
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(userId, reviewId, replyId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		if err = row.Scan(&report.Id, &report.ReviewId, &report.ReplyId, &report.User.Id, &report.Reason, &report.Note, &report.CreatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
+func (mr *ModerationRepository) GetOpenReportsByReviewId(reviewId uint) (reports []_entity.ReviewReport, err error) {
+	// prepare statement before execution
+	stmt, err := mr.db.Prepare(`
+		SELECT id, review_id, user_id, reason, note, created_at
+		FROM review_reports
+		WHERE review_id = ?
+		  AND reply_id = 0
+		  AND resolved_at IS NULL
+		ORDER BY created_at
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(reviewId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		report := _entity.ReviewReport{}
+
+		if err = row.Scan(&report.Id, &report.ReviewId, &report.User.Id, &report.Reason, &report.Note, &report.CreatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+
+		reports = append(reports, report)
+	}
+
+	return
+}
+
+func (mr *ModerationRepository) GetModerationQueue() (items []_entity.ModerationQueueItem, err error) {
+	// prepare statement before execution
+	stmt, err := mr.db.Prepare(`
+		SELECT r.id, r.user_id, r.book_id, r.star, r.content, r.flag, r.status, r.screening_reason, r.created_at, r.updated_at, COUNT(rr.id), COALESCE(MAX(rr.created_at), r.updated_at)
+		FROM reviews r
+		LEFT JOIN review_reports rr
+		ON r.id = rr.review_id
+		AND rr.reply_id = 0
+		AND rr.resolved_at IS NULL
+		WHERE r.deleted_at IS NULL
+		  AND (rr.id IS NOT NULL OR r.status = 'pending')
+		GROUP BY r.id, r.user_id, r.book_id, r.star, r.content, r.flag, r.status, r.screening_reason, r.created_at, r.updated_at
+		ORDER BY COUNT(rr.id) DESC, COALESCE(MIN(rr.created_at), r.updated_at)
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		item := _entity.ModerationQueueItem{}
+		review := &item.Review
+
+		if err = row.Scan(&review.Id, &review.User.Id, &review.Book.Id, &review.Star, &review.Content, &review.Flag, &review.Status, &review.ScreeningReason, &review.CreatedAt, &review.UpdatedAt, &item.ReportCount, &item.LastReportedAt); err != nil {
+			log.Println(err)
+			return
+		}
+
+		items = append(items, item)
+	}
+
+	return
+}
+
+func (mr *ModerationRepository) GetOpenReportsByReplyId(replyId uint) (reports []_entity.ReviewReport, err error) {
+	// prepare statement before execution
+	stmt, err := mr.db.Prepare(`
+		SELECT id, review_id, reply_id, user_id, reason, note, created_at
+		FROM review_reports
+		WHERE reply_id = ?
+		  AND resolved_at IS NULL
+		ORDER BY created_at
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(replyId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		report := _entity.ReviewReport{}
+
+		if err = row.Scan(&report.Id, &report.ReviewId, &report.ReplyId, &report.User.Id, &report.Reason, &report.Note, &report.CreatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+
+		reports = append(reports, report)
+	}
+
+	return
+}
+
+func (mr *ModerationRepository) GetReplyModerationQueue() (items []_entity.ReplyModerationQueueItem, err error) {
+	// prepare statement before execution
+	stmt, err := mr.db.Prepare(`
+		SELECT p.id, p.review_id, p.user_id, p.content, p.status, p.screening_reason, p.created_at, p.updated_at, COUNT(rr.id), COALESCE(MAX(rr.created_at), p.updated_at)
+		FROM review_replies p
+		LEFT JOIN review_reports rr
+		ON p.id = rr.reply_id
+		AND rr.resolved_at IS NULL
+		WHERE p.deleted_at IS NULL
+		  AND (rr.id IS NOT NULL OR p.status = 'pending')
+		GROUP BY p.id, p.review_id, p.user_id, p.content, p.status, p.screening_reason, p.created_at, p.updated_at
+		ORDER BY COUNT(rr.id) DESC, COALESCE(MIN(rr.created_at), p.updated_at)
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		item := _entity.ReplyModerationQueueItem{}
+		reply := &item.Reply
+
+		if err = row.Scan(&reply.Id, &reply.ReviewId, &reply.User.Id, &reply.Content, &reply.Status, &reply.ScreeningReason, &reply.CreatedAt, &reply.UpdatedAt, &item.ReportCount, &item.LastReportedAt); err != nil {
+			log.Println(err)
+			return
+		}
+
+		items = append(items, item)
+	}
+
+	return
+}
+
+func (mr *ModerationRepository) CreateAction(newAction _entity.ModerationAction, events ..._entity.Event) (action _entity.ModerationAction, err error) {
+	var id uint
+
+	// action, review changes and events are committed together
+	err = _transaction.Run(mr.db, func(tx *sql.Tx) (uint, error) {
+		id, err = _transaction.Insert(tx, `
+			INSERT INTO moderation_actions (review_id, reply_id, librarian_id, action, note, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, newAction.ReviewId, newAction.ReplyId, newAction.Librarian.Id, newAction.Action, newAction.Note, newAction.CreatedAt)
+
+		if err != nil {
+			return newAction.ReviewId, err
+		}
+
+		// apply action to the moderated review or reply
+		statement, targetId := actionStatements[newAction.Action], newAction.ReviewId
+
+		if newAction.ReplyId != 0 {
+			statement, targetId = replyActionStatements[newAction.Action], newAction.ReplyId
+		}
+
+		steps := []_transaction.Step{
+			{Query: statement, Args: []interface{}{newAction.CreatedAt, targetId}},
+			// every open report on the same target is settled by the action
+			{Query: `UPDATE review_reports SET resolved_at = ? WHERE review_id = ? AND reply_id = ? AND resolved_at IS NULL`, Args: []interface{}{newAction.CreatedAt, newAction.ReviewId, newAction.ReplyId}},
+		}
+
+		return newAction.ReviewId, _transaction.Exec(tx, steps)
+	}, events...)
+
+	if err != nil {
+		return
+	}
+
+	action = newAction
+	action.Id = id
+
+	return
+}
@@ -0,0 +1,17 @@
+package notification
+
+import (
+	_entity "plain-go/public-library/entity"
+)
+
+type Notification interface {
+	GetPreferenceByUserId(userId uint) (preference _entity.NotificationPreference, err error)
+	UpsertPreference(newPreference _entity.NotificationPreference) (preference _entity.NotificationPreference, err error)
+	CreateNotification(newNotification _entity.Notification) (notification _entity.Notification, err error)
+	IsNotificationSent(userId uint, reference string) (sent bool, err error)
+	CreateNotificationLog(userId uint, reference string, channels string) (err error)
+	GetAllNotificationsByUserId(userId uint, unreadOnly bool) (notifications []_entity.Notification, err error)
+	CountUnreadNotificationsByUserId(userId uint) (count uint, err error)
+	MarkNotificationsAsRead(userId uint, notificationIds []uint) (err error)
+	DismissNotifications(userId uint, notificationIds []uint) (err error)
+}
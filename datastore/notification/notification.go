@@ -0,0 +1,346 @@
+package notification
+
+import (
+	"database/sql"
+	"log"
+	"strings"
+	"time"
+
+	_entity "plain-go/public-library/entity"
+)
+
+type NotificationRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+func (nr *NotificationRepository) GetPreferenceByUserId(userId uint) (preference _entity.NotificationPreference, err error) {
+	// prepare statement before execution
+	stmt, err := nr.db.Prepare(`
+		SELECT user_id, email, sms, in_app, days_before_due, updated_at
+		FROM notification_preferences
+		WHERE user_id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(userId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		if err = row.Scan(&preference.UserId, &preference.Email, &preference.SMS, &preference.InApp, &preference.DaysBeforeDue, &preference.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
+func (nr *NotificationRepository) UpsertPreference(newPreference _entity.NotificationPreference) (preference _entity.NotificationPreference, err error) {
+	// prepare statement before execution
+	stmt, err := nr.db.Prepare(`
+		INSERT INTO notification_preferences (user_id, email, sms, in_app, days_before_due, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE email = VALUES(email), sms = VALUES(sms), in_app = VALUES(in_app), days_before_due = VALUES(days_before_due), updated_at = VALUES(updated_at)
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(newPreference.UserId, newPreference.Email, newPreference.SMS, newPreference.InApp, newPreference.DaysBeforeDue, newPreference.UpdatedAt)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	preference = newPreference
+
+	return
+}
+
+func (nr *NotificationRepository) CreateNotification(newNotification _entity.Notification) (notification _entity.Notification, err error) {
+	// prepare statement before execution
+	stmt, err := nr.db.Prepare(`
+		INSERT INTO notifications (user_id, kind, subject, content, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	res, err := stmt.Exec(newNotification.User.Id, newNotification.Kind, newNotification.Subject, newNotification.Content, newNotification.CreatedAt)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	// get new notification id
+	id, err := res.LastInsertId()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	notification = newNotification
+	notification.Id = uint(id)
+
+	return
+}
+
+func (nr *NotificationRepository) IsNotificationSent(userId uint, reference string) (sent bool, err error) {
+	// prepare statement before execution
+	stmt, err := nr.db.Prepare(`
+		SELECT COUNT(id)
+		FROM notification_logs
+		WHERE user_id = ?
+		  AND reference = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(userId, reference)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	count := 0
+
+	if row.Next() {
+		if err = row.Scan(&count); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	sent = count > 0
+
+	return
+}
+
+func (nr *NotificationRepository) CreateNotificationLog(userId uint, reference string, channels string) (err error) {
+	// prepare statement before execution
+	stmt, err := nr.db.Prepare(`
+		INSERT INTO notification_logs (user_id, reference, channels, created_at)
+		VALUES (?, ?, ?, ?)
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(userId, reference, channels, time.Now())
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+func (nr *NotificationRepository) GetAllNotificationsByUserId(userId uint, unreadOnly bool) (notifications []_entity.Notification, err error) {
+	// basic query
+	query := `
+		SELECT id, user_id, kind, subject, content, read_at, created_at
+		FROM notifications
+		WHERE user_id = ?
+		  AND dismissed_at IS NULL
+	`
+
+	if unreadOnly {
+		query += ` AND read_at IS NULL`
+	}
+
+	query += ` ORDER BY created_at DESC`
+
+	// prepare statement before execution
+	stmt, err := nr.db.Prepare(query)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(userId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		notification := _entity.Notification{}
+
+		if err = row.Scan(&notification.Id, &notification.User.Id, &notification.Kind, &notification.Subject, &notification.Content, &notification.ReadAt, &notification.CreatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	return
+}
+
+func (nr *NotificationRepository) CountUnreadNotificationsByUserId(userId uint) (count uint, err error) {
+	// prepare statement before execution
+	stmt, err := nr.db.Prepare(`
+		SELECT COUNT(id)
+		FROM notifications
+		WHERE user_id = ?
+		  AND read_at IS NULL
+		  AND dismissed_at IS NULL
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(userId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		if err = row.Scan(&count); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
+func (nr *NotificationRepository) MarkNotificationsAsRead(userId uint, notificationIds []uint) (err error) {
+	// basic query, empty ids means every notification of the user
+	query := `
+		UPDATE notifications
+		SET read_at = ?
+		WHERE user_id = ?
+		  AND read_at IS NULL
+		  AND dismissed_at IS NULL
+	`
+
+	args := []interface{}{time.Now(), userId}
+
+	if len(notificationIds) > 0 {
+		query += ` AND id IN (?` + strings.Repeat(`, ?`, len(notificationIds)-1) + `)`
+
+		for _, id := range notificationIds {
+			args = append(args, id)
+		}
+	}
+
+	// prepare statement before execution
+	stmt, err := nr.db.Prepare(query)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(args...)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+func (nr *NotificationRepository) DismissNotifications(userId uint, notificationIds []uint) (err error) {
+	// basic query
+	query := `
+		UPDATE notifications
+		SET dismissed_at = ?
+		WHERE user_id = ?
+		  AND dismissed_at IS NULL
+		  AND id IN (?` + strings.Repeat(`, ?`, len(notificationIds)-1) + `)
+	`
+
+	args := []interface{}{time.Now(), userId}
+
+	for _, id := range notificationIds {
+		args = append(args, id)
+	}
+
+	// prepare statement before execution
+	stmt, err := nr.db.Prepare(query)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(args...)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
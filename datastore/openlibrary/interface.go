@@ -0,0 +1,14 @@
+package openlibrary
+
+import (
+	_entity "plain-go/public-library/entity"
+)
+
+type OpenLibrary interface {
+	SaveEditions(editions []_entity.OpenLibraryEdition) (err error)
+	SaveWorks(works []_entity.OpenLibraryWork) (err error)
+	SaveAuthors(authors []_entity.OpenLibraryAuthor) (err error)
+	GetEditionByISBN(isbn13 string) (edition _entity.OpenLibraryEdition, err error)
+	GetWorkByKey(key string) (work _entity.OpenLibraryWork, err error)
+	GetAuthorNames(keys []string) (names map[string]string, err error)
+}
@@ -0,0 +1,229 @@
+package openlibrary
+
+import (
+	"database/sql"
+	"log"
+	_entity "plain-go/public-library/entity"
+	"strings"
+)
+
+type OpenLibraryRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *OpenLibraryRepository {
+	return &OpenLibraryRepository{db: db}
+}
+
+// keys are kept comma separated, Open Library keys never contain comma
+func joinKeys(keys []string) string {
+	return strings.Join(keys, ",")
+}
+
+func splitKeys(keys string) []string {
+	if keys == "" {
+		return nil
+	}
+
+	return strings.Split(keys, ",")
+}
+
+// save writes a batch of records in one transaction, record of a later dump replaces the earlier one
+func (olr *OpenLibraryRepository) save(query string, count int, args func(i int) []interface{}) (err error) {
+	// begin transaction
+	tx, err := olr.db.Begin()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer tx.Rollback()
+
+	// prepare statement before execution
+	stmt, err := tx.Prepare(query)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	for i := 0; i < count; i++ {
+		// execute statement
+		if _, err = stmt.Exec(args(i)...); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+func (olr *OpenLibraryRepository) SaveEditions(editions []_entity.OpenLibraryEdition) (err error) {
+	return olr.save(`
+		INSERT INTO openlibrary_editions (isbn13, title, publisher, pages, language, author_keys, work_key, description, subject)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE title = VALUES(title), publisher = VALUES(publisher), pages = VALUES(pages), language = VALUES(language),
+		                        author_keys = VALUES(author_keys), work_key = VALUES(work_key), description = VALUES(description), subject = VALUES(subject)
+	`, len(editions), func(i int) []interface{} {
+		e := editions[i]
+		return []interface{}{e.ISBN13, e.Title, e.Publisher, e.Pages, e.Language, joinKeys(e.AuthorKeys), e.WorkKey, e.Description, e.Subject}
+	})
+}
+
+func (olr *OpenLibraryRepository) SaveWorks(works []_entity.OpenLibraryWork) (err error) {
+	return olr.save(`
+		INSERT INTO openlibrary_works (work_key, description, subject, author_keys)
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE description = VALUES(description), subject = VALUES(subject), author_keys = VALUES(author_keys)
+	`, len(works), func(i int) []interface{} {
+		w := works[i]
+		return []interface{}{w.Key, w.Description, w.Subject, joinKeys(w.AuthorKeys)}
+	})
+}
+
+func (olr *OpenLibraryRepository) SaveAuthors(authors []_entity.OpenLibraryAuthor) (err error) {
+	return olr.save(`
+		INSERT INTO openlibrary_authors (author_key, name)
+		VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE name = VALUES(name)
+	`, len(authors), func(i int) []interface{} {
+		return []interface{}{authors[i].Key, authors[i].Name}
+	})
+}
+
+func (olr *OpenLibraryRepository) GetEditionByISBN(isbn13 string) (edition _entity.OpenLibraryEdition, err error) {
+	// prepare statement before execution
+	stmt, err := olr.db.Prepare(`
+		SELECT isbn13, title, publisher, pages, language, author_keys, work_key, description, subject
+		FROM openlibrary_editions
+		WHERE isbn13 = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(isbn13)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		authorKeys := ""
+
+		if err = row.Scan(&edition.ISBN13, &edition.Title, &edition.Publisher, &edition.Pages, &edition.Language, &authorKeys, &edition.WorkKey, &edition.Description, &edition.Subject); err != nil {
+			log.Println(err)
+			return
+		}
+
+		edition.AuthorKeys = splitKeys(authorKeys)
+	}
+
+	return
+}
+
+func (olr *OpenLibraryRepository) GetWorkByKey(key string) (work _entity.OpenLibraryWork, err error) {
+	// prepare statement before execution
+	stmt, err := olr.db.Prepare(`
+		SELECT work_key, description, subject, author_keys
+		FROM openlibrary_works
+		WHERE work_key = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(key)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		authorKeys := ""
+
+		if err = row.Scan(&work.Key, &work.Description, &work.Subject, &authorKeys); err != nil {
+			log.Println(err)
+			return
+		}
+
+		work.AuthorKeys = splitKeys(authorKeys)
+	}
+
+	return
+}
+
+func (olr *OpenLibraryRepository) GetAuthorNames(keys []string) (names map[string]string, err error) {
+	names = map[string]string{}
+
+	if len(keys) == 0 {
+		return
+	}
+
+	args := []interface{}{}
+
+	for _, key := range keys {
+		args = append(args, key)
+	}
+
+	// prepare statement before execution
+	stmt, err := olr.db.Prepare(`
+		SELECT author_key, name
+		FROM openlibrary_authors
+		WHERE author_key IN (?` + strings.Repeat(`, ?`, len(keys)-1) + `)
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(args...)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		key, name := "", ""
+
+		if err = row.Scan(&key, &name); err != nil {
+			log.Println(err)
+			return
+		}
+
+		names[key] = name
+	}
+
+	return
+}
@@ -0,0 +1,16 @@
+package outbox
+
+import (
+	_entity "plain-go/public-library/entity"
+	"time"
+)
+
+type Outbox interface {
+	GetPendingEvents(limit int) (events []_entity.Event, err error)
+	IsEventHandled(eventId string, subscriber string) (handled bool, err error)
+	MarkEventHandled(eventId string, subscriber string) (err error)
+	MarkEventDispatched(eventId string) (err error)
+	DelayEvent(eventId string, attempts uint, nextAttemptAt time.Time) (err error)
+	ParkEvent(eventId string, attempts uint) (err error)
+	CreateAuditLog(event _entity.Event) (err error)
+}
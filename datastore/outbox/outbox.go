@@ -0,0 +1,281 @@
+package outbox
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	_entity "plain-go/public-library/entity"
+	_helper "plain-go/public-library/helper"
+	"time"
+)
+
+type OutboxRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// event is written within caller's transaction, so it is only visible once the state change is committed
+func Append(tx *sql.Tx, event _entity.Event) (err error) {
+	if event.Id == "" {
+		event.Id = _helper.GenerateId()
+	}
+
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	payload, err := json.Marshal(event.Payload)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	// prepare statement before execution
+	stmt, err := tx.Prepare(`
+		INSERT INTO outbox_events (id, type, aggregate_type, aggregate_id, user_id, payload, occurred_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(event.Id, event.Type, event.AggregateType, event.AggregateId, event.UserId, payload, event.OccurredAt)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+func (or *OutboxRepository) GetPendingEvents(limit int) (events []_entity.Event, err error) {
+	// prepare statement before execution
+	stmt, err := or.db.Prepare(`
+		SELECT id, type, aggregate_type, aggregate_id, user_id, payload, occurred_at, attempts
+		FROM outbox_events
+		WHERE dispatched_at IS NULL
+		  AND parked_at IS NULL
+		  AND (next_attempt_at IS NULL OR next_attempt_at <= ?)
+		ORDER BY sequence ASC
+		LIMIT ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(time.Now(), limit)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		event := _entity.Event{}
+		payload := []byte{}
+
+		if err = row.Scan(&event.Id, &event.Type, &event.AggregateType, &event.AggregateId, &event.UserId, &payload, &event.OccurredAt, &event.Attempts); err != nil {
+			log.Println(err)
+			return
+		}
+
+		event.Payload = json.RawMessage(payload)
+		events = append(events, event)
+	}
+
+	return
+}
+
+func (or *OutboxRepository) IsEventHandled(eventId string, subscriber string) (handled bool, err error) {
+	// prepare statement before execution
+	stmt, err := or.db.Prepare(`
+		SELECT COUNT(event_id)
+		FROM outbox_handled_events
+		WHERE event_id = ?
+		  AND subscriber = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(eventId, subscriber)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	count := 0
+
+	if row.Next() {
+		if err = row.Scan(&count); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	handled = count > 0
+
+	return
+}
+
+func (or *OutboxRepository) MarkEventHandled(eventId string, subscriber string) (err error) {
+	// prepare statement before execution
+	stmt, err := or.db.Prepare(`
+		INSERT IGNORE INTO outbox_handled_events (event_id, subscriber, handled_at)
+		VALUES (?, ?, ?)
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(eventId, subscriber, time.Now())
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+func (or *OutboxRepository) MarkEventDispatched(eventId string) (err error) {
+	// prepare statement before execution
+	stmt, err := or.db.Prepare(`
+		UPDATE outbox_events
+		SET dispatched_at = ?
+		WHERE id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(time.Now(), eventId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+// DelayEvent keeps failed event out of pending events until its next attempt
+func (or *OutboxRepository) DelayEvent(eventId string, attempts uint, nextAttemptAt time.Time) (err error) {
+	// prepare statement before execution
+	stmt, err := or.db.Prepare(`
+		UPDATE outbox_events
+		SET attempts = ?, next_attempt_at = ?
+		WHERE id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(attempts, nextAttemptAt, eventId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+// ParkEvent gives up on event failing too many times, it is kept for inspection and never dispatched again
+func (or *OutboxRepository) ParkEvent(eventId string, attempts uint) (err error) {
+	// prepare statement before execution
+	stmt, err := or.db.Prepare(`
+		UPDATE outbox_events
+		SET attempts = ?, parked_at = ?
+		WHERE id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(attempts, time.Now(), eventId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+func (or *OutboxRepository) CreateAuditLog(event _entity.Event) (err error) {
+	payload, err := json.Marshal(event.Payload)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	// prepare statement before execution
+	stmt, err := or.db.Prepare(`
+		INSERT IGNORE INTO audit_logs (event_id, type, aggregate_type, aggregate_id, user_id, payload, occurred_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(event.Id, event.Type, event.AggregateType, event.AggregateId, event.UserId, payload, event.OccurredAt)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
@@ -0,0 +1,21 @@
+package readinglist
+
+import (
+	_entity "plain-go/public-library/entity"
+	_model "plain-go/public-library/model"
+)
+
+type ReadingList interface {
+	CreateList(newList _entity.ReadingList) (list _entity.ReadingList, err error)
+	GetListById(listId uint) (list _entity.ReadingList, err error)
+	GetListsByUserId(userId uint, publicOnly bool) (lists []_entity.ReadingList, err error)
+	GetPublicLists(params _model.GetAllReadingListsRequest) (lists []_entity.ReadingList, err error)
+	UpdateList(updatedList _entity.ReadingList) (list _entity.ReadingList, err error)
+	DeleteList(listId uint) (err error)
+	GetItems(listId uint) (items []_entity.ReadingListItem, err error)
+	GetItem(listId uint, bookId uint) (item _entity.ReadingListItem, err error)
+	AddItem(listId uint, newItem _entity.ReadingListItem) (item _entity.ReadingListItem, err error)
+	UpdateItem(listId uint, updatedItem _entity.ReadingListItem) (item _entity.ReadingListItem, err error)
+	RemoveItem(listId uint, bookId uint) (err error)
+	ReorderItems(listId uint, bookIds []uint) (err error)
+}
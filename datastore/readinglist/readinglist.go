@@ -0,0 +1,474 @@
+package readinglist
+
+import (
+	"database/sql"
+	"log"
+	_entity "plain-go/public-library/entity"
+	_model "plain-go/public-library/model"
+	"strings"
+	"time"
+)
+
+type ReadingListRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *ReadingListRepository {
+	return &ReadingListRepository{db: db}
+}
+
+func (rr *ReadingListRepository) CreateList(newList _entity.ReadingList) (list _entity.ReadingList, err error) {
+	// prepare statement before execution
+	stmt, err := rr.db.Prepare(`
+		INSERT INTO reading_lists (user_id, name, description, visibility, share_token, featured, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, 0, ?, ?)
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	res, err := stmt.Exec(newList.Owner.Id, newList.Name, newList.Description, newList.Visibility, newList.ShareToken, newList.CreatedAt, newList.UpdatedAt)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	// get new list id
+	id, err := res.LastInsertId()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	list = newList
+	list.Id = uint(id)
+
+	return
+}
+
+func (rr *ReadingListRepository) GetListById(listId uint) (list _entity.ReadingList, err error) {
+	// prepare statement before execution
+	stmt, err := rr.db.Prepare(`
+		SELECT l.id, l.user_id, u.name, l.name, l.description, l.visibility, l.share_token, l.featured,
+			(
+				SELECT COUNT(i.book_id)
+				FROM reading_list_items i
+				JOIN books b
+				ON i.book_id = b.id
+				WHERE i.list_id = l.id
+				  AND b.deleted_at IS NULL
+			) AS item_count,
+			l.created_at, l.updated_at
+		FROM reading_lists l
+		JOIN users u
+		ON l.user_id = u.id
+		WHERE l.id = ?
+		  AND l.deleted_at IS NULL
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(listId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		if err = row.Scan(&list.Id, &list.Owner.Id, &list.Owner.Name, &list.Name, &list.Description, &list.Visibility, &list.ShareToken, &list.Featured, &list.ItemCount, &list.CreatedAt, &list.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
+func (rr *ReadingListRepository) GetListsByUserId(userId uint, publicOnly bool) (lists []_entity.ReadingList, err error) {
+	// prepare statement before execution
+	stmt, err := rr.db.Prepare(`
+		SELECT l.id, l.user_id, u.name, l.name, l.description, l.visibility, l.share_token, l.featured,
+			(
+				SELECT COUNT(i.book_id)
+				FROM reading_list_items i
+				JOIN books b
+				ON i.book_id = b.id
+				WHERE i.list_id = l.id
+				  AND b.deleted_at IS NULL
+			) AS item_count,
+			l.created_at, l.updated_at
+		FROM reading_lists l
+		JOIN users u
+		ON l.user_id = u.id
+		WHERE l.user_id = ?
+		  AND l.deleted_at IS NULL
+		  AND (? = 0 OR l.visibility = 'public')
+		ORDER BY l.updated_at DESC
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(userId, publicOnly)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		list := _entity.ReadingList{}
+
+		if err = row.Scan(&list.Id, &list.Owner.Id, &list.Owner.Name, &list.Name, &list.Description, &list.Visibility, &list.ShareToken, &list.Featured, &list.ItemCount, &list.CreatedAt, &list.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+
+		lists = append(lists, list)
+	}
+
+	return
+}
+
+func (rr *ReadingListRepository) GetPublicLists(params _model.GetAllReadingListsRequest) (lists []_entity.ReadingList, err error) {
+	// prepare statement before execution
+	// featured list comes first
+	stmt, err := rr.db.Prepare(`
+		SELECT l.id, l.user_id, u.name, l.name, l.description, l.visibility, l.featured,
+			(
+				SELECT COUNT(i.book_id)
+				FROM reading_list_items i
+				JOIN books b
+				ON i.book_id = b.id
+				WHERE i.list_id = l.id
+				  AND b.deleted_at IS NULL
+			) AS item_count,
+			l.created_at, l.updated_at
+		FROM reading_lists l
+		JOIN users u
+		ON l.user_id = u.id
+		WHERE l.visibility = 'public'
+		  AND l.deleted_at IS NULL
+		  AND u.deleted_at IS NULL
+		  AND (? = 0 OR l.featured = 1)
+		  AND (? = '' OR UPPER(l.name) LIKE ? OR UPPER(l.description) LIKE ?)
+		ORDER BY l.featured DESC, l.featured_at DESC, l.updated_at DESC
+		LIMIT ? OFFSET ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	keyword := "%" + strings.ToUpper(params.Keyword) + "%"
+	row, err := stmt.Query(params.Featured, params.Keyword, keyword, keyword, params.Records, (params.Page-1)*params.Records)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		list := _entity.ReadingList{}
+
+		if err = row.Scan(&list.Id, &list.Owner.Id, &list.Owner.Name, &list.Name, &list.Description, &list.Visibility, &list.Featured, &list.ItemCount, &list.CreatedAt, &list.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+
+		lists = append(lists, list)
+	}
+
+	return
+}
+
+func (rr *ReadingListRepository) UpdateList(updatedList _entity.ReadingList) (list _entity.ReadingList, err error) {
+	// prepare statement before execution
+	// featured_at is only stamped when list becomes featured
+	stmt, err := rr.db.Prepare(`
+		UPDATE reading_lists
+		SET name = ?, description = ?, visibility = ?,
+			featured_at = IF(? = 1 AND featured = 0, ?, featured_at),
+			featured = ?, updated_at = ?
+		WHERE id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(updatedList.Name, updatedList.Description, updatedList.Visibility, updatedList.Featured, updatedList.UpdatedAt, updatedList.Featured, updatedList.UpdatedAt, updatedList.Id)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	list = updatedList
+
+	return
+}
+
+func (rr *ReadingListRepository) DeleteList(listId uint) (err error) {
+	// prepare statement before execution
+	stmt, err := rr.db.Prepare(`
+		UPDATE reading_lists
+		SET deleted_at = ?
+		WHERE id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(time.Now(), listId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+func (rr *ReadingListRepository) GetItems(listId uint) (items []_entity.ReadingListItem, err error) {
+	// prepare statement before execution
+	stmt, err := rr.db.Prepare(`
+		SELECT b.id, b.title, b.publisher, b.language, b.pages, b.category, b.isbn13, b.description, b.created_at, b.updated_at,
+			i.position, i.note, i.created_at, i.updated_at
+		FROM reading_list_items i
+		JOIN books b
+		ON i.book_id = b.id
+		WHERE i.list_id = ?
+		  AND b.deleted_at IS NULL
+		ORDER BY i.position, i.created_at
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(listId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		item := _entity.ReadingListItem{}
+
+		if err = row.Scan(&item.Book.Id, &item.Book.Title, &item.Book.Publisher, &item.Book.Language, &item.Book.Pages, &item.Book.Category, &item.Book.ISBN13, &item.Book.Description, &item.Book.CreatedAt, &item.Book.UpdatedAt, &item.Position, &item.Note, &item.CreatedAt, &item.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+
+		items = append(items, item)
+	}
+
+	return
+}
+
+func (rr *ReadingListRepository) GetItem(listId uint, bookId uint) (item _entity.ReadingListItem, err error) {
+	// prepare statement before execution
+	stmt, err := rr.db.Prepare(`
+		SELECT book_id, position, note, created_at, updated_at
+		FROM reading_list_items
+		WHERE list_id = ?
+		  AND book_id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(listId, bookId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		if err = row.Scan(&item.Book.Id, &item.Position, &item.Note, &item.CreatedAt, &item.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
+func (rr *ReadingListRepository) AddItem(listId uint, newItem _entity.ReadingListItem) (item _entity.ReadingListItem, err error) {
+	// prepare statement before execution
+	// new book is placed at the end of the list
+	stmt, err := rr.db.Prepare(`
+		INSERT INTO reading_list_items (list_id, book_id, position, note, created_at, updated_at)
+		SELECT ?, ?, COALESCE(MAX(position), 0) + 1, ?, ?, ?
+		FROM reading_list_items
+		WHERE list_id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(listId, newItem.Book.Id, newItem.Note, newItem.CreatedAt, newItem.UpdatedAt, listId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	return rr.GetItem(listId, newItem.Book.Id)
+}
+
+func (rr *ReadingListRepository) UpdateItem(listId uint, updatedItem _entity.ReadingListItem) (item _entity.ReadingListItem, err error) {
+	// prepare statement before execution
+	stmt, err := rr.db.Prepare(`
+		UPDATE reading_list_items
+		SET note = ?, updated_at = ?
+		WHERE list_id = ?
+		  AND book_id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(updatedItem.Note, updatedItem.UpdatedAt, listId, updatedItem.Book.Id)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	item = updatedItem
+
+	return
+}
+
+func (rr *ReadingListRepository) RemoveItem(listId uint, bookId uint) (err error) {
+	// prepare statement before execution
+	stmt, err := rr.db.Prepare(`
+		DELETE FROM reading_list_items
+		WHERE list_id = ?
+		  AND book_id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(listId, bookId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+func (rr *ReadingListRepository) ReorderItems(listId uint, bookIds []uint) (err error) {
+	// begin transaction, every position is saved together
+	tx, err := rr.db.Begin()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer tx.Rollback()
+
+	// prepare statement before execution
+	stmt, err := tx.Prepare(`
+		UPDATE reading_list_items
+		SET position = ?
+		WHERE list_id = ?
+		  AND book_id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	for i, bookId := range bookIds {
+		if _, err = stmt.Exec(i+1, listId, bookId); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
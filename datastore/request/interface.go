@@ -9,7 +9,11 @@ type Request interface {
 	GetAllRequestsByUserId(userId uint) (requests []_entity.SimplifiedRequest, err error)
 	CountActiveRequestByUserId(userId uint) (count uint, err error)
 	GetRequestByUserIdAndBookId(userId uint, bookId uint) (requests []_entity.Request, err error)
-	CreateNewRequest(newRequest _entity.Request) (request _entity.Request, err error)
+	CreateNewRequest(newRequest _entity.Request, events ..._entity.Event) (request _entity.Request, err error)
 	GetRequestById(requestId uint) (request _entity.Request, err error)
-	Update(updatedRequest _entity.Request) (request _entity.Request, err error)
+	Update(updatedRequest _entity.Request, events ..._entity.Event) (request _entity.Request, err error)
+	GetAllActiveLoans() (requests []_entity.Request, err error)
+	HasReturnedBook(userId uint, bookId uint) (returned bool, err error)
+	GetOldestWaitingRequest(bookId uint, workId uint) (request _entity.Request, err error)
+	AssignBookItem(updatedRequest _entity.Request, events ..._entity.Event) (request _entity.Request, err error)
 }
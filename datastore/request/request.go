@@ -3,9 +3,22 @@ package request
 import (
 	"database/sql"
 	"log"
+	_transaction "plain-go/public-library/datastore/transaction"
 	_entity "plain-go/public-library/entity"
 )
 
+// VerifiedBorrower tells whether user r.user_id has returned a copy of book r.book_id,
+// status 8 and 9 are returned and returned with penalty paid
+const VerifiedBorrower = `EXISTS (
+	SELECT 1
+	FROM requests rq
+	JOIN book_items bi
+	ON rq.book_item_id = bi.id
+	WHERE rq.user_id = r.user_id
+	  AND bi.book_id = r.book_id
+	  AND rq.status_id IN (8, 9)
+)`
+
 type RequestRepository struct {
 	db *sql.DB
 }
@@ -175,11 +188,37 @@ func (rr RequestRepository) GetRequestByUserIdAndBookId(userId uint, bookId uint
 	return
 }
 
-func (rr RequestRepository) CreateNewRequest(newRequest _entity.Request) (request _entity.Request, err error) {
+func (rr RequestRepository) CreateNewRequest(newRequest _entity.Request, events ..._entity.Event) (request _entity.Request, err error) {
+	var id uint
+
+	// request and its events are committed together
+	err = _transaction.Run(rr.db, func(tx *sql.Tx) (uint, error) {
+		id, err = _transaction.Insert(tx, `
+			INSERT INTO requests (user_id, book_item_id, work_id, book_id, status_id, created_at, updated_at)
+			VALUES (?, ?, NULLIF(?, 0), NULLIF(?, 0), ?, ?, ?)
+		`, newRequest.User.Id, newRequest.BookItem.Id, newRequest.WorkId, newRequest.BookId, newRequest.Status.Id, newRequest.CreatedAt, newRequest.UpdatedAt)
+
+		return id, err
+	}, events...)
+
+	if err != nil {
+		return
+	}
+
+	request = newRequest
+	request.Id = id
+
+	return
+}
+
+func (rr RequestRepository) GetRequestById(requestId uint) (request _entity.Request, err error) {
 	// prepare statement before execution
 	stmt, err := rr.db.Prepare(`
-		INSERT INTO requests (user_id, book_item_id, status_id, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?)
+		SELECT r.id, r.user_id, r.book_id, r.status_id, rs.description, r.extended, r.created_at, r.start_at, r.finish_at, r.return_at, r.cancel_at, r.updated_at
+		FROM requests r
+		JOIN request_status rs
+		ON r.status_id = rs.id
+		WHERE id = ?
 	`)
 
 	if err != nil {
@@ -190,35 +229,54 @@ func (rr RequestRepository) CreateNewRequest(newRequest _entity.Request) (reques
 	defer stmt.Close()
 
 	// execute statement
-	res, err := stmt.Exec(newRequest.User.Id, newRequest.BookItem.Id, newRequest.Status.Id, newRequest.CreatedAt, newRequest.UpdatedAt)
+	row, err := stmt.Query(requestId)
 
 	if err != nil {
 		log.Println(err)
 		return
 	}
 
-	// get new request id
-	id, err := res.LastInsertId()
+	if row.Next() {
+		if err = row.Scan(&request.Id, &request.User.Id, &request.BookItem.Id, &request.Status.Id, &request.Status.Description, &request.Extended, &request.StartAt, &request.ReturnAt, &request.CancelAt, &request.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
 
-	if err != nil {
-		log.Println(err)
+func (rr RequestRepository) Update(updatedRequest _entity.Request, events ..._entity.Event) (request _entity.Request, err error) {
+	steps := []_transaction.Step{{
+		Query: `
+			UPDATE requests
+			SET status_id = ?, extended = ?, finish_at = ?, cancel_at = ?, updated_at = ?
+			WHERE id = ?
+		`,
+		Args: []interface{}{updatedRequest.Status.Id, updatedRequest.Extended, updatedRequest.FinishAt, updatedRequest.CancelAt, updatedRequest.UpdatedAt, updatedRequest.Id},
+	}}
+
+	// request and its events are committed together
+	if err = _transaction.Commit(rr.db, steps, updatedRequest.Id, events...); err != nil {
 		return
 	}
 
-	request = newRequest
-	request.Id = uint(id)
+	request = updatedRequest
 
 	return
 }
 
-func (rr RequestRepository) GetRequestById(requestId uint) (request _entity.Request, err error) {
+func (rr RequestRepository) GetAllActiveLoans() (requests []_entity.Request, err error) {
 	// prepare statement before execution
 	stmt, err := rr.db.Prepare(`
-		SELECT r.id, r.user_id, r.book_id, r.status_id, rs.description, r.extended, r.created_at, r.start_at, r.finish_at, r.return_at, r.cancel_at, r.updated_at
+		SELECT r.id, r.book_item_id, r.user_id, r.status_id, rs.description, r.extended, r.created_at, r.start_at, r.finish_at, r.updated_at
 		FROM requests r
 		JOIN request_status rs
 		ON r.status_id = rs.id
-		WHERE id = ?
+		WHERE r.status_id IN (5, 6, 7)
+		  AND r.finish_at IS NOT NULL
+		  AND r.return_at IS NULL
+		  AND r.cancel_at IS NULL
 	`)
 
 	if err != nil {
@@ -229,15 +287,55 @@ func (rr RequestRepository) GetRequestById(requestId uint) (request _entity.Requ
 	defer stmt.Close()
 
 	// execute statement
-	row, err := stmt.Query(requestId)
+	row, err := stmt.Query()
 
 	if err != nil {
 		log.Println(err)
 		return
 	}
 
+	defer row.Close()
+
+	for row.Next() {
+		request := _entity.Request{}
+
+		if err = row.Scan(&request.Id, &request.BookItem.Id, &request.User.Id, &request.Status.Id, &request.Status.Description, &request.Extended, &request.CreatedAt, &request.StartAt, &request.FinishAt, &request.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+
+		requests = append(requests, request)
+	}
+
+	return
+}
+
+func (rr RequestRepository) HasReturnedBook(userId uint, bookId uint) (returned bool, err error) {
+	// prepare statement before execution
+	stmt, err := rr.db.Prepare(`
+		SELECT ` + VerifiedBorrower + `
+		FROM (SELECT ? AS user_id, ? AS book_id) r
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(userId, bookId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
 	if row.Next() {
-		if err = row.Scan(&request.Id, &request.User.Id, &request.BookItem.Id, &request.Status.Id, &request.Status.Description, &request.Extended, &request.StartAt, &request.ReturnAt, &request.CancelAt, &request.UpdatedAt); err != nil {
+		if err = row.Scan(&returned); err != nil {
 			log.Println(err)
 			return
 		}
@@ -246,12 +344,19 @@ func (rr RequestRepository) GetRequestById(requestId uint) (request _entity.Requ
 	return
 }
 
-func (rr RequestRepository) Update(updatedRequest _entity.Request) (request _entity.Request, err error) {
-	// prepare statment before execution
+// GetOldestWaitingRequest returns the oldest request in queue, either waiting for the book or holding any edition of the work
+func (rr RequestRepository) GetOldestWaitingRequest(bookId uint, workId uint) (request _entity.Request, err error) {
+	// prepare statement before execution
 	stmt, err := rr.db.Prepare(`
-		UPDATE requests
-		SET status_id = ?, extended = ?, finish_at = ?, cancel_at = ?, updated_at = ?
-		WHERE id = ?
+		SELECT r.id, r.user_id, r.book_item_id, COALESCE(r.work_id, 0), COALESCE(r.book_id, 0), r.status_id, rs.description, r.extended, r.created_at, r.updated_at
+		FROM requests r
+		JOIN request_status rs
+		ON r.status_id = rs.id
+		WHERE (r.book_id = ? OR r.work_id = ?)
+		  AND r.status_id = 1
+		  AND r.cancel_at IS NULL
+		ORDER BY r.created_at ASC, r.id ASC
+		LIMIT 1
 	`)
 
 	if err != nil {
@@ -262,13 +367,42 @@ func (rr RequestRepository) Update(updatedRequest _entity.Request) (request _ent
 	defer stmt.Close()
 
 	// execute statement
-	_, err = stmt.Exec(updatedRequest.Status.Id, updatedRequest.Extended, updatedRequest.FinishAt, updatedRequest.CancelAt, updatedRequest.UpdatedAt, updatedRequest.Id)
+	row, err := stmt.Query(bookId, workId)
 
 	if err != nil {
 		log.Println(err)
 		return
 	}
 
+	defer row.Close()
+
+	if row.Next() {
+		if err = row.Scan(&request.Id, &request.User.Id, &request.BookItem.Id, &request.WorkId, &request.BookId, &request.Status.Id, &request.Status.Description, &request.Extended, &request.CreatedAt, &request.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
+// AssignBookItem hands a copy to a request in queue
+func (rr RequestRepository) AssignBookItem(updatedRequest _entity.Request, events ..._entity.Event) (request _entity.Request, err error) {
+	steps := []_transaction.Step{{
+		Query: `
+			UPDATE requests
+			SET book_item_id = ?, status_id = ?, updated_at = ?
+			WHERE id = ?
+			  AND status_id = 1
+		`,
+		Args: []interface{}{updatedRequest.BookItem.Id, updatedRequest.Status.Id, updatedRequest.UpdatedAt, updatedRequest.Id},
+	}}
+
+	// request and its events are committed together
+	if err = _transaction.Commit(rr.db, steps, updatedRequest.Id, events...); err != nil {
+		return
+	}
+
 	request = updatedRequest
 
 	return
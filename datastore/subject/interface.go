@@ -0,0 +1,21 @@
+package subject
+
+import (
+	_entity "plain-go/public-library/entity"
+	_model "plain-go/public-library/model"
+)
+
+type Subject interface {
+	GetSubjects(params _model.GetAllSubjectsRequest) (subjects []_entity.Subject, err error)
+	GetSubjectById(subjectId uint) (subject _entity.Subject, err error)
+	GetSubjectByCode(scheme string, code string) (subject _entity.Subject, err error)
+	CreateSubject(newSubject _entity.Subject) (subject _entity.Subject, err error)
+	UpdateSubject(updatedSubject _entity.Subject, oldPath string) (subject _entity.Subject, err error)
+	DeleteSubject(subjectId uint) (err error)
+	GetBooksBySubject(subject _entity.Subject, params _model.GetBooksBySubjectRequest) (books []_entity.Book, err error)
+	CountBooksBySubject(subject _entity.Subject, descendants bool) (count uint, err error)
+	GetBookSubjects(bookId uint) (subjects []_entity.BookSubject, err error)
+	GetCallNumber(bookId uint) (callNumber string, err error)
+	SetBookSubjects(bookId uint, subjectIds []uint, primaryId uint, callNumber string, category string) (err error)
+	SetCallNumber(bookId uint, callNumber string) (err error)
+}
@@ -0,0 +1,529 @@
+package subject
+
+import (
+	"database/sql"
+	"log"
+	_transaction "plain-go/public-library/datastore/transaction"
+	_entity "plain-go/public-library/entity"
+	_model "plain-go/public-library/model"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type SubjectRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *SubjectRepository {
+	return &SubjectRepository{db: db}
+}
+
+// path holds ids from the root such as /1/5/23/, so descendants share the path prefix
+// book count includes books classified under any descendant
+const subjectColumns = `
+	s.id, COALESCE(s.parent_id, 0), s.scheme, s.code, s.name, s.path,
+	(
+		SELECT COUNT(c.id)
+		FROM subjects c
+		WHERE c.parent_id = s.id
+		  AND c.deleted_at IS NULL
+	) AS child_count,
+	(
+		SELECT COUNT(DISTINCT bs.book_id)
+		FROM book_subjects bs
+		JOIN subjects d
+		ON bs.subject_id = d.id
+		JOIN books b
+		ON bs.book_id = b.id
+		WHERE d.path LIKE CONCAT(s.path, '%')
+		  AND d.deleted_at IS NULL
+		  AND b.deleted_at IS NULL
+	) AS book_count,
+	s.created_at, s.updated_at
+`
+
+func scanSubject(row *sql.Rows, subject *_entity.Subject) (err error) {
+	if err = row.Scan(&subject.Id, &subject.ParentId, &subject.Scheme, &subject.Code, &subject.Name, &subject.Path, &subject.ChildCount, &subject.BookCount, &subject.CreatedAt, &subject.UpdatedAt); err != nil {
+		log.Println(err)
+	}
+
+	return
+}
+
+// GetSubjects lists children of the parent, or the whole tree when searching by keyword
+func (sr *SubjectRepository) GetSubjects(params _model.GetAllSubjectsRequest) (subjects []_entity.Subject, err error) {
+	// prepare statement before execution
+	stmt, err := sr.db.Prepare(`
+		SELECT ` + subjectColumns + `
+		FROM subjects s
+		WHERE s.deleted_at IS NULL
+		  AND (? = '' OR s.scheme = ?)
+		  AND (? <> '' OR COALESCE(s.parent_id, 0) = ?)
+		  AND (? = '' OR UPPER(s.name) LIKE ? OR s.code LIKE ?)
+		ORDER BY s.scheme ASC, s.code ASC, s.name ASC
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	keyword := "%" + strings.ToUpper(params.Keyword) + "%"
+	row, err := stmt.Query(params.Scheme, params.Scheme, params.Keyword, params.ParentId, params.Keyword, keyword, params.Keyword+"%")
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		subject := _entity.Subject{}
+
+		if err = scanSubject(row, &subject); err != nil {
+			return
+		}
+
+		subjects = append(subjects, subject)
+	}
+
+	return
+}
+
+func (sr *SubjectRepository) GetSubjectById(subjectId uint) (subject _entity.Subject, err error) {
+	// prepare statement before execution
+	stmt, err := sr.db.Prepare(`
+		SELECT ` + subjectColumns + `
+		FROM subjects s
+		WHERE s.id = ?
+		  AND s.deleted_at IS NULL
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(subjectId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		err = scanSubject(row, &subject)
+	}
+
+	return
+}
+
+func (sr *SubjectRepository) GetSubjectByCode(scheme string, code string) (subject _entity.Subject, err error) {
+	// prepare statement before execution
+	stmt, err := sr.db.Prepare(`
+		SELECT ` + subjectColumns + `
+		FROM subjects s
+		WHERE s.scheme = ?
+		  AND s.code = ?
+		  AND s.deleted_at IS NULL
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(scheme, code)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		err = scanSubject(row, &subject)
+	}
+
+	return
+}
+
+// CreateSubject expects path of the parent in new subject, own id is appended once it is known
+func (sr *SubjectRepository) CreateSubject(newSubject _entity.Subject) (subject _entity.Subject, err error) {
+	// begin transaction, subject is saved together with its path
+	tx, err := sr.db.Begin()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer tx.Rollback()
+
+	// prepare statement before execution
+	stmt, err := tx.Prepare(`
+		INSERT INTO subjects (parent_id, scheme, code, name, path, created_at, updated_at)
+		VALUES (NULLIF(?, 0), ?, ?, ?, '', ?, ?)
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	res, err := stmt.Exec(newSubject.ParentId, newSubject.Scheme, newSubject.Code, newSubject.Name, newSubject.CreatedAt, newSubject.UpdatedAt)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	// get new subject id
+	id, err := res.LastInsertId()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	path := newSubject.Path + strconv.Itoa(int(id)) + "/"
+
+	// prepare statement before execution
+	pathStmt, err := tx.Prepare(`
+		UPDATE subjects
+		SET path = ?
+		WHERE id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer pathStmt.Close()
+
+	// execute statement
+	if _, err = pathStmt.Exec(path, id); err != nil {
+		log.Println(err)
+		return
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Println(err)
+		return
+	}
+
+	subject = newSubject
+	subject.Id = uint(id)
+	subject.Path = path
+
+	return
+}
+
+// UpdateSubject moves descendants along when the path changes
+func (sr *SubjectRepository) UpdateSubject(updatedSubject _entity.Subject, oldPath string) (subject _entity.Subject, err error) {
+	// begin transaction, subject and its descendants are moved together
+	tx, err := sr.db.Begin()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer tx.Rollback()
+
+	steps := []_transaction.Step{
+		{Query: `UPDATE subjects SET parent_id = NULLIF(?, 0), code = ?, name = ?, path = ?, updated_at = ? WHERE id = ?`, Args: []interface{}{updatedSubject.ParentId, updatedSubject.Code, updatedSubject.Name, updatedSubject.Path, updatedSubject.UpdatedAt, updatedSubject.Id}},
+	}
+
+	if updatedSubject.Path != oldPath {
+		steps = append(steps, _transaction.Step{Query: `UPDATE subjects SET path = CONCAT(?, SUBSTRING(path, ?)) WHERE path LIKE ? AND id <> ?`, Args: []interface{}{updatedSubject.Path, len(oldPath) + 1, oldPath + "%", updatedSubject.Id}})
+	}
+
+	if err = _transaction.Exec(tx, steps); err != nil {
+		return
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Println(err)
+		return
+	}
+
+	subject = updatedSubject
+
+	return
+}
+
+func (sr *SubjectRepository) DeleteSubject(subjectId uint) (err error) {
+	// begin transaction, subject is removed from every book as well
+	tx, err := sr.db.Begin()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer tx.Rollback()
+
+	steps := []_transaction.Step{
+		{Query: `DELETE FROM book_subjects WHERE subject_id = ?`, Args: []interface{}{subjectId}},
+		{Query: `UPDATE subjects SET deleted_at = ? WHERE id = ?`, Args: []interface{}{time.Now(), subjectId}},
+	}
+
+	if err = _transaction.Exec(tx, steps); err != nil {
+		return
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+// GetBooksBySubject lists books in shelf order, books without call number come last
+func (sr *SubjectRepository) GetBooksBySubject(subject _entity.Subject, params _model.GetBooksBySubjectRequest) (books []_entity.Book, err error) {
+	// prepare statement before execution
+	stmt, err := sr.db.Prepare(`
+		SELECT b.id, b.title, b.publisher, b.language, b.pages, b.category, b.isbn13, b.description, b.created_at, b.updated_at
+		FROM books b
+		WHERE b.deleted_at IS NULL
+		  AND b.id IN (
+			SELECT bs.book_id
+			FROM book_subjects bs
+			JOIN subjects s
+			ON bs.subject_id = s.id
+			WHERE s.deleted_at IS NULL
+			  AND (s.id = ? OR (? = 1 AND s.path LIKE ?))
+		  )
+		ORDER BY b.call_number IS NULL, b.call_number ASC, b.title ASC
+		LIMIT ? OFFSET ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(subject.Id, params.Descendants, subject.Path+"%", params.Records, (params.Page-1)*params.Records)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		book := _entity.Book{}
+
+		if err = row.Scan(&book.Id, &book.Title, &book.Publisher, &book.Language, &book.Pages, &book.Category, &book.ISBN13, &book.Description, &book.CreatedAt, &book.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+
+		books = append(books, book)
+	}
+
+	return
+}
+
+func (sr *SubjectRepository) CountBooksBySubject(subject _entity.Subject, descendants bool) (count uint, err error) {
+	// prepare statement before execution
+	stmt, err := sr.db.Prepare(`
+		SELECT COUNT(DISTINCT b.id)
+		FROM books b
+		JOIN book_subjects bs
+		ON b.id = bs.book_id
+		JOIN subjects s
+		ON bs.subject_id = s.id
+		WHERE b.deleted_at IS NULL
+		  AND s.deleted_at IS NULL
+		  AND (s.id = ? OR (? = 1 AND s.path LIKE ?))
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(subject.Id, descendants, subject.Path+"%")
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		if err = row.Scan(&count); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
+func (sr *SubjectRepository) GetBookSubjects(bookId uint) (subjects []_entity.BookSubject, err error) {
+	// prepare statement before execution
+	stmt, err := sr.db.Prepare(`
+		SELECT ` + subjectColumns + `, bs.is_primary
+		FROM book_subjects bs
+		JOIN subjects s
+		ON bs.subject_id = s.id
+		WHERE bs.book_id = ?
+		  AND s.deleted_at IS NULL
+		ORDER BY bs.is_primary DESC, s.scheme ASC, s.code ASC
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(bookId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		subject := _entity.BookSubject{}
+
+		if err = row.Scan(&subject.Id, &subject.ParentId, &subject.Scheme, &subject.Code, &subject.Name, &subject.Path, &subject.ChildCount, &subject.BookCount, &subject.CreatedAt, &subject.UpdatedAt, &subject.Primary); err != nil {
+			log.Println(err)
+			return
+		}
+
+		subjects = append(subjects, subject)
+	}
+
+	return
+}
+
+func (sr *SubjectRepository) GetCallNumber(bookId uint) (callNumber string, err error) {
+	// prepare statement before execution
+	stmt, err := sr.db.Prepare(`
+		SELECT COALESCE(call_number, '')
+		FROM books
+		WHERE id = ?
+		  AND deleted_at IS NULL
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(bookId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		if err = row.Scan(&callNumber); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
+// SetBookSubjects replaces subjects of the book, category follows the primary subject for older clients
+func (sr *SubjectRepository) SetBookSubjects(bookId uint, subjectIds []uint, primaryId uint, callNumber string, category string) (err error) {
+	// begin transaction, subjects and call number are saved together
+	tx, err := sr.db.Begin()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer tx.Rollback()
+
+	steps := []_transaction.Step{{Query: `DELETE FROM book_subjects WHERE book_id = ?`, Args: []interface{}{bookId}}}
+
+	for _, subjectId := range subjectIds {
+		steps = append(steps, _transaction.Step{Query: `INSERT INTO book_subjects (book_id, subject_id, is_primary) VALUES (?, ?, ?)`, Args: []interface{}{bookId, subjectId, subjectId == primaryId}})
+	}
+
+	steps = append(steps, _transaction.Step{Query: `UPDATE books SET call_number = NULLIF(?, ''), category = COALESCE(NULLIF(?, ''), category), updated_at = ? WHERE id = ?`, Args: []interface{}{callNumber, category, time.Now(), bookId}})
+
+	if err = _transaction.Exec(tx, steps); err != nil {
+		return
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+func (sr *SubjectRepository) SetCallNumber(bookId uint, callNumber string) (err error) {
+	// prepare statement before execution
+	stmt, err := sr.db.Prepare(`
+		UPDATE books
+		SET call_number = NULLIF(?, ''), updated_at = ?
+		WHERE id = ?
+		  AND deleted_at IS NULL
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(callNumber, time.Now(), bookId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
@@ -0,0 +1,114 @@
+package transaction
+
+import (
+	"database/sql"
+	"log"
+	_outboxRepository "plain-go/public-library/datastore/outbox"
+	_entity "plain-go/public-library/entity"
+)
+
+// Step is a statement with its arguments
+type Step struct {
+	Query string
+	Args  []interface{}
+}
+
+// steps are executed in order within caller's transaction, the first failure stops the rest
+func Exec(tx *sql.Tx, steps []Step) (err error) {
+	for _, step := range steps {
+		// prepare statement before execution
+		stmt, err := tx.Prepare(step.Query)
+
+		if err != nil {
+			log.Println(err)
+			return err
+		}
+
+		// execute statement
+		_, err = stmt.Exec(step.Args...)
+		stmt.Close()
+
+		if err != nil {
+			log.Println(err)
+			return err
+		}
+	}
+
+	return
+}
+
+// Insert executes insert statement within caller's transaction and returns id of the new row
+func Insert(tx *sql.Tx, query string, args ...interface{}) (id uint, err error) {
+	// prepare statement before execution
+	stmt, err := tx.Prepare(query)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	res, err := stmt.Exec(args...)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	// get new row id
+	newId, err := res.LastInsertId()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	id = uint(newId)
+
+	return
+}
+
+// Run applies work in a new transaction and writes events of the aggregate returned by work to outbox,
+// state change and its events are committed together or not at all
+func Run(db *sql.DB, work func(tx *sql.Tx) (aggregateId uint, err error), events ..._entity.Event) (err error) {
+	// begin transaction
+	tx, err := db.Begin()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer tx.Rollback()
+
+	aggregateId, err := work(tx)
+
+	if err != nil {
+		return
+	}
+
+	// write events to outbox
+	for _, event := range events {
+		event.AggregateId = aggregateId
+
+		if err = _outboxRepository.Append(tx, event); err != nil {
+			return
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+// Commit executes steps in a new transaction together with events of the aggregate
+func Commit(db *sql.DB, steps []Step, aggregateId uint, events ..._entity.Event) (err error) {
+	return Run(db, func(tx *sql.Tx) (uint, error) {
+		return aggregateId, Exec(tx, steps)
+	}, events...)
+}
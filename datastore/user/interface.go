@@ -9,6 +9,7 @@ type User interface {
 	CreateNewUser(newUser _entity.User) (user _entity.User, err error)
 	GetAllUsers() (users []_entity.User, err error)
 	GetUserById(userId uint) (user _entity.User, err error)
+	GetUsersByIds(userIds []uint) (users map[uint]_entity.User, err error)
 	UpdateUser(updatedUser _entity.User) (user _entity.User, err error)
 	DeleteUser(userId uint) (err error)
 }
@@ -3,6 +3,7 @@ package user
 import (
 	"log"
 	_entity "plain-go/public-library/entity"
+	"strings"
 	"time"
 
 	"database/sql"
@@ -163,6 +164,59 @@ func (ur *UserRepository) GetUserById(userId uint) (user _entity.User, err error
 	return
 }
 
+// GetUsersByIds reads every user in one query, deleted or unknown user is missing from the map
+func (ur *UserRepository) GetUsersByIds(userIds []uint) (users map[uint]_entity.User, err error) {
+	users = map[uint]_entity.User{}
+
+	if len(userIds) == 0 {
+		return
+	}
+
+	// prepare statement
+	stmt, err := ur.db.Prepare(`
+		SELECT id, role, name, email, phone, password, created_at, updated_at
+		FROM users
+		WHERE deleted_at IS NULL
+		  AND id IN (?` + strings.Repeat(`, ?`, len(userIds)-1) + `)
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	args := []interface{}{}
+
+	for _, userId := range userIds {
+		args = append(args, userId)
+	}
+
+	// execute statement
+	row, err := stmt.Query(args...)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		user := _entity.User{}
+
+		if err = row.Scan(&user.Id, &user.Role, &user.Name, &user.Email, &user.Phone, &user.Password, &user.CreatedAt, &user.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+
+		users[user.Id] = user
+	}
+
+	return
+}
+
 func (ur *UserRepository) UpdateUser(updatedUser _entity.User) (user _entity.User, err error) {
 	// prepare statement
 	stmt, err := ur.db.Prepare(`
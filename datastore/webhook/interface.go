@@ -0,0 +1,20 @@
+package webhook
+
+import (
+	_entity "plain-go/public-library/entity"
+)
+
+type Webhook interface {
+	CreateWebhook(newWebhook _entity.Webhook) (webhook _entity.Webhook, err error)
+	GetAllWebhooks() (webhooks []_entity.Webhook, err error)
+	GetWebhookById(webhookId uint) (webhook _entity.Webhook, err error)
+	GetActiveWebhooksByEvent(eventType string) (webhooks []_entity.Webhook, err error)
+	UpdateWebhook(updatedWebhook _entity.Webhook) (webhook _entity.Webhook, err error)
+	DeleteWebhook(webhookId uint) (err error)
+	CreateDelivery(newDelivery _entity.WebhookDelivery) (delivery _entity.WebhookDelivery, err error)
+	GetDeliveriesByWebhookId(webhookId uint) (deliveries []_entity.WebhookDelivery, err error)
+	GetDeliveryById(deliveryId uint) (delivery _entity.WebhookDelivery, err error)
+	CountDeliveriesByEvent(webhookId uint, eventId string) (attempts uint, delivered bool, err error)
+	GetDueDeliveries(limit int) (deliveries []_entity.WebhookDelivery, err error)
+	CompleteDelivery(updatedDelivery _entity.WebhookDelivery) (delivery _entity.WebhookDelivery, err error)
+}
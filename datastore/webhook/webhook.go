@@ -0,0 +1,454 @@
+package webhook
+
+import (
+	"database/sql"
+	"log"
+	"strings"
+	"time"
+
+	_entity "plain-go/public-library/entity"
+)
+
+type WebhookRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (wr *WebhookRepository) CreateWebhook(newWebhook _entity.Webhook) (webhook _entity.Webhook, err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		INSERT INTO webhooks (url, secret, events, active, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	res, err := stmt.Exec(newWebhook.URL, newWebhook.Secret, strings.Join(newWebhook.Events, ","), newWebhook.Active, newWebhook.CreatedAt, newWebhook.UpdatedAt)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	// get new webhook id
+	id, err := res.LastInsertId()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	webhook = newWebhook
+	webhook.Id = uint(id)
+
+	return
+}
+
+func (wr *WebhookRepository) GetAllWebhooks() (webhooks []_entity.Webhook, err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		SELECT id, url, secret, events, active, created_at, updated_at
+		FROM webhooks
+		WHERE deleted_at IS NULL
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		webhook := _entity.Webhook{}
+		events := ""
+
+		if err = row.Scan(&webhook.Id, &webhook.URL, &webhook.Secret, &events, &webhook.Active, &webhook.CreatedAt, &webhook.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+
+		webhook.Events = strings.Split(events, ",")
+		webhooks = append(webhooks, webhook)
+	}
+
+	return
+}
+
+func (wr *WebhookRepository) GetWebhookById(webhookId uint) (webhook _entity.Webhook, err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		SELECT id, url, secret, events, active, created_at, updated_at
+		FROM webhooks
+		WHERE deleted_at IS NULL
+		  AND id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(webhookId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		events := ""
+
+		if err = row.Scan(&webhook.Id, &webhook.URL, &webhook.Secret, &events, &webhook.Active, &webhook.CreatedAt, &webhook.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+
+		webhook.Events = strings.Split(events, ",")
+	}
+
+	return
+}
+
+func (wr *WebhookRepository) GetActiveWebhooksByEvent(eventType string) (webhooks []_entity.Webhook, err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		SELECT id, url, secret, events, active, created_at, updated_at
+		FROM webhooks
+		WHERE deleted_at IS NULL
+		  AND active = TRUE
+		  AND FIND_IN_SET(?, events) > 0
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(eventType)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		webhook := _entity.Webhook{}
+		events := ""
+
+		if err = row.Scan(&webhook.Id, &webhook.URL, &webhook.Secret, &events, &webhook.Active, &webhook.CreatedAt, &webhook.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+
+		webhook.Events = strings.Split(events, ",")
+		webhooks = append(webhooks, webhook)
+	}
+
+	return
+}
+
+func (wr *WebhookRepository) UpdateWebhook(updatedWebhook _entity.Webhook) (webhook _entity.Webhook, err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		UPDATE webhooks
+		SET url = ?, events = ?, active = ?, updated_at = ?
+		WHERE id = ?
+		  AND deleted_at IS NULL
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(updatedWebhook.URL, strings.Join(updatedWebhook.Events, ","), updatedWebhook.Active, updatedWebhook.UpdatedAt, updatedWebhook.Id)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	webhook = updatedWebhook
+
+	return
+}
+
+func (wr *WebhookRepository) DeleteWebhook(webhookId uint) (err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		UPDATE webhooks
+		SET deleted_at = ?
+		WHERE id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(time.Now(), webhookId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+func (wr *WebhookRepository) CreateDelivery(newDelivery _entity.WebhookDelivery) (delivery _entity.WebhookDelivery, err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, attempt, response_code, error, success, next_attempt_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	res, err := stmt.Exec(newDelivery.WebhookId, newDelivery.EventId, newDelivery.EventType, newDelivery.Payload, newDelivery.Attempt, newDelivery.ResponseCode, newDelivery.Error, newDelivery.Success, newDelivery.NextAttemptAt, newDelivery.CreatedAt)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	// get new delivery id
+	id, err := res.LastInsertId()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	delivery = newDelivery
+	delivery.Id = uint(id)
+
+	return
+}
+
+func (wr *WebhookRepository) GetDeliveriesByWebhookId(webhookId uint) (deliveries []_entity.WebhookDelivery, err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		SELECT id, webhook_id, event_id, event_type, payload, attempt, response_code, error, success, next_attempt_at, created_at
+		FROM webhook_deliveries
+		WHERE webhook_id = ?
+		ORDER BY created_at DESC
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(webhookId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		delivery := _entity.WebhookDelivery{}
+
+		if err = row.Scan(&delivery.Id, &delivery.WebhookId, &delivery.EventId, &delivery.EventType, &delivery.Payload, &delivery.Attempt, &delivery.ResponseCode, &delivery.Error, &delivery.Success, &delivery.NextAttemptAt, &delivery.CreatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return
+}
+
+func (wr *WebhookRepository) GetDeliveryById(deliveryId uint) (delivery _entity.WebhookDelivery, err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		SELECT id, webhook_id, event_id, event_type, payload, attempt, response_code, error, success, next_attempt_at, created_at
+		FROM webhook_deliveries
+		WHERE id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(deliveryId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		if err = row.Scan(&delivery.Id, &delivery.WebhookId, &delivery.EventId, &delivery.EventType, &delivery.Payload, &delivery.Attempt, &delivery.ResponseCode, &delivery.Error, &delivery.Success, &delivery.NextAttemptAt, &delivery.CreatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
+// CountDeliveriesByEvent tells how many times the event was sent to the webhook and whether any succeeded
+func (wr *WebhookRepository) CountDeliveriesByEvent(webhookId uint, eventId string) (attempts uint, delivered bool, err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		SELECT COUNT(id), COALESCE(MAX(success), 0)
+		FROM webhook_deliveries
+		WHERE webhook_id = ?
+		  AND event_id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(webhookId, eventId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		if err = row.Scan(&attempts, &delivered); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
+// GetDueDeliveries returns queued deliveries whose attempt is due, oldest first
+func (wr *WebhookRepository) GetDueDeliveries(limit int) (deliveries []_entity.WebhookDelivery, err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		SELECT id, webhook_id, event_id, event_type, payload, attempt, response_code, error, success, next_attempt_at, created_at
+		FROM webhook_deliveries
+		WHERE next_attempt_at IS NOT NULL
+		  AND next_attempt_at <= ?
+		ORDER BY next_attempt_at ASC, id ASC
+		LIMIT ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(time.Now(), limit)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		delivery := _entity.WebhookDelivery{}
+
+		if err = row.Scan(&delivery.Id, &delivery.WebhookId, &delivery.EventId, &delivery.EventType, &delivery.Payload, &delivery.Attempt, &delivery.ResponseCode, &delivery.Error, &delivery.Success, &delivery.NextAttemptAt, &delivery.CreatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return
+}
+
+// CompleteDelivery records outcome of the attempt and takes the delivery off the queue
+func (wr *WebhookRepository) CompleteDelivery(updatedDelivery _entity.WebhookDelivery) (delivery _entity.WebhookDelivery, err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		UPDATE webhook_deliveries
+		SET response_code = ?, error = ?, success = ?, next_attempt_at = NULL, created_at = ?
+		WHERE id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(updatedDelivery.ResponseCode, updatedDelivery.Error, updatedDelivery.Success, updatedDelivery.CreatedAt, updatedDelivery.Id)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	delivery = updatedDelivery
+	delivery.NextAttemptAt = nil
+
+	return
+}
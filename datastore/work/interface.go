@@ -0,0 +1,27 @@
+package work
+
+import (
+	_entity "plain-go/public-library/entity"
+	_model "plain-go/public-library/model"
+)
+
+type Work interface {
+	GetAllWorks(params _model.GetAllWorksRequest) (works []_entity.Work, err error)
+	CountWorks(params _model.GetAllWorksRequest) (count uint, err error)
+	CreateWork(newWork _entity.Work, bookIds []uint) (work _entity.Work, err error)
+	GetWorkById(workId uint) (work _entity.Work, err error)
+	GetWorkIdByBookId(bookId uint) (workId uint, err error)
+	UpdateWork(updatedWork _entity.Work) (work _entity.Work, err error)
+	GetEditions(workId uint) (books []_entity.Book, err error)
+	SetBookWork(bookId uint, workId uint) (err error)
+	GetReviewsByWorkId(workId uint, params _model.GetAllReviewsByBookIdRequest) (reviews []_entity.Review, err error)
+	GetAvailableBookItemByWorkId(workId uint) (bookItemId uint, err error)
+	GetAllSeries() (series []_entity.Series, err error)
+	CreateSeries(newSeries _entity.Series) (series _entity.Series, err error)
+	GetSeriesById(seriesId uint) (series _entity.Series, err error)
+	UpdateSeries(updatedSeries _entity.Series) (series _entity.Series, err error)
+	GetSeriesWorks(seriesId uint) (entries []_entity.SeriesEntry, err error)
+	GetSeriesByWorkId(workId uint) (entries []_entity.SeriesEntry, err error)
+	SaveSeriesWork(seriesId uint, workId uint, position uint) (err error)
+	RemoveSeriesWork(seriesId uint, workId uint) (err error)
+}
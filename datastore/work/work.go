@@ -0,0 +1,805 @@
+package work
+
+import (
+	"database/sql"
+	"log"
+	_bookRepository "plain-go/public-library/datastore/book"
+	_requestRepository "plain-go/public-library/datastore/request"
+	_entity "plain-go/public-library/entity"
+	_helper "plain-go/public-library/helper"
+	_model "plain-go/public-library/model"
+	"strings"
+	"time"
+)
+
+type WorkRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *WorkRepository {
+	return &WorkRepository{db: db}
+}
+
+// ratings and favorites are aggregated over every edition of the work
+const workColumns = `
+	w.id, w.title, w.description,
+	(
+		SELECT COUNT(b.id)
+		FROM books b
+		WHERE b.work_id = w.id
+		  AND b.deleted_at IS NULL
+	) AS edition_count,
+	(
+		SELECT COALESCE(AVG(r.star), 0)
+		FROM reviews r
+		JOIN books b
+		ON r.book_id = b.id
+		WHERE b.work_id = w.id
+		  AND b.deleted_at IS NULL
+		  AND r.status = 'published'
+		  AND r.deleted_at IS NULL
+	) AS average_star,
+	(
+		SELECT COUNT(r.id)
+		FROM reviews r
+		JOIN books b
+		ON r.book_id = b.id
+		WHERE b.work_id = w.id
+		  AND b.deleted_at IS NULL
+		  AND r.status = 'published'
+		  AND r.deleted_at IS NULL
+	) AS review_count,
+	(
+		SELECT COUNT(f.id)
+		FROM favorites f
+		JOIN books b
+		ON f.book_id = b.id
+		WHERE b.work_id = w.id
+		  AND b.deleted_at IS NULL
+		  AND f.deleted_at IS NULL
+	) AS favorite_count,
+	w.created_at, w.updated_at
+`
+
+func scanWork(row *sql.Rows, work *_entity.Work, extra ...interface{}) (err error) {
+	var averageStar float64
+
+	dest := append([]interface{}{&work.Id, &work.Title, &work.Description, &work.EditionCount, &averageStar, &work.ReviewCount, &work.FavoriteCount, &work.CreatedAt, &work.UpdatedAt}, extra...)
+
+	if err = row.Scan(dest...); err != nil {
+		log.Println(err)
+		return
+	}
+
+	work.AverageStar = _helper.NilHandler(averageStar)
+
+	return
+}
+
+func (wr *WorkRepository) GetAllWorks(params _model.GetAllWorksRequest) (works []_entity.Work, err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		SELECT ` + workColumns + `
+		FROM works w
+		WHERE w.deleted_at IS NULL
+		  AND (? = '' OR UPPER(w.title) LIKE ?)
+		ORDER BY w.title ASC
+		LIMIT ? OFFSET ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	keyword := "%" + strings.ToUpper(params.Keyword) + "%"
+	row, err := stmt.Query(params.Keyword, keyword, params.Records, (params.Page-1)*params.Records)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		work := _entity.Work{}
+
+		if err = scanWork(row, &work); err != nil {
+			return
+		}
+
+		works = append(works, work)
+	}
+
+	return
+}
+
+func (wr *WorkRepository) CountWorks(params _model.GetAllWorksRequest) (count uint, err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		SELECT COUNT(id)
+		FROM works
+		WHERE deleted_at IS NULL
+		  AND (? = '' OR UPPER(title) LIKE ?)
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(params.Keyword, "%"+strings.ToUpper(params.Keyword)+"%")
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		if err = row.Scan(&count); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
+func (wr *WorkRepository) CreateWork(newWork _entity.Work, bookIds []uint) (work _entity.Work, err error) {
+	// begin transaction, work and its editions are saved together
+	tx, err := wr.db.Begin()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer tx.Rollback()
+
+	// prepare statement before execution
+	stmt, err := tx.Prepare(`
+		INSERT INTO works (title, description, created_at, updated_at)
+		VALUES (?, ?, ?, ?)
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	res, err := stmt.Exec(newWork.Title, newWork.Description, newWork.CreatedAt, newWork.UpdatedAt)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	// get new work id
+	id, err := res.LastInsertId()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	// prepare statement before execution
+	bookStmt, err := tx.Prepare(`
+		UPDATE books
+		SET work_id = ?
+		WHERE id = ?
+		  AND deleted_at IS NULL
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer bookStmt.Close()
+
+	for _, bookId := range bookIds {
+		// execute statement
+		if _, err = bookStmt.Exec(id, bookId); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Println(err)
+		return
+	}
+
+	work = newWork
+	work.Id = uint(id)
+	work.EditionCount = uint(len(bookIds))
+
+	return
+}
+
+func (wr *WorkRepository) GetWorkById(workId uint) (work _entity.Work, err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		SELECT ` + workColumns + `
+		FROM works w
+		WHERE w.id = ?
+		  AND w.deleted_at IS NULL
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(workId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		err = scanWork(row, &work)
+	}
+
+	return
+}
+
+func (wr *WorkRepository) GetWorkIdByBookId(bookId uint) (workId uint, err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		SELECT COALESCE(b.work_id, 0)
+		FROM books b
+		LEFT JOIN works w
+		ON b.work_id = w.id
+		WHERE b.id = ?
+		  AND w.deleted_at IS NULL
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(bookId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		if err = row.Scan(&workId); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
+func (wr *WorkRepository) UpdateWork(updatedWork _entity.Work) (work _entity.Work, err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		UPDATE works
+		SET title = ?, description = ?, updated_at = ?
+		WHERE id = ?
+		  AND deleted_at IS NULL
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(updatedWork.Title, updatedWork.Description, updatedWork.UpdatedAt, updatedWork.Id)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	work = updatedWork
+
+	return
+}
+
+func (wr *WorkRepository) GetEditions(workId uint) (books []_entity.Book, err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		SELECT id, title, publisher, language, pages, category, isbn13, description, created_at, updated_at
+		FROM books
+		WHERE work_id = ?
+		  AND deleted_at IS NULL
+		ORDER BY language ASC, created_at ASC
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(workId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		book := _entity.Book{}
+
+		if err = row.Scan(&book.Id, &book.Title, &book.Publisher, &book.Language, &book.Pages, &book.Category, &book.ISBN13, &book.Description, &book.CreatedAt, &book.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+
+		books = append(books, book)
+	}
+
+	return
+}
+
+// SetBookWork attaches book as an edition of the work, zero work id detaches it
+func (wr *WorkRepository) SetBookWork(bookId uint, workId uint) (err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		UPDATE books
+		SET work_id = NULLIF(?, 0), updated_at = ?
+		WHERE id = ?
+		  AND deleted_at IS NULL
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(workId, time.Now(), bookId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+func (wr *WorkRepository) GetReviewsByWorkId(workId uint, params _model.GetAllReviewsByBookIdRequest) (reviews []_entity.Review, err error) {
+	// basic query
+	query := (`
+		SELECT r.id, r.user_id, r.book_id, r.star, r.content, r.created_at, r.updated_at,
+			` + _requestRepository.VerifiedBorrower + ` AS verified_borrower,
+			COALESCE(SUM(v.helpful = 1), 0) AS helpful_count,
+			COALESCE(SUM(v.helpful = 0), 0) AS unhelpful_count
+		FROM reviews r
+		JOIN books b
+		ON r.book_id = b.id
+		LEFT JOIN review_votes v
+		ON r.id = v.review_id
+		WHERE b.work_id = ?
+		  AND b.deleted_at IS NULL
+		  AND r.status = 'published'
+		  AND r.deleted_at IS NULL
+		GROUP BY r.id, r.user_id, r.book_id, r.star, r.content, r.created_at, r.updated_at
+	`)
+
+	// sort by
+	order, exist := _bookRepository.ReviewOrders[params.SortBy]
+
+	if !exist {
+		order = _bookRepository.ReviewOrders["newest"]
+	}
+
+	query += ` ORDER BY ` + order
+
+	// page and records
+	query += ` LIMIT ? OFFSET ?`
+
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(query)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(workId, params.Records, (params.Page-1)*params.Records)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		review := _entity.Review{}
+
+		if err = row.Scan(&review.Id, &review.User.Id, &review.BookId, &review.Star, &review.Content, &review.CreatedAt, &review.UpdatedAt, &review.VerifiedBorrower, &review.HelpfulCount, &review.UnhelpfulCount); err != nil {
+			log.Println(err)
+			return
+		}
+
+		reviews = append(reviews, review)
+	}
+
+	return
+}
+
+// GetAvailableBookItemByWorkId picks the first available copy of any edition
+func (wr *WorkRepository) GetAvailableBookItemByWorkId(workId uint) (bookItemId uint, err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		SELECT bi.id
+		FROM book_items bi
+		JOIN books b
+		ON bi.book_id = b.id
+		WHERE b.work_id = ?
+		  AND b.deleted_at IS NULL
+		  AND bi.status = 'available'
+		ORDER BY bi.id ASC
+		LIMIT 1
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(workId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		if err = row.Scan(&bookItemId); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
+func (wr *WorkRepository) GetAllSeries() (series []_entity.Series, err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		SELECT s.id, s.name, s.description,
+			(
+				SELECT COUNT(sw.work_id)
+				FROM series_works sw
+				JOIN works w
+				ON sw.work_id = w.id
+				WHERE sw.series_id = s.id
+				  AND w.deleted_at IS NULL
+			) AS work_count,
+			s.created_at, s.updated_at
+		FROM series s
+		WHERE s.deleted_at IS NULL
+		ORDER BY s.name ASC
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		item := _entity.Series{}
+
+		if err = row.Scan(&item.Id, &item.Name, &item.Description, &item.WorkCount, &item.CreatedAt, &item.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+
+		series = append(series, item)
+	}
+
+	return
+}
+
+func (wr *WorkRepository) CreateSeries(newSeries _entity.Series) (series _entity.Series, err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		INSERT INTO series (name, description, created_at, updated_at)
+		VALUES (?, ?, ?, ?)
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	res, err := stmt.Exec(newSeries.Name, newSeries.Description, newSeries.CreatedAt, newSeries.UpdatedAt)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	// get new series id
+	id, err := res.LastInsertId()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	series = newSeries
+	series.Id = uint(id)
+
+	return
+}
+
+func (wr *WorkRepository) GetSeriesById(seriesId uint) (series _entity.Series, err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		SELECT s.id, s.name, s.description,
+			(
+				SELECT COUNT(sw.work_id)
+				FROM series_works sw
+				JOIN works w
+				ON sw.work_id = w.id
+				WHERE sw.series_id = s.id
+				  AND w.deleted_at IS NULL
+			) AS work_count,
+			s.created_at, s.updated_at
+		FROM series s
+		WHERE s.id = ?
+		  AND s.deleted_at IS NULL
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(seriesId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	if row.Next() {
+		if err = row.Scan(&series.Id, &series.Name, &series.Description, &series.WorkCount, &series.CreatedAt, &series.UpdatedAt); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	return
+}
+
+func (wr *WorkRepository) UpdateSeries(updatedSeries _entity.Series) (series _entity.Series, err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		UPDATE series
+		SET name = ?, description = ?, updated_at = ?
+		WHERE id = ?
+		  AND deleted_at IS NULL
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(updatedSeries.Name, updatedSeries.Description, updatedSeries.UpdatedAt, updatedSeries.Id)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	series = updatedSeries
+
+	return
+}
+
+func (wr *WorkRepository) GetSeriesWorks(seriesId uint) (entries []_entity.SeriesEntry, err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		SELECT ` + workColumns + `, sw.series_id, s.name, sw.position
+		FROM series_works sw
+		JOIN series s
+		ON sw.series_id = s.id
+		JOIN works w
+		ON sw.work_id = w.id
+		WHERE sw.series_id = ?
+		  AND w.deleted_at IS NULL
+		ORDER BY sw.position ASC, w.title ASC
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(seriesId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		entry := _entity.SeriesEntry{Work: &_entity.Work{}}
+
+		if err = scanWork(row, entry.Work, &entry.SeriesId, &entry.SeriesName, &entry.Position); err != nil {
+			return
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return
+}
+
+func (wr *WorkRepository) GetSeriesByWorkId(workId uint) (entries []_entity.SeriesEntry, err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		SELECT s.id, s.name, sw.position
+		FROM series_works sw
+		JOIN series s
+		ON sw.series_id = s.id
+		WHERE sw.work_id = ?
+		  AND s.deleted_at IS NULL
+		ORDER BY s.name ASC
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	row, err := stmt.Query(workId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		entry := _entity.SeriesEntry{}
+
+		if err = row.Scan(&entry.SeriesId, &entry.SeriesName, &entry.Position); err != nil {
+			log.Println(err)
+			return
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return
+}
+
+// SaveSeriesWork adds work to series or moves it to another position
+func (wr *WorkRepository) SaveSeriesWork(seriesId uint, workId uint, position uint) (err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		INSERT INTO series_works (series_id, work_id, position)
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE position = VALUES(position)
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(seriesId, workId, position)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+func (wr *WorkRepository) RemoveSeriesWork(seriesId uint, workId uint) (err error) {
+	// prepare statement before execution
+	stmt, err := wr.db.Prepare(`
+		DELETE FROM series_works
+		WHERE series_id = ?
+		  AND work_id = ?
+	`)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer stmt.Close()
+
+	// execute statement
+	_, err = stmt.Exec(seriesId, workId)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
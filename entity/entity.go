@@ -26,6 +26,8 @@ type Book struct {
 	Quantity      uint        `json:"quantity"`
 	FavoriteCount uint        `json:"favorite_count"`
 	AverageStar   interface{} `json:"average_star"`
+	Cover         *BookCover  `json:"cover,omitempty"`
+	Locale        string      `json:"locale,omitempty"`
 	CreatedAt     time.Time   `json:"created_at"`
 	UpdatedAt     time.Time   `json:"updated_at"`
 	// ReadCount     uint        `json:"read_count"`
@@ -44,23 +46,42 @@ type Author struct {
 }
 
 type Review struct {
-	Id        uint      `json:"id"`
-	User      User      `json:"reviewer"`
-	Star      uint      `json:"star"`
-	Content   string    `json:"content"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Id               uint          `json:"id"`
+	User             User          `json:"reviewer"`
+	BookId           uint          `json:"book_id,omitempty"`
+	Star             uint          `json:"star"`
+	Content          string        `json:"content"`
+	VerifiedBorrower bool          `json:"verified_borrower"`
+	HelpfulCount     uint          `json:"helpful_count"`
+	UnhelpfulCount   uint          `json:"unhelpful_count"`
+	Replies          []ReviewReply `json:"replies"`
+	CreatedAt        time.Time     `json:"created_at"`
+	UpdatedAt        time.Time     `json:"updated_at"`
+}
+
+type ReviewReply struct {
+	Id              uint      `json:"id"`
+	ReviewId        uint      `json:"review_id"`
+	User            User      `json:"author"`
+	Content         string    `json:"content"`
+	Status          string    `json:"status"`
+	ScreeningReason string    `json:"screening_reason,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
 }
 
 type SimplifiedReview struct {
-	Id        uint      `json:"id"`
-	User      User      `json:"reviewer"`
-	Book      Book      `json:"book_reviewed"`
-	Star      uint      `json:"star"`
-	Content   string    `json:"content"`
-	Flag      uint      `json:"flag"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Id               uint      `json:"id"`
+	User             User      `json:"reviewer"`
+	Book             Book      `json:"book_reviewed"`
+	Star             uint      `json:"star"`
+	Content          string    `json:"content"`
+	VerifiedBorrower bool      `json:"verified_borrower"`
+	Flag             uint      `json:"flag"`
+	Status           string    `json:"status"`
+	ScreeningReason  string    `json:"screening_reason,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
 }
 
 type Request struct {
@@ -69,6 +90,8 @@ type Request struct {
 	User      User          `json:"user"`
 	Status    RequestStatus `json:"status"`
 	Extended  uint          `json:"extended"`
+	WorkId    uint          `json:"work_id,omitempty"`
+	BookId    uint          `json:"book_id,omitempty"`
 	CreatedAt time.Time     `json:"created_at"`
 	StartAt   interface{}   `json:"start_at"`
 	FinishAt  interface{}   `json:"finish_at"`
@@ -121,3 +144,312 @@ type SimplifiedWish struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+type Notification struct {
+	Id        uint        `json:"id"`
+	User      User        `json:"-"`
+	Kind      string      `json:"kind"`
+	Subject   string      `json:"subject"`
+	Content   string      `json:"content"`
+	Reference string      `json:"-"`
+	ReadAt    interface{} `json:"read_at"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type NotificationPreference struct {
+	UserId        uint      `json:"user_id"`
+	Email         bool      `json:"email"`
+	SMS           bool      `json:"sms"`
+	InApp         bool      `json:"in_app"`
+	DaysBeforeDue uint      `json:"days_before_due"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type Event struct {
+	Id            string      `json:"id"`
+	Type          string      `json:"type"`
+	AggregateType string      `json:"aggregate_type"`
+	AggregateId   uint        `json:"aggregate_id"`
+	UserId        uint        `json:"user_id"`
+	Payload       interface{} `json:"payload"`
+	OccurredAt    time.Time   `json:"occurred_at"`
+	Attempts      uint        `json:"-"`
+}
+
+type Webhook struct {
+	Id        uint      `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	Id            uint        `json:"id"`
+	WebhookId     uint        `json:"webhook_id"`
+	EventId       string      `json:"event_id"`
+	EventType     string      `json:"event_type"`
+	Payload       string      `json:"payload"`
+	Attempt       uint        `json:"attempt"`
+	ResponseCode  int         `json:"response_code"`
+	Error         string      `json:"error"`
+	Success       bool        `json:"success"`
+	NextAttemptAt interface{} `json:"next_attempt_at"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+type ReviewReport struct {
+	Id         uint        `json:"id"`
+	ReviewId   uint        `json:"review_id"`
+	ReplyId    uint        `json:"reply_id,omitempty"`
+	User       User        `json:"reporter"`
+	Reason     string      `json:"reason"`
+	Note       string      `json:"note"`
+	ResolvedAt interface{} `json:"resolved_at"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+type ModerationQueueItem struct {
+	Review         SimplifiedReview `json:"review"`
+	ReportCount    uint             `json:"report_count"`
+	Reports        []ReviewReport   `json:"reports"`
+	LastReportedAt time.Time        `json:"last_reported_at"`
+}
+
+type ReplyModerationQueueItem struct {
+	Reply          ReviewReply    `json:"reply"`
+	ReportCount    uint           `json:"report_count"`
+	Reports        []ReviewReport `json:"reports"`
+	LastReportedAt time.Time      `json:"last_reported_at"`
+}
+
+type ModerationAction struct {
+	Id        uint      `json:"id"`
+	ReviewId  uint      `json:"review_id"`
+	ReplyId   uint      `json:"reply_id,omitempty"`
+	Librarian User      `json:"librarian"`
+	Action    string    `json:"action"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ReviewVote struct {
+	ReviewId  uint      `json:"review_id"`
+	UserId    uint      `json:"user_id"`
+	Helpful   bool      `json:"helpful"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type AcquisitionCandidate struct {
+	Id           uint             `json:"id"`
+	Key          string           `json:"-"`
+	Title        string           `json:"title"`
+	Authors      string           `json:"authors"`
+	Category     string           `json:"category"`
+	Status       string           `json:"status"`
+	RejectReason string           `json:"reject_reason"`
+	Budget       float64          `json:"budget"`
+	Vendor       string           `json:"vendor"`
+	BookId       uint             `json:"book_id"`
+	WishCount    uint             `json:"wish_count"`
+	VoteCount    uint             `json:"vote_count"`
+	Wishes       []SimplifiedWish `json:"wishes,omitempty"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+}
+
+type Suggestion struct {
+	Id        uint      `json:"id"`
+	Title     string    `json:"title"`
+	Authors   string    `json:"authors"`
+	Category  string    `json:"category"`
+	Status    string    `json:"status"`
+	WishCount uint      `json:"wish_count"`
+	VoteCount uint      `json:"vote_count"`
+	Voted     bool      `json:"voted"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PossibleDuplicate struct {
+	Type  string  `json:"type"`
+	Id    uint    `json:"id"`
+	Name  string  `json:"name"`
+	Score float64 `json:"score"`
+}
+
+type AuthorDetail struct {
+	Id           uint               `json:"id"`
+	Name         string             `json:"name"`
+	Biography    string             `json:"biography"`
+	BirthYear    *uint              `json:"birth_year"`
+	DeathYear    *uint              `json:"death_year"`
+	ExternalIds  map[string]string  `json:"external_ids"`
+	BookCount    uint               `json:"book_count"`
+	Bibliography []Book             `json:"bibliography,omitempty"`
+	BorrowStats  *AuthorBorrowStats `json:"borrow_stats,omitempty"`
+}
+
+type AuthorBorrowStats struct {
+	BorrowCount       uint `json:"borrow_count"`
+	ActiveBorrowCount uint `json:"active_borrow_count"`
+	BorrowerCount     uint `json:"borrower_count"`
+}
+
+type ReadingList struct {
+	Id          uint              `json:"id"`
+	Owner       ReadingListOwner  `json:"owner"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Visibility  string            `json:"visibility"`
+	ShareToken  string            `json:"share_token,omitempty"`
+	Featured    bool              `json:"featured"`
+	ItemCount   uint              `json:"item_count"`
+	Items       []ReadingListItem `json:"items,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+type ReadingListOwner struct {
+	Id   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type ReadingListItem struct {
+	Book      Book      `json:"book"`
+	Position  uint      `json:"position"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"added_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ImportedRecord struct {
+	Index   uint   `json:"index"`
+	Title   string `json:"title"`
+	ISBN13  string `json:"isbn13"`
+	Status  string `json:"status"`
+	BookId  uint   `json:"book_id,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// exported book carries the copies of the book in Quantity and how many of them are on the shelf
+type ExportedBook struct {
+	Book
+	Available uint `json:"available"`
+}
+
+// harvested book is stamped with the time of its last change, deletion included
+type HarvestedBook struct {
+	ExportedBook
+	Deleted   bool      `json:"deleted"`
+	Datestamp time.Time `json:"datestamp"`
+}
+
+type BookCover struct {
+	BookId      uint              `json:"book_id"`
+	ContentType string            `json:"content_type"`
+	Size        uint              `json:"size"`
+	Width       uint              `json:"width"`
+	Height      uint              `json:"height"`
+	ETag        string            `json:"etag"`
+	BlobPrefix  string            `json:"-"`
+	URLs        map[string]string `json:"urls"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// translated title and description of a book, locale is a lowercase language tag such as pt-br
+type BookTranslation struct {
+	BookId      uint      `json:"book_id"`
+	Locale      string    `json:"locale"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// work groups editions and translations of the same title, rating is aggregated over all editions
+type Work struct {
+	Id            uint          `json:"id"`
+	Title         string        `json:"title"`
+	Description   string        `json:"description"`
+	EditionCount  uint          `json:"edition_count"`
+	AverageStar   interface{}   `json:"average_star"`
+	ReviewCount   uint          `json:"review_count"`
+	FavoriteCount uint          `json:"favorite_count"`
+	Editions      []Book        `json:"editions,omitempty"`
+	Series        []SeriesEntry `json:"series,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+type Series struct {
+	Id          uint          `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	WorkCount   uint          `json:"work_count"`
+	Works       []SeriesEntry `json:"works,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+type SeriesEntry struct {
+	SeriesId   uint   `json:"series_id"`
+	SeriesName string `json:"series_name"`
+	Position   uint   `json:"position"`
+	Work       *Work  `json:"work,omitempty"`
+}
+
+// subject is a node of the classification tree, either a Dewey class or a local subject heading
+type Subject struct {
+	Id         uint      `json:"id"`
+	ParentId   uint      `json:"parent_id,omitempty"`
+	Scheme     string    `json:"scheme"`
+	Code       string    `json:"code"`
+	Name       string    `json:"name"`
+	Path       string    `json:"-"`
+	ChildCount uint      `json:"child_count"`
+	BookCount  uint      `json:"book_count"`
+	Ancestors  []Subject `json:"ancestors,omitempty"`
+	Children   []Subject `json:"children,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type BookSubject struct {
+	Subject
+	Primary bool `json:"primary"`
+}
+
+type BookClassification struct {
+	BookId     uint          `json:"book_id"`
+	CallNumber string        `json:"call_number"`
+	Subjects   []BookSubject `json:"subjects"`
+}
+
+// edition of Open Library dump indexed by ISBN-13, author and work keys refer to records of the same dump
+type OpenLibraryEdition struct {
+	ISBN13      string
+	Title       string
+	Publisher   string
+	Pages       uint
+	Language    string
+	AuthorKeys  []string
+	WorkKey     string
+	Description string
+	Subject     string
+}
+
+type OpenLibraryWork struct {
+	Key         string
+	Description string
+	Subject     string
+	AuthorKeys  []string
+}
+
+type OpenLibraryAuthor struct {
+	Key  string
+	Name string
+}
@@ -0,0 +1,75 @@
+package helper
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ten main classes of the Dewey Decimal Classification
+var DeweyClasses = map[string]string{
+	"000": "Computer science, information and general works",
+	"100": "Philosophy and psychology",
+	"200": "Religion",
+	"300": "Social sciences",
+	"400": "Language",
+	"500": "Science",
+	"600": "Technology",
+	"700": "Arts and recreation",
+	"800": "Literature",
+	"900": "History and geography",
+}
+
+var deweyPattern = regexp.MustCompile(`^[0-9]{3}(\.[0-9]+)?$`)
+
+func IsValidDewey(code string) bool {
+	return deweyPattern.MatchString(code)
+}
+
+// DeweyAncestors lists broader class numbers from the nearest, such as 005.13, 005.1, 005, 000 for 005.133
+func DeweyAncestors(code string) (ancestors []string) {
+	if !IsValidDewey(code) {
+		return
+	}
+
+	// decimal digits are dropped one by one
+	for strings.Contains(code, ".") {
+		code = strings.TrimSuffix(code[:len(code)-1], ".")
+		ancestors = append(ancestors, code)
+	}
+
+	// section belongs to division, division belongs to main class
+	for _, broader := range []string{code[:2] + "0", code[:1] + "00"} {
+		if broader != code && (len(ancestors) == 0 || ancestors[len(ancestors)-1] != broader) {
+			ancestors = append(ancestors, broader)
+		}
+
+		code = broader
+	}
+
+	return
+}
+
+// CallNumber builds shelf mark from class number and a cutter of the main author surname,
+// title is used when the book has no author, such as 005.133 KER
+func CallNumber(classNumber string, author string, title string) string {
+	entry := title
+
+	if fields := strings.Fields(author); len(fields) > 0 {
+		entry = fields[len(fields)-1]
+	}
+
+	cutter := []rune{}
+
+	for _, r := range strings.ToUpper(entry) {
+		if unicode.IsLetter(r) {
+			cutter = append(cutter, r)
+		}
+
+		if len(cutter) == 3 {
+			break
+		}
+	}
+
+	return strings.TrimSpace(classNumber + " " + string(cutter))
+}
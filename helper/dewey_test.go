@@ -0,0 +1,54 @@
+package helper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeweyAncestors(t *testing.T) {
+	tests := []struct {
+		code string
+		want []string
+	}{
+		{"005.133", []string{"005.13", "005.1", "005", "000"}},
+		{"512.70", []string{"512.7", "512", "510", "500"}},
+		{"512", []string{"510", "500"}},
+		{"510", []string{"500"}},
+		{"330.9", []string{"330", "300"}},
+		{"300", nil},
+		{"000", nil},
+		{"51", nil},
+		{"512.", nil},
+		{"5a2", nil},
+	}
+
+	for _, test := range tests {
+		if got := DeweyAncestors(test.code); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("DeweyAncestors(%q) = %q, want %q", test.code, got, test.want)
+		}
+	}
+}
+
+func TestCallNumber(t *testing.T) {
+	tests := []struct {
+		classNumber string
+		author      string
+		title       string
+		want        string
+	}{
+		{"005.133", "Brian W. Kernighan", "The C Programming Language", "005.133 KER"},
+		{"823.914", "", "Ulysses", "823.914 ULY"},
+		{"823.914", "Flann O'Brien", "The Third Policeman", "823.914 OBR"},
+		{"306.09", "Ann Smith-Jones", "", "306.09 SMI"},
+		{"539.7", "Anders Ångström", "", "539.7 ÅNG"},
+		{"495.1", "Li", "", "495.1 LI"},
+		{"823.912", "", "1984", "823.912"},
+		{"", "George Orwell", "Animal Farm", "ORW"},
+	}
+
+	for _, test := range tests {
+		if got := CallNumber(test.classNumber, test.author, test.title); got != test.want {
+			t.Errorf("CallNumber(%q, %q, %q) = %q, want %q", test.classNumber, test.author, test.title, got, test.want)
+		}
+	}
+}
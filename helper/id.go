@@ -0,0 +1,19 @@
+package helper
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+func GenerateId() string {
+	b := make([]byte, 16)
+
+	// fallback to timestamp in the unlikely case random source fails
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,211 @@
+package helper
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// NormalizeISBN strips hyphen and space, check character x is uppercased
+func NormalizeISBN(isbn string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(isbn)))
+}
+
+func IsValidISBN10(isbn string) bool {
+	if len(isbn) != 10 {
+		return false
+	}
+
+	sum := 0
+
+	for i, r := range isbn {
+		digit := int(r - '0')
+
+		// X stands for 10 and is only allowed as check character
+		if r == 'X' && i == 9 {
+			digit = 10
+		} else if r < '0' || r > '9' {
+			return false
+		}
+
+		sum += digit * (10 - i)
+	}
+
+	return sum%11 == 0
+}
+
+func IsValidISBN13(isbn string) bool {
+	if len(isbn) != 13 || !(strings.HasPrefix(isbn, "978") || strings.HasPrefix(isbn, "979")) {
+		return false
+	}
+
+	for _, r := range isbn {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return isbn13CheckDigit(isbn[:12]) == isbn[12:]
+}
+
+func isbn13CheckDigit(digits string) string {
+	sum := 0
+
+	for i, r := range digits {
+		weight := 1
+
+		if i%2 == 1 {
+			weight = 3
+		}
+
+		sum += int(r-'0') * weight
+	}
+
+	return strconv.Itoa((10 - sum%10) % 10)
+}
+
+func isbn10CheckDigit(digits string) string {
+	sum := 0
+
+	for i, r := range digits {
+		sum += int(r-'0') * (10 - i)
+	}
+
+	check := (11 - sum%11) % 11
+
+	if check == 10 {
+		return "X"
+	}
+
+	return strconv.Itoa(check)
+}
+
+func ISBN10To13(isbn string) (isbn13 string, err error) {
+	isbn = NormalizeISBN(isbn)
+
+	if !IsValidISBN10(isbn) {
+		err = errors.New("invalid isbn-10")
+		return
+	}
+
+	isbn13 = "978" + isbn[:9]
+	isbn13 += isbn13CheckDigit(isbn13)
+	return
+}
+
+// ISBN13To10 only converts 978 prefix, 979 has no ISBN-10 form
+func ISBN13To10(isbn string) (isbn10 string, err error) {
+	isbn = NormalizeISBN(isbn)
+
+	if !IsValidISBN13(isbn) {
+		err = errors.New("invalid isbn-13")
+		return
+	}
+
+	if !strings.HasPrefix(isbn, "978") {
+		err = errors.New("isbn-13 has no isbn-10 form")
+		return
+	}
+
+	isbn10 = isbn[3:12] + isbn10CheckDigit(isbn[3:12])
+	return
+}
+
+// ParseISBN accepts either form, with or without hyphen, and returns ISBN-13
+func ParseISBN(isbn string) (isbn13 string, err error) {
+	isbn = NormalizeISBN(isbn)
+
+	switch len(isbn) {
+	case 10:
+		return ISBN10To13(isbn)
+	case 13:
+		if !IsValidISBN13(isbn) {
+			err = errors.New("invalid isbn-13")
+			return
+		}
+
+		return isbn, nil
+	}
+
+	err = errors.New("isbn must be 10 or 13 characters")
+	return
+}
+
+// registration group length by leading digits, following the ranges of the International ISBN Agency
+var isbnGroupRanges = []struct {
+	prefix string
+	from   string
+	to     string
+	length int
+}{
+	{"978", "0", "5", 1},
+	{"978", "600", "649", 3},
+	{"978", "65", "65", 2},
+	{"978", "7", "7", 1},
+	{"978", "80", "94", 2},
+	{"978", "950", "989", 3},
+	{"978", "9900", "9989", 4},
+	{"978", "99900", "99999", 5},
+	{"979", "10", "13", 2},
+	{"979", "8", "8", 1},
+}
+
+// area of well known registration groups
+var isbnGroupAreas = map[string]string{
+	"978-0":   "English language",
+	"978-1":   "English language",
+	"978-2":   "French language",
+	"978-3":   "German language",
+	"978-4":   "Japan",
+	"978-5":   "former U.S.S.R.",
+	"978-602": "Indonesia",
+	"978-623": "Indonesia",
+	"978-634": "Indonesia",
+	"978-65":  "Brazil",
+	"978-7":   "China",
+	"978-81":  "India",
+	"978-83":  "Poland",
+	"978-84":  "Spain",
+	"978-85":  "Brazil",
+	"978-87":  "Denmark",
+	"978-88":  "Italy",
+	"978-89":  "Korea",
+	"978-90":  "Netherlands",
+	"978-91":  "Sweden",
+	"978-93":  "India",
+	"978-967": "Malaysia",
+	"978-979": "Indonesia",
+	"978-981": "Singapore",
+	"978-983": "Malaysia",
+	"979-10":  "France",
+	"979-11":  "Korea",
+	"979-12":  "Italy",
+	"979-13":  "Spain",
+	"979-8":   "United States",
+}
+
+// ISBNGroup detects the registration group such as "978-602", area is empty for group not listed
+func ISBNGroup(isbn string) (group string, area string, err error) {
+	isbn13, err := ParseISBN(isbn)
+
+	if err != nil {
+		return
+	}
+
+	for _, r := range isbnGroupRanges {
+		if !strings.HasPrefix(isbn13, r.prefix) {
+			continue
+		}
+
+		digits := isbn13[3 : 3+len(r.from)]
+
+		if digits >= r.from && digits <= r.to {
+			group = r.prefix + "-" + isbn13[3:3+r.length]
+			area = isbnGroupAreas[group]
+			return
+		}
+	}
+
+	err = errors.New("unknown registration group")
+	return
+}
@@ -0,0 +1,156 @@
+package helper
+
+import "testing"
+
+func TestNormalizeISBN(t *testing.T) {
+	tests := []struct {
+		isbn string
+		want string
+	}{
+		{"978-0-306-40615-7", "9780306406157"},
+		{" 0 8044 2957 x ", "080442957X"},
+		{"9780306406157", "9780306406157"},
+	}
+
+	for _, test := range tests {
+		if got := NormalizeISBN(test.isbn); got != test.want {
+			t.Errorf("NormalizeISBN(%q) = %q, want %q", test.isbn, got, test.want)
+		}
+	}
+}
+
+func TestIsValidISBN(t *testing.T) {
+	tests := []struct {
+		isbn   string
+		valid  bool
+		isbn13 bool
+	}{
+		{"0306406152", true, false},
+		{"080442957X", true, false},
+		{"0306406153", false, false},
+		{"X306406152", false, false},
+		{"08044295X7", false, false},
+		{"030640615", false, false},
+		{"9780306406157", true, true},
+		{"9791034304479", true, true},
+		{"9780306406158", false, true},
+		{"9770306406157", false, true},
+		{"978030640615X", false, true},
+		{"978030640615", false, true},
+	}
+
+	for _, test := range tests {
+		valid := IsValidISBN10(test.isbn)
+
+		if test.isbn13 {
+			valid = IsValidISBN13(test.isbn)
+		}
+
+		if valid != test.valid {
+			t.Errorf("validity of %q = %v, want %v", test.isbn, valid, test.valid)
+		}
+	}
+}
+
+func TestParseISBN(t *testing.T) {
+	tests := []struct {
+		isbn    string
+		want    string
+		invalid bool
+	}{
+		{"0-306-40615-2", "9780306406157", false},
+		{"080442957x", "9780804429573", false},
+		{"978-0-306-40615-7", "9780306406157", false},
+		{"979-10-343-0447-9", "9791034304479", false},
+		{"0-306-40615-3", "", true},
+		{"978-0-306-40615-8", "", true},
+		{"12345", "", true},
+		{"", "", true},
+	}
+
+	for _, test := range tests {
+		got, err := ParseISBN(test.isbn)
+
+		if (err != nil) != test.invalid || got != test.want {
+			t.Errorf("ParseISBN(%q) = %q, %v, want %q", test.isbn, got, err, test.want)
+		}
+	}
+}
+
+func TestISBN13To10(t *testing.T) {
+	tests := []struct {
+		isbn    string
+		want    string
+		invalid bool
+	}{
+		{"9780306406157", "0306406152", false},
+		{"978-0-8044-2957-3", "080442957X", false},
+		{"9791034304479", "", true},
+		{"9780306406158", "", true},
+	}
+
+	for _, test := range tests {
+		got, err := ISBN13To10(test.isbn)
+
+		if (err != nil) != test.invalid || got != test.want {
+			t.Errorf("ISBN13To10(%q) = %q, %v, want %q", test.isbn, got, err, test.want)
+		}
+	}
+}
+
+func TestISBN10To13(t *testing.T) {
+	tests := []struct {
+		isbn    string
+		want    string
+		invalid bool
+	}{
+		{"0306406152", "9780306406157", false},
+		{"0-8044-2957-X", "9780804429573", false},
+		{"0306406153", "", true},
+	}
+
+	for _, test := range tests {
+		got, err := ISBN10To13(test.isbn)
+
+		if (err != nil) != test.invalid || got != test.want {
+			t.Errorf("ISBN10To13(%q) = %q, %v, want %q", test.isbn, got, err, test.want)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		// converting back gives the original ISBN-10
+		if back, _ := ISBN13To10(got); back != NormalizeISBN(test.isbn) {
+			t.Errorf("ISBN13To10(%q) = %q, want %q", got, back, NormalizeISBN(test.isbn))
+		}
+	}
+}
+
+func TestISBNGroup(t *testing.T) {
+	tests := []struct {
+		isbn    string
+		group   string
+		area    string
+		invalid bool
+	}{
+		{"9780306406157", "978-0", "English language", false},
+		{"9786020000008", "978-602", "Indonesia", false},
+		{"9788175257665", "978-81", "India", false},
+		{"9789993000006", "978-99930", "", false},
+		{"9791034304479", "979-10", "France", false},
+		{"9798600000001", "979-8", "United States", false},
+		{"0306406152", "978-0", "English language", false},
+		{"9789900000006", "978-9900", "", false},
+		{"9790000000001", "", "", true},
+		{"9780306406158", "", "", true},
+	}
+
+	for _, test := range tests {
+		group, area, err := ISBNGroup(test.isbn)
+
+		if (err != nil) != test.invalid || group != test.group || area != test.area {
+			t.Errorf("ISBNGroup(%q) = %q, %q, %v, want %q, %q", test.isbn, group, area, err, test.group, test.area)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package helper
+
+import "strings"
+
+// MARC language code, also used by Open Library, to language name
+var languages = map[string]string{
+	"ara": "Arabic",
+	"chi": "Chinese",
+	"dut": "Dutch",
+	"eng": "English",
+	"fre": "French",
+	"ger": "German",
+	"ind": "Indonesian",
+	"ita": "Italian",
+	"jav": "Javanese",
+	"jpn": "Japanese",
+	"kor": "Korean",
+	"may": "Malay",
+	"por": "Portuguese",
+	"rus": "Russian",
+	"spa": "Spanish",
+	"sun": "Sundanese",
+}
+
+// LanguageName returns the name of MARC language code, unknown code is kept as it is
+func LanguageName(code string) string {
+	if name, exist := languages[strings.ToLower(code)]; exist {
+		return name
+	}
+
+	return code
+}
+
+// LanguageCode returns the MARC code of language name or code, empty for unknown language
+func LanguageCode(language string) string {
+	for code, name := range languages {
+		if strings.EqualFold(name, language) || strings.EqualFold(code, language) {
+			return code
+		}
+	}
+
+	return ""
+}
+
+// ISO 639-1 tag of MARC language code, as used by BCP 47 and Accept-Language
+var languageTags = map[string]string{
+	"ara": "ar",
+	"chi": "zh",
+	"dut": "nl",
+	"eng": "en",
+	"fre": "fr",
+	"ger": "de",
+	"ind": "id",
+	"ita": "it",
+	"jav": "jv",
+	"jpn": "ja",
+	"kor": "ko",
+	"may": "ms",
+	"por": "pt",
+	"rus": "ru",
+	"spa": "es",
+	"sun": "su",
+}
+
+// LanguageTag returns the ISO 639-1 tag of language name or code, empty for unknown language
+func LanguageTag(language string) string {
+	return languageTags[LanguageCode(language)]
+}
+
+// LanguageFromTag returns the language name of ISO 639-1 tag, region is ignored, empty for unknown tag
+func LanguageFromTag(tag string) string {
+	primary := strings.SplitN(strings.ToLower(tag), "-", 2)[0]
+
+	for code, languageTag := range languageTags {
+		if languageTag == primary {
+			return languages[code]
+		}
+	}
+
+	return ""
+}
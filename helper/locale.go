@@ -0,0 +1,98 @@
+package helper
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// NormalizeLocale lowercases language tag such as en-US or pt_BR, empty for invalid tag
+func NormalizeLocale(tag string) string {
+	tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+
+	if !localePattern.MatchString(tag) {
+		return ""
+	}
+
+	return tag
+}
+
+// ParseAcceptLanguage returns tags of Accept-Language header by preference, tag with q=0 is left out
+func ParseAcceptLanguage(header string) (tags []string) {
+	type weighted struct {
+		tag    string
+		weight float64
+	}
+
+	preferences := []weighted{}
+
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.TrimSpace(fields[0])
+		weight := 1.0
+
+		for _, param := range fields[1:] {
+			if value := strings.TrimSpace(param); strings.HasPrefix(value, "q=") {
+				q, err := strconv.ParseFloat(value[2:], 64)
+
+				if err != nil {
+					q = 0
+				}
+
+				weight = q
+			}
+		}
+
+		if tag != "*" {
+			tag = NormalizeLocale(tag)
+		}
+
+		if tag == "" || weight <= 0 {
+			continue
+		}
+
+		preferences = append(preferences, weighted{tag, weight})
+	}
+
+	// same weight keeps the order in header
+	sort.SliceStable(preferences, func(i, j int) bool {
+		return preferences[i].weight > preferences[j].weight
+	})
+
+	for _, preference := range preferences {
+		tags = append(tags, preference.tag)
+	}
+
+	return
+}
+
+// MatchLocale picks the available locale for the first preferred tag that matches exactly or by primary language,
+// wildcard takes the first available locale, empty when nothing matches
+func MatchLocale(preferred []string, available []string) string {
+	primary := func(tag string) string {
+		return strings.SplitN(tag, "-", 2)[0]
+	}
+
+	for _, tag := range preferred {
+		if tag == "*" && len(available) > 0 {
+			return available[0]
+		}
+
+		for _, locale := range available {
+			if locale != "" && locale == tag {
+				return locale
+			}
+		}
+
+		for _, locale := range available {
+			if locale != "" && primary(locale) == primary(tag) {
+				return locale
+			}
+		}
+	}
+
+	return ""
+}
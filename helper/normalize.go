@@ -0,0 +1,82 @@
+package helper
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// leading articles ignored when comparing titles
+var articles = map[string]interface{}{"the": nil, "a": nil, "an": nil}
+
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// NormalizeTitle drops subtitle, punctuation and leading article,
+// so "The Hobbit: Or There and Back Again" becomes "hobbit"
+func NormalizeTitle(title string) string {
+	if i := strings.IndexAny(title, ":;("); i > 0 {
+		title = title[:i]
+	}
+
+	tokens := tokenize(title)
+
+	if len(tokens) > 1 {
+		if _, exist := articles[tokens[0]]; exist {
+			tokens = tokens[1:]
+		}
+	}
+
+	return strings.Join(tokens, " ")
+}
+
+// NormalizeName drops punctuation and spacing differences,
+// so "J.K. Rowling" and "J. K. Rowling" become "j k rowling"
+func NormalizeName(name string) string {
+	return strings.Join(tokenize(name), " ")
+}
+
+// NormalizeAuthors normalizes and sorts names, so author order does not matter
+func NormalizeAuthors(names []string) string {
+	normalized := []string{}
+
+	for _, name := range names {
+		if name = NormalizeName(name); name != "" {
+			normalized = append(normalized, name)
+		}
+	}
+
+	sort.Strings(normalized)
+
+	return strings.Join(normalized, ";")
+}
+
+// MatchKey is normalized text without space, it is stored so near-duplicates are shortlisted in database
+func MatchKey(normalized string) string {
+	return strings.ReplaceAll(normalized, " ", "")
+}
+
+// KeyRange returns the first two characters and the length range a stored key must have
+// to score at least threshold against key, one character of slack is left for spacing
+func KeyRange(key string, threshold float64) (prefix string, minLength int, maxLength int) {
+	runes := []rune(key)
+	prefix = string(runes)
+
+	if len(runes) > 2 {
+		prefix = string(runes[:2])
+	}
+
+	minLength = int(math.Floor(float64(len(runes))*threshold)) - 1
+	maxLength = int(math.Ceil(float64(len(runes))/threshold)) + 1
+
+	return
+}
+
+// CandidateKey identifies wishes and books referring to the same title by the same authors
+func CandidateKey(title string, authors []string) string {
+	return NormalizeTitle(title) + "|" + NormalizeAuthors(authors)
+}
@@ -0,0 +1,55 @@
+package helper
+
+// Similarity scores two strings from 0 to 1 by their edit distance,
+// strings are expected to be normalized before compared
+func Similarity(a string, b string) float64 {
+	x, y := []rune(a), []rune(b)
+
+	if len(x) == 0 && len(y) == 0 {
+		return 1
+	}
+
+	// levenshtein distance, only two rows are kept
+	previous := make([]int, len(y)+1)
+	current := make([]int, len(y)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(x); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(y); j++ {
+			cost := 1
+
+			if x[i-1] == y[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	longest := len(x)
+
+	if len(y) > longest {
+		longest = len(y)
+	}
+
+	return 1 - float64(previous[len(y)])/float64(longest)
+}
+
+func min(values ...int) int {
+	result := values[0]
+
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+
+	return result
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	_entity "plain-go/public-library/entity"
+	"time"
 )
 
 type LoginRequest struct {
@@ -58,7 +59,8 @@ type CreateBookRequest struct {
 }
 
 type CreateBookResponse struct {
-	Book _entity.Book `json:"book"`
+	Book               _entity.Book                `json:"book"`
+	PossibleDuplicates []_entity.PossibleDuplicate `json:"possible_duplicates,omitempty"`
 }
 
 type CreateAuthorRequest struct {
@@ -72,11 +74,22 @@ type GetAllBooksRequest struct {
 	Keyword  string
 	SortBy   string
 	SortMode string
+	Locale   string
+	Language string
 }
 
 type GetAllBooksResponse struct {
 	Books []_entity.Book `json:"books"`
 	Count uint           `json:"count"`
+	ISBN  *ISBNDetail    `json:"isbn,omitempty"`
+}
+
+// looked up ISBN in both forms, ISBN-10 and area are empty when there is none
+type ISBNDetail struct {
+	ISBN13 string `json:"isbn13"`
+	ISBN10 string `json:"isbn10,omitempty"`
+	Group  string `json:"group,omitempty"`
+	Area   string `json:"area,omitempty"`
 }
 
 type GetBookByIdResponse struct {
@@ -95,6 +108,15 @@ type UpdateBookRequest struct {
 }
 
 type UpdateBookResponse struct {
+	Book               _entity.Book                `json:"book"`
+	PossibleDuplicates []_entity.PossibleDuplicate `json:"possible_duplicates,omitempty"`
+}
+
+type MergeBookRequest struct {
+	TargetId uint `json:"target_id"`
+}
+
+type MergeBookResponse struct {
 	Book _entity.Book `json:"book"`
 }
 
@@ -132,7 +154,8 @@ type AddBookToWishlistRequest struct {
 }
 
 type AddBookToWishlistResponse struct {
-	Wish _entity.Wish `json:"wish"`
+	Wish               _entity.Wish                `json:"wish"`
+	PossibleDuplicates []_entity.PossibleDuplicate `json:"possible_duplicates,omitempty"`
 }
 
 type UpdateWishRequest struct {
@@ -143,7 +166,8 @@ type UpdateWishRequest struct {
 }
 
 type UpdateWishResponse struct {
-	Wish _entity.Wish `json:"wish"`
+	Wish               _entity.Wish                `json:"wish"`
+	PossibleDuplicates []_entity.PossibleDuplicate `json:"possible_duplicates,omitempty"`
 }
 
 type CreateReviewRequest struct {
@@ -159,9 +183,17 @@ type GetReviewByIdResponse struct {
 	Review _entity.SimplifiedReview `json:"review"`
 }
 
+type GetAllReviewsByBookIdRequest struct {
+	Page    int
+	Records int
+	SortBy  string
+}
+
 type GetAllReviewsByBookIdResponse struct {
-	Book    _entity.Book     `json:"book"`
-	Reviews []_entity.Review `json:"reviews"`
+	Book      _entity.Book     `json:"book"`
+	Reviews   []_entity.Review `json:"reviews"`
+	Count     uint             `json:"count"`
+	Histogram map[uint]uint    `json:"histogram"`
 }
 
 type CreateReviewResponse struct {
@@ -178,6 +210,58 @@ type UpdateReviewResponse struct {
 	Review _entity.SimplifiedReview `json:"review"`
 }
 
+type VoteReviewRequest struct {
+	Helpful *bool `json:"helpful"`
+}
+
+type VoteReviewResponse struct {
+	Vote           _entity.ReviewVote `json:"vote"`
+	HelpfulCount   uint               `json:"helpful_count"`
+	UnhelpfulCount uint               `json:"unhelpful_count"`
+}
+
+type ReportReviewRequest struct {
+	Reason string `json:"reason"`
+	Note   string `json:"note"`
+}
+
+type ReportReviewResponse struct {
+	Report _entity.ReviewReport `json:"report"`
+}
+
+type GetModerationQueueResponse struct {
+	Reviews []_entity.ModerationQueueItem `json:"reviews"`
+}
+
+type ModerateReviewRequest struct {
+	Action string `json:"action"`
+	Note   string `json:"note"`
+}
+
+type ModerateReviewResponse struct {
+	Action _entity.ModerationAction `json:"action"`
+}
+
+type CreateReplyRequest struct {
+	Content string `json:"content"`
+}
+
+type CreateReplyResponse struct {
+	Reply _entity.ReviewReply `json:"reply"`
+}
+
+type UpdateReplyRequest struct {
+	Content string `json:"content"`
+}
+
+type UpdateReplyResponse struct {
+	Reply _entity.ReviewReply `json:"reply"`
+}
+
+type GetReplyModerationQueueResponse struct {
+	Replies []_entity.ReplyModerationQueueItem `json:"replies"`
+}
+
 type GetAllRequestResponse struct {
 	Requests []_entity.Request `json:"requests"`
 }
@@ -190,8 +274,10 @@ type GetRequestByIdResponse struct {
 	Request _entity.Request `json:"request"`
 }
 
+// either book or work is given, request for work takes any of its editions
 type CreateRequestRequest struct {
 	BookId uint `json:"book_id"`
+	WorkId uint `json:"work_id"`
 }
 
 type CreateRequestResponse struct {
@@ -205,3 +291,363 @@ type UpdateRequestRequest struct {
 type UpdateRequestResponse struct {
 	Request _entity.Request `json:"request"`
 }
+
+type GetNotificationPreferenceResponse struct {
+	Preference _entity.NotificationPreference `json:"preference"`
+}
+
+type UpdateNotificationPreferenceRequest struct {
+	Email         *bool `json:"email"`
+	SMS           *bool `json:"sms"`
+	InApp         *bool `json:"in_app"`
+	DaysBeforeDue *uint `json:"days_before_due"`
+}
+
+type UpdateNotificationPreferenceResponse struct {
+	Preference _entity.NotificationPreference `json:"preference"`
+}
+
+type GetAllNotificationsResponse struct {
+	UnreadCount   uint                   `json:"unread_count"`
+	Notifications []_entity.Notification `json:"notifications"`
+}
+
+type UpdateNotificationsRequest struct {
+	NotificationIds []uint `json:"notification_ids"`
+}
+
+type CreateWebhookRequest struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+}
+
+type CreateWebhookResponse struct {
+	Webhook _entity.Webhook `json:"webhook"`
+}
+
+type GetAllWebhooksResponse struct {
+	Webhooks []_entity.Webhook `json:"webhooks"`
+}
+
+type GetWebhookByIdResponse struct {
+	Webhook _entity.Webhook `json:"webhook"`
+}
+
+type UpdateWebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Active *bool    `json:"active"`
+}
+
+type UpdateWebhookResponse struct {
+	Webhook _entity.Webhook `json:"webhook"`
+}
+
+type GetAllWebhookDeliveriesResponse struct {
+	Deliveries []_entity.WebhookDelivery `json:"deliveries"`
+}
+
+type ReplayWebhookDeliveryResponse struct {
+	Delivery _entity.WebhookDelivery `json:"delivery"`
+}
+
+type GetAllAcquisitionCandidatesRequest struct {
+	Status   string
+	Category string
+	Keyword  string
+	SortBy   string
+}
+
+type GetAllAcquisitionCandidatesResponse struct {
+	Candidates []_entity.AcquisitionCandidate `json:"candidates"`
+}
+
+type GetAcquisitionCandidateByIdResponse struct {
+	Candidate _entity.AcquisitionCandidate `json:"candidate"`
+}
+
+type UpdateAcquisitionCandidateRequest struct {
+	Status string   `json:"status"`
+	Reason string   `json:"reason"`
+	Budget *float64 `json:"budget"`
+	Vendor string   `json:"vendor"`
+}
+
+type UpdateAcquisitionCandidateResponse struct {
+	Candidate _entity.AcquisitionCandidate `json:"candidate"`
+}
+
+type GetAllSuggestionsResponse struct {
+	Suggestions []_entity.Suggestion `json:"suggestions"`
+}
+
+type VoteSuggestionResponse struct {
+	Suggestion _entity.Suggestion `json:"suggestion"`
+}
+
+type GetAllAuthorsRequest struct {
+	Page    int
+	Records int
+	Keyword string
+}
+
+type GetAllAuthorsResponse struct {
+	Authors []_entity.AuthorDetail `json:"authors"`
+	Count   uint                   `json:"count"`
+}
+
+type GetAuthorByIdResponse struct {
+	Author _entity.AuthorDetail `json:"author"`
+}
+
+type UpdateAuthorRequest struct {
+	Name        string            `json:"name"`
+	Biography   string            `json:"biography"`
+	BirthYear   *uint             `json:"birth_year"`
+	DeathYear   *uint             `json:"death_year"`
+	ExternalIds map[string]string `json:"external_ids"`
+}
+
+type UpdateAuthorResponse struct {
+	Author _entity.AuthorDetail `json:"author"`
+}
+
+type MergeAuthorRequest struct {
+	TargetId uint `json:"target_id"`
+}
+
+type MergeAuthorResponse struct {
+	Author _entity.AuthorDetail `json:"author"`
+}
+
+type CreateReadingListRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Visibility  string `json:"visibility"`
+}
+
+type CreateReadingListResponse struct {
+	List _entity.ReadingList `json:"list"`
+}
+
+type GetAllReadingListsRequest struct {
+	Page     int
+	Records  int
+	Keyword  string
+	Featured bool
+}
+
+type GetAllReadingListsResponse struct {
+	Lists []_entity.ReadingList `json:"lists"`
+}
+
+type GetReadingListByIdResponse struct {
+	List _entity.ReadingList `json:"list"`
+}
+
+type UpdateReadingListRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Visibility  string `json:"visibility"`
+}
+
+type UpdateReadingListResponse struct {
+	List _entity.ReadingList `json:"list"`
+}
+
+type SaveReadingListItemRequest struct {
+	Note string `json:"note"`
+}
+
+type SaveReadingListItemResponse struct {
+	Item _entity.ReadingListItem `json:"item"`
+}
+
+type ReorderReadingListRequest struct {
+	BookIds []uint `json:"book_ids"`
+}
+
+type FeatureReadingListRequest struct {
+	Featured *bool `json:"featured"`
+}
+
+type ImportBooksResponse struct {
+	Created uint                     `json:"created"`
+	Skipped uint                     `json:"skipped"`
+	Failed  uint                     `json:"failed"`
+	Records []_entity.ImportedRecord `json:"records"`
+}
+
+type ExportBooksRequest struct {
+	Category string
+	From     time.Time
+	To       time.Time
+}
+
+type HarvestBooksRequest struct {
+	Id         uint
+	Categories []string
+	From       time.Time
+	Until      time.Time
+	AfterId    uint
+	Limit      int
+}
+
+type UploadCoverResponse struct {
+	Cover _entity.BookCover `json:"cover"`
+}
+
+type GetBookTranslationsResponse struct {
+	Translations []_entity.BookTranslation `json:"translations"`
+	Count        uint                      `json:"count"`
+}
+
+type SaveBookTranslationRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+type SaveBookTranslationResponse struct {
+	Translation _entity.BookTranslation `json:"translation"`
+}
+
+type GetAllWorksRequest struct {
+	Page    int
+	Records int
+	Keyword string
+}
+
+type GetAllWorksResponse struct {
+	Works []_entity.Work `json:"works"`
+	Count uint           `json:"count"`
+}
+
+type CreateWorkRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	BookIds     []uint `json:"book_ids"`
+}
+
+type CreateWorkResponse struct {
+	Work _entity.Work `json:"work"`
+}
+
+type GetWorkByIdResponse struct {
+	Work _entity.Work `json:"work"`
+}
+
+type UpdateWorkRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+type UpdateWorkResponse struct {
+	Work _entity.Work `json:"work"`
+}
+
+type GetWorkReviewsResponse struct {
+	Work    _entity.Work     `json:"work"`
+	Reviews []_entity.Review `json:"reviews"`
+}
+
+type CreateSeriesRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type CreateSeriesResponse struct {
+	Series _entity.Series `json:"series"`
+}
+
+type GetAllSeriesResponse struct {
+	Series []_entity.Series `json:"series"`
+}
+
+type GetSeriesByIdResponse struct {
+	Series _entity.Series `json:"series"`
+}
+
+type UpdateSeriesRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type UpdateSeriesResponse struct {
+	Series _entity.Series `json:"series"`
+}
+
+type SaveSeriesWorkRequest struct {
+	Position uint `json:"position"`
+}
+
+type SaveSeriesWorkResponse struct {
+	Series _entity.Series `json:"series"`
+}
+
+type GetAllSubjectsRequest struct {
+	Scheme   string
+	ParentId uint
+	Keyword  string
+}
+
+type GetAllSubjectsResponse struct {
+	Subjects []_entity.Subject `json:"subjects"`
+}
+
+type CreateSubjectRequest struct {
+	Scheme   string `json:"scheme"`
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	ParentId uint   `json:"parent_id"`
+}
+
+type CreateSubjectResponse struct {
+	Subject _entity.Subject `json:"subject"`
+}
+
+type SeedDeweyResponse struct {
+	Subjects []_entity.Subject `json:"subjects"`
+}
+
+type GetSubjectByIdResponse struct {
+	Subject _entity.Subject `json:"subject"`
+}
+
+// zero parent id keeps current parent, moving to the top level is done with root set
+type UpdateSubjectRequest struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	ParentId uint   `json:"parent_id"`
+	Root     bool   `json:"root"`
+}
+
+type UpdateSubjectResponse struct {
+	Subject _entity.Subject `json:"subject"`
+}
+
+type GetBooksBySubjectRequest struct {
+	Page        int
+	Records     int
+	Descendants bool
+}
+
+type GetBooksBySubjectResponse struct {
+	Subject _entity.Subject `json:"subject"`
+	Books   []_entity.Book  `json:"books"`
+	Count   uint            `json:"count"`
+}
+
+// the first subject is the primary one when primary id is not given
+type SetBookSubjectsRequest struct {
+	SubjectIds []uint `json:"subject_ids"`
+	PrimaryId  uint   `json:"primary_id"`
+}
+
+type SetCallNumberRequest struct {
+	CallNumber string `json:"call_number"`
+}
+
+type GetBookClassificationResponse struct {
+	Classification _entity.BookClassification `json:"classification"`
+}
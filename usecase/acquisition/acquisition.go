@@ -0,0 +1,394 @@
+package acquisition
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	_acquisitionRepository "plain-go/public-library/datastore/acquisition"
+	_bookRepository "plain-go/public-library/datastore/book"
+	_entity "plain-go/public-library/entity"
+	_helper "plain-go/public-library/helper"
+	_model "plain-go/public-library/model"
+	"strings"
+	"time"
+)
+
+// allowed status transitions, received candidate is final
+var transitions = map[string]map[string]interface{}{
+	"under_review": {"ordered": nil, "rejected": nil},
+	"ordered":      {"under_review": nil, "received": nil, "rejected": nil},
+	"rejected":     {"under_review": nil},
+	"received":     {},
+}
+
+// ranking of candidates and suggestions
+var mapSort = map[string]interface{}{"votes": nil, "recent": nil, "category": nil}
+
+type AcquisitionUseCase struct {
+	acquisitionRepo _acquisitionRepository.Acquisition
+	bookRepo        _bookRepository.Book
+}
+
+func New(acquisition _acquisitionRepository.Acquisition, book _bookRepository.Book) *AcquisitionUseCase {
+	return &AcquisitionUseCase{acquisitionRepo: acquisition, bookRepo: book}
+}
+
+func (auc AcquisitionUseCase) GetAllCandidates(query url.Values) (res _model.GetAllAcquisitionCandidatesResponse, code int, message string) {
+	// default parameters
+	params := _model.GetAllAcquisitionCandidatesRequest{}
+	params.Status = strings.ToLower(strings.TrimSpace(query.Get("status")))
+	params.Category = strings.TrimSpace(query.Get("category"))
+	params.SortBy = "votes"
+
+	if _, exist := transitions[params.Status]; params.Status != "" && !exist {
+		log.Println("invalid status")
+		code, message = http.StatusBadRequest, "status must be one of under_review, ordered, received or rejected"
+		return
+	}
+
+	if value, exist := query["sort"]; exist {
+		if _, exist := mapSort[value[0]]; !exist {
+			log.Println("unaccepted sorting criteria")
+			code, message = http.StatusBadRequest, "unaccepted sorting criteria"
+			return
+		}
+
+		params.SortBy = value[0]
+	}
+
+	// calling repository
+	candidates, err := auc.acquisitionRepo.GetAllCandidates(params)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	for i := range candidates {
+		candidates[i].CreatedAt, _ = _helper.TimeFormatter(candidates[i].CreatedAt)
+		candidates[i].UpdatedAt, _ = _helper.TimeFormatter(candidates[i].UpdatedAt)
+	}
+
+	res.Candidates = candidates
+	code, message = http.StatusOK, "success get all acquisition candidates"
+
+	return
+}
+
+func (auc AcquisitionUseCase) GetCandidateById(candidateId uint) (res _model.GetAcquisitionCandidateByIdResponse, code int, message string) {
+	// calling repository
+	candidate, err := auc.acquisitionRepo.GetCandidateById(candidateId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if candidate.Title == "" {
+		log.Println("acquisition candidate not found")
+		code, message = http.StatusNotFound, "acquisition candidate not found"
+		return
+	}
+
+	wishes, err := auc.acquisitionRepo.GetWishesByCandidateId(candidateId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	for _, wish := range wishes {
+		// getting each wish's authors
+		wish.Author, err = auc.bookRepo.GetWishAuthors(wish.Id)
+
+		// detect failure in repository
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		wish.CreatedAt, _ = _helper.TimeFormatter(wish.CreatedAt)
+		wish.UpdatedAt, _ = _helper.TimeFormatter(wish.UpdatedAt)
+		candidate.Wishes = append(candidate.Wishes, wish)
+	}
+
+	res.Candidate = candidate
+	res.Candidate.CreatedAt, _ = _helper.TimeFormatter(res.Candidate.CreatedAt)
+	res.Candidate.UpdatedAt, _ = _helper.TimeFormatter(res.Candidate.UpdatedAt)
+	code, message = http.StatusOK, "success get acquisition candidate"
+
+	return
+}
+
+func (auc AcquisitionUseCase) UpdateCandidate(librarianId uint, candidateId uint, req _model.UpdateAcquisitionCandidateRequest) (res _model.UpdateAcquisitionCandidateResponse, code int, message string) {
+	// prepare input string
+	status := strings.ToLower(strings.TrimSpace(req.Status))
+	reason := strings.TrimSpace(req.Reason)
+	vendor := strings.TrimSpace(req.Vendor)
+
+	for _, s := range []string{reason, vendor} {
+		// check if there is any forbidden character
+		if strings.Contains(strings.ReplaceAll(s, " ", ""), ";--") {
+			log.Println("forbidden character")
+			code, message = http.StatusBadRequest, "forbidden character"
+			return
+		}
+	}
+
+	if _, exist := transitions[status]; status != "" && !exist {
+		log.Println("invalid status")
+		code, message = http.StatusBadRequest, "status must be one of under_review, ordered, received or rejected"
+		return
+	}
+
+	if req.Budget != nil && *req.Budget < 0 {
+		log.Println("invalid budget")
+		code, message = http.StatusBadRequest, "invalid budget"
+		return
+	}
+
+	// check candidate existence
+	candidate, err := auc.acquisitionRepo.GetCandidateById(candidateId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if candidate.Title == "" {
+		log.Println("acquisition candidate not found")
+		code, message = http.StatusNotFound, "acquisition candidate not found"
+		return
+	}
+
+	flag := true
+	events := []_entity.Event{}
+
+	if status != "" && status != candidate.Status {
+		if _, allowed := transitions[candidate.Status][status]; !allowed {
+			log.Println("invalid status transition")
+			code, message = http.StatusBadRequest, "cannot change status from "+candidate.Status+" to "+status
+			return
+		}
+
+		// wishers must be told why the book will not be bought
+		if status == "rejected" && reason == "" {
+			log.Println("empty reason")
+			code, message = http.StatusBadRequest, "reason is required for rejected status"
+			return
+		}
+
+		if status == "rejected" {
+			// record rejection as event
+			event := _entity.Event{}
+			event.Type = "acquisition.rejected"
+			event.AggregateType = "acquisition"
+			event.UserId = librarianId
+			event.Payload = map[string]interface{}{"title": candidate.Title, "reason": reason}
+			events = append(events, event)
+		}
+
+		candidate.Status = status
+		flag = false
+	}
+
+	if candidate.Status == "rejected" && reason != "" && reason != candidate.RejectReason {
+		candidate.RejectReason = reason
+		flag = false
+	}
+
+	// reason only belongs to rejected candidate
+	if candidate.Status != "rejected" {
+		candidate.RejectReason = ""
+	}
+
+	if req.Budget != nil && *req.Budget != candidate.Budget {
+		candidate.Budget = *req.Budget
+		flag = false
+	}
+
+	if vendor != "" && vendor != candidate.Vendor {
+		candidate.Vendor = vendor
+		flag = false
+	}
+
+	// check if no field is updated
+	if flag {
+		log.Println("no update was performed")
+		code, message = http.StatusBadRequest, "no update was performed"
+		return
+	}
+
+	// calling repository
+	candidate.UpdatedAt = time.Now()
+	res.Candidate, err = auc.acquisitionRepo.UpdateCandidate(candidate, events...)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	res.Candidate.CreatedAt, _ = _helper.TimeFormatter(res.Candidate.CreatedAt)
+	res.Candidate.UpdatedAt, _ = _helper.TimeFormatter(res.Candidate.UpdatedAt)
+	code, message = http.StatusOK, "success update acquisition candidate"
+
+	return
+}
+
+func (auc AcquisitionUseCase) GetSuggestions(userId uint, query url.Values) (res _model.GetAllSuggestionsResponse, code int, message string) {
+	// default parameters
+	params := _model.GetAllAcquisitionCandidatesRequest{}
+	params.Category = strings.TrimSpace(query.Get("category"))
+	params.Keyword = strings.TrimSpace(query.Get("keyword"))
+	params.SortBy = "votes"
+
+	if value, exist := query["sort"]; exist {
+		if _, exist := mapSort[value[0]]; !exist {
+			log.Println("unaccepted sorting criteria")
+			code, message = http.StatusBadRequest, "unaccepted sorting criteria"
+			return
+		}
+
+		params.SortBy = value[0]
+	}
+
+	// calling repository
+	suggestions, err := auc.acquisitionRepo.GetSuggestions(userId, params)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	for i := range suggestions {
+		suggestions[i].CreatedAt, _ = _helper.TimeFormatter(suggestions[i].CreatedAt)
+	}
+
+	res.Suggestions = suggestions
+	code, message = http.StatusOK, "success get all suggestions"
+
+	return
+}
+
+// getOpenCandidate returns candidate which is still open for vote
+func (auc AcquisitionUseCase) getOpenCandidate(candidateId uint) (candidate _entity.AcquisitionCandidate, code int, message string) {
+	// calling repository
+	candidate, err := auc.acquisitionRepo.GetCandidateById(candidateId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if candidate.Title == "" {
+		log.Println("suggestion not found")
+		code, message = http.StatusNotFound, "suggestion not found"
+		return
+	}
+
+	if candidate.Status != "under_review" && candidate.Status != "ordered" {
+		log.Println("suggestion is closed")
+		code, message = http.StatusBadRequest, "suggestion is closed"
+		return
+	}
+
+	return
+}
+
+func (auc AcquisitionUseCase) VoteSuggestion(userId uint, candidateId uint) (res _model.VoteSuggestionResponse, code int, message string) {
+	// check candidate existence
+	candidate, code, message := auc.getOpenCandidate(candidateId)
+
+	if code != 0 {
+		return
+	}
+
+	// wishing for the book already counts as a vote
+	wished, err := auc.acquisitionRepo.HasWished(candidateId, userId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if wished {
+		log.Println("book already in wishlist")
+		code, message = http.StatusConflict, "book already in wishlist"
+		return
+	}
+
+	voted, err := auc.acquisitionRepo.HasVoted(candidateId, userId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if !voted {
+		// calling repository
+		if err = auc.acquisitionRepo.Vote(candidateId, userId); err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		candidate.VoteCount++
+	}
+
+	// formatting response
+	res.Suggestion = _entity.Suggestion{
+		Id:        candidate.Id,
+		Title:     candidate.Title,
+		Authors:   candidate.Authors,
+		Category:  candidate.Category,
+		Status:    candidate.Status,
+		WishCount: candidate.WishCount,
+		VoteCount: candidate.VoteCount,
+		Voted:     true,
+	}
+	res.Suggestion.CreatedAt, _ = _helper.TimeFormatter(candidate.CreatedAt)
+	code, message = http.StatusOK, "success vote suggestion"
+
+	return
+}
+
+func (auc AcquisitionUseCase) RemoveSuggestionVote(userId uint, candidateId uint) (code int, message string) {
+	// check candidate existence
+	_, code, message = auc.getOpenCandidate(candidateId)
+
+	if code != 0 {
+		return
+	}
+
+	voted, err := auc.acquisitionRepo.HasVoted(candidateId, userId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if !voted {
+		log.Println("vote not found")
+		code, message = http.StatusNotFound, "vote not found"
+		return
+	}
+
+	// calling repository
+	if err = auc.acquisitionRepo.RemoveVote(candidateId, userId); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	code, message = http.StatusOK, "success remove vote"
+
+	return
+}
@@ -0,0 +1,15 @@
+package acquisition
+
+import (
+	"net/url"
+	_model "plain-go/public-library/model"
+)
+
+type Acquisition interface {
+	GetAllCandidates(query url.Values) (res _model.GetAllAcquisitionCandidatesResponse, code int, message string)
+	GetCandidateById(candidateId uint) (res _model.GetAcquisitionCandidateByIdResponse, code int, message string)
+	GetSuggestions(userId uint, query url.Values) (res _model.GetAllSuggestionsResponse, code int, message string)
+	VoteSuggestion(userId uint, candidateId uint) (res _model.VoteSuggestionResponse, code int, message string)
+	RemoveSuggestionVote(userId uint, candidateId uint) (code int, message string)
+	UpdateCandidate(librarianId uint, candidateId uint, req _model.UpdateAcquisitionCandidateRequest) (res _model.UpdateAcquisitionCandidateResponse, code int, message string)
+}
@@ -0,0 +1,398 @@
+package author
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	_authorRepository "plain-go/public-library/datastore/author"
+	_bookRepository "plain-go/public-library/datastore/book"
+	_entity "plain-go/public-library/entity"
+	_helper "plain-go/public-library/helper"
+	_model "plain-go/public-library/model"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// external identifier schemes which can be stored for an author
+var identifierSchemes = map[string]interface{}{
+	"isni":        nil,
+	"loc":         nil,
+	"openlibrary": nil,
+	"orcid":       nil,
+	"viaf":        nil,
+	"wikidata":    nil,
+}
+
+type AuthorUseCase struct {
+	authorRepo _authorRepository.Author
+	bookRepo   _bookRepository.Book
+}
+
+func New(author _authorRepository.Author, book _bookRepository.Book) *AuthorUseCase {
+	return &AuthorUseCase{authorRepo: author, bookRepo: book}
+}
+
+func (auc AuthorUseCase) GetAllAuthors(query url.Values) (res _model.GetAllAuthorsResponse, code int, message string) {
+	// default parameters
+	params := _model.GetAllAuthorsRequest{}
+	params.Page = 1
+	params.Records = 20
+	params.Keyword = strings.TrimSpace(query.Get("keyword"))
+
+	if value, exist := query["page"]; exist {
+		page, err := strconv.Atoi(value[0])
+
+		if err != nil {
+			log.Println(err)
+			code, message = http.StatusBadRequest, "invalid page"
+			return
+		}
+
+		if page < 1 {
+			log.Println("invalid page")
+			code, message = http.StatusBadRequest, "invalid page"
+			return
+		}
+
+		params.Page = page
+	}
+
+	mapRecords := map[int]interface{}{10: nil, 20: nil, 50: nil}
+
+	if value, exist := query["records"]; exist {
+		records, err := strconv.Atoi(value[0])
+
+		if err != nil {
+			log.Println(err)
+			code, message = http.StatusBadRequest, "invalid number of records"
+			return
+		}
+
+		if _, exist := mapRecords[records]; !exist {
+			log.Println("unaccepted number of records")
+			code, message = http.StatusBadRequest, "unaccepted number of records"
+			return
+		}
+
+		params.Records = records
+	}
+
+	// calling repository
+	authors, err := auc.authorRepo.GetAllAuthors(params)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	res.Count, err = auc.authorRepo.CountAuthors(params.Keyword)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	for i := range authors {
+		authors[i].ExternalIds, err = auc.authorRepo.GetIdentifiers(authors[i].Id)
+
+		// detect failure in repository
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+	}
+
+	res.Authors = authors
+	code, message = http.StatusOK, "success get all authors"
+
+	return
+}
+
+func (auc AuthorUseCase) GetAuthorById(authorId uint) (res _model.GetAuthorByIdResponse, code int, message string) {
+	// calling repository
+	author, err := auc.authorRepo.GetAuthorById(authorId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if author.Name == "" {
+		log.Println("author not found")
+		code, message = http.StatusNotFound, "author not found"
+		return
+	}
+
+	author.ExternalIds, err = auc.authorRepo.GetIdentifiers(authorId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	books, err := auc.authorRepo.GetBibliography(authorId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	for _, book := range books {
+		// getting each book's authors, co-authors are listed too
+		book.Author, err = auc.bookRepo.GetBookAuthors(book.Id)
+
+		// detect failure in repository
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		book.CreatedAt, _ = _helper.TimeFormatter(book.CreatedAt)
+		book.UpdatedAt, _ = _helper.TimeFormatter(book.UpdatedAt)
+		author.Bibliography = append(author.Bibliography, book)
+	}
+
+	stats, err := auc.authorRepo.GetBorrowStats(authorId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	author.BorrowStats = &stats
+	res.Author = author
+	code, message = http.StatusOK, "success get author"
+
+	return
+}
+
+// updateYear applies requested year, nil keeps current year and 0 clears it
+func updateYear(current *uint, input *uint) (year *uint, updated bool) {
+	switch {
+	case input == nil:
+		return current, false
+	case *input == 0:
+		return nil, current != nil
+	case current != nil && *current == *input:
+		return current, false
+	}
+
+	value := *input
+
+	return &value, true
+}
+
+func (auc AuthorUseCase) UpdateAuthor(req _model.UpdateAuthorRequest, authorId uint) (res _model.UpdateAuthorResponse, code int, message string) {
+	// check author existence
+	author, err := auc.authorRepo.GetAuthorById(authorId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if author.Name == "" {
+		log.Println("author not found")
+		code, message = http.StatusNotFound, "author not found"
+		return
+	}
+
+	author.ExternalIds, err = auc.authorRepo.GetIdentifiers(authorId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// prepare input string
+	name := strings.TrimSpace(req.Name)
+	biography := strings.TrimSpace(req.Biography)
+
+	check := []string{name, biography}
+	flag := true
+
+	for _, s := range check {
+		// check if there is any forbidden character
+		if strings.Contains(strings.ReplaceAll(s, " ", ""), ";--") {
+			log.Println("forbidden character")
+			code, message = http.StatusBadRequest, "forbidden character"
+			return
+		}
+	}
+
+	if len(biography) > 5000 {
+		log.Println("biography too long")
+		code, message = http.StatusBadRequest, "biography must be at most 5000 characters"
+		return
+	}
+
+	if name != "" && name != author.Name {
+		// other author with the same normalized name must be merged instead, same name has the same key
+		authors, err := auc.bookRepo.GetAuthorsByNameKey(_helper.KeyRange(_helper.MatchKey(_helper.NormalizeName(name)), 1))
+
+		// detect failure in repository
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		for _, other := range authors {
+			if other.Id != authorId && _helper.NormalizeName(other.Name) == _helper.NormalizeName(name) {
+				log.Println("author already exist")
+				code, message = http.StatusConflict, "author already exist, merge the authors instead"
+				return
+			}
+		}
+
+		author.Name = name
+		flag = false
+	}
+
+	if biography != "" && biography != author.Biography {
+		author.Biography = biography
+		flag = false
+	}
+
+	for _, year := range []*uint{req.BirthYear, req.DeathYear} {
+		if year != nil && *year > uint(time.Now().Year()) {
+			log.Println("invalid year")
+			code, message = http.StatusBadRequest, "invalid year"
+			return
+		}
+	}
+
+	if birthYear, updated := updateYear(author.BirthYear, req.BirthYear); updated {
+		author.BirthYear = birthYear
+		flag = false
+	}
+
+	if deathYear, updated := updateYear(author.DeathYear, req.DeathYear); updated {
+		author.DeathYear = deathYear
+		flag = false
+	}
+
+	if author.BirthYear != nil && author.DeathYear != nil && *author.DeathYear < *author.BirthYear {
+		log.Println("invalid year")
+		code, message = http.StatusBadRequest, "death year must not be earlier than birth year"
+		return
+	}
+
+	// empty value removes the identifier
+	for scheme, value := range req.ExternalIds {
+		scheme = strings.ToLower(strings.TrimSpace(scheme))
+		value = strings.TrimSpace(value)
+
+		if _, exist := identifierSchemes[scheme]; !exist {
+			log.Println("invalid identifier scheme")
+			code, message = http.StatusBadRequest, "identifier scheme must be one of isni, loc, openlibrary, orcid, viaf or wikidata"
+			return
+		}
+
+		if strings.Contains(strings.ReplaceAll(value, " ", ""), ";--") {
+			log.Println("forbidden character")
+			code, message = http.StatusBadRequest, "forbidden character"
+			return
+		}
+
+		if value == "" {
+			if _, exist := author.ExternalIds[scheme]; exist {
+				delete(author.ExternalIds, scheme)
+				flag = false
+			}
+		} else if author.ExternalIds[scheme] != value {
+			author.ExternalIds[scheme] = value
+			flag = false
+		}
+	}
+
+	// check if no field is updated
+	if flag {
+		log.Println("no update was performed")
+		code, message = http.StatusBadRequest, "no update was performed"
+		return
+	}
+
+	// record update as event
+	event := _entity.Event{}
+	event.Type = "author.updated"
+	event.AggregateType = "author"
+	event.Payload = map[string]interface{}{"name": author.Name}
+
+	// calling repository
+	res.Author, err = auc.authorRepo.UpdateAuthor(author, event)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	code, message = http.StatusOK, "success update author"
+
+	return
+}
+
+func (auc AuthorUseCase) MergeAuthor(librarianId uint, authorId uint, req _model.MergeAuthorRequest) (res _model.MergeAuthorResponse, code int, message string) {
+	if req.TargetId == 0 || req.TargetId == authorId {
+		log.Println("invalid target")
+		code, message = http.StatusBadRequest, "target must be another author"
+		return
+	}
+
+	// check both authors existence
+	source := _entity.AuthorDetail{}
+
+	for _, id := range []uint{authorId, req.TargetId} {
+		author, err := auc.authorRepo.GetAuthorById(id)
+
+		// detect failure in repository
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		if author.Name == "" {
+			log.Println("author not found")
+			code, message = http.StatusNotFound, "author not found"
+			return
+		}
+
+		if id == authorId {
+			source = author
+		}
+	}
+
+	// record merge as event
+	event := _entity.Event{}
+	event.Type = "author.merged"
+	event.AggregateType = "author"
+	event.UserId = librarianId
+	event.Payload = map[string]interface{}{"source_id": authorId, "source_name": source.Name, "target_id": req.TargetId}
+
+	// calling repository
+	if err := auc.authorRepo.MergeAuthors(authorId, req.TargetId, event); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// formatting response
+	author, code, message := auc.GetAuthorById(req.TargetId)
+
+	if code != http.StatusOK {
+		return
+	}
+
+	res.Author = author.Author
+	code, message = http.StatusOK, "success merge author"
+
+	return
+}
@@ -0,0 +1,13 @@
+package author
+
+import (
+	"net/url"
+	_model "plain-go/public-library/model"
+)
+
+type Author interface {
+	GetAllAuthors(query url.Values) (res _model.GetAllAuthorsResponse, code int, message string)
+	GetAuthorById(authorId uint) (res _model.GetAuthorByIdResponse, code int, message string)
+	UpdateAuthor(req _model.UpdateAuthorRequest, authorId uint) (res _model.UpdateAuthorResponse, code int, message string)
+	MergeAuthor(librarianId uint, authorId uint, req _model.MergeAuthorRequest) (res _model.MergeAuthorResponse, code int, message string)
+}
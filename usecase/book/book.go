@@ -1,24 +1,32 @@
 package book
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"net/url"
+	_acquisitionRepository "plain-go/public-library/datastore/acquisition"
 	_bookRepository "plain-go/public-library/datastore/book"
 	_entity "plain-go/public-library/entity"
 	_helper "plain-go/public-library/helper"
 	_model "plain-go/public-library/model"
+	_coverUseCase "plain-go/public-library/usecase/cover"
+	_duplicate "plain-go/public-library/usecase/duplicate"
+	_enrichment "plain-go/public-library/usecase/enrichment"
 	"strconv"
 	"strings"
 	"time"
 )
 
 type BookUseCase struct {
-	repository _bookRepository.Book
+	repository      _bookRepository.Book
+	acquisitionRepo _acquisitionRepository.Acquisition
+	detector        _duplicate.Detector
+	provider        _enrichment.Provider
 }
 
-func New(book _bookRepository.Book) *BookUseCase {
-	return &BookUseCase{repository: book}
+func New(book _bookRepository.Book, acquisition _acquisitionRepository.Acquisition, detector _duplicate.Detector, provider _enrichment.Provider) *BookUseCase {
+	return &BookUseCase{repository: book, acquisitionRepo: acquisition, detector: detector, provider: provider}
 }
 
 func (buc BookUseCase) CreateBook(req _model.CreateBookRequest) (res _model.CreateBookResponse, code int, message string) {
@@ -73,6 +81,15 @@ func (buc BookUseCase) CreateBook(req _model.CreateBookRequest) (res _model.Crea
 		}
 	}
 
+	// ISBN is kept in its 13 digit form without hyphen
+	isbn13, err := _helper.ParseISBN(isbn13)
+
+	if err != nil {
+		log.Println(err)
+		code, message = http.StatusBadRequest, "invalid isbn"
+		return
+	}
+
 	// check if pages invalid
 	if req.Pages <= 0 {
 		log.Println("invalid number of pages")
@@ -101,6 +118,42 @@ func (buc BookUseCase) CreateBook(req _model.CreateBookRequest) (res _model.Crea
 		return
 	}
 
+	// check if ISBN is already used by another book
+	sameISBN, err := buc.repository.GetBookByISBN(isbn13)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if sameISBN.Id != 0 {
+		log.Println("isbn already exist")
+		code, message = http.StatusConflict, "isbn already exist"
+		return
+	}
+
+	// check if the same book is already exist under different spelling
+	names := []string{}
+
+	for _, _author := range req.Author {
+		names = append(names, _author.Name)
+	}
+
+	exact, similar, err := buc.detector.FindBooks(title, names)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if exact.Id != 0 {
+		log.Println("book already exist")
+		code, message = http.StatusConflict, "book already exist"
+		return
+	}
+
+	res.PossibleDuplicates = similar
+
 	// prepare input to repository
 	now := time.Now()
 	newBook.Title = title
@@ -113,19 +166,21 @@ func (buc BookUseCase) CreateBook(req _model.CreateBookRequest) (res _model.Crea
 	newBook.CreatedAt = now
 	newBook.UpdatedAt = now
 
-	// calling repository
-	res.Book, err = buc.repository.CreateNewBook(newBook)
-
-	// detect failure in repository
-	if err != nil {
-		code, message = http.StatusInternalServerError, "internal server error"
-		return
+	// record book creation as event
+	event := _entity.Event{}
+	event.Type = "book.created"
+	event.AggregateType = "book"
+	event.Payload = map[string]interface{}{
+		"title":    newBook.Title,
+		"category": newBook.Category,
+		"isbn13":   newBook.ISBN13,
+		"quantity": req.Quantity,
 	}
 
 	// create author
 	for _, _author := range req.Author {
-		// calling repository
-		author, err := buc.repository.GetAuthorByName(_author.Name)
+		// reuse author of the same normalized name, otherwise create new
+		author, similar, err := buc.detector.ResolveAuthor(_author.Name)
 
 		// detect failure in repository
 		if err != nil {
@@ -133,31 +188,56 @@ func (buc BookUseCase) CreateBook(req _model.CreateBookRequest) (res _model.Crea
 			return
 		}
 
-		// if author does not exist, create new
-		if author.Name == "" {
-			author.Name = _author.Name
+		res.PossibleDuplicates = append(res.PossibleDuplicates, similar...)
+		newBook.Author = append(newBook.Author, author)
+	}
 
-			// calling repository
-			author, err = buc.repository.CreateNewAuthor(author)
+	// calling repository, book author junction is created with the book
+	res.Book, err = buc.repository.CreateNewBook(newBook, event)
 
-			// detect failure in repository
-			if err != nil {
-				code, message = http.StatusInternalServerError, "internal server error"
-				return
-			}
-		}
+	// book of the same ISBN created at the same time
+	if errors.Is(err, _bookRepository.ErrDuplicateISBN) {
+		log.Println("isbn already exist")
+		code, message = http.StatusConflict, "isbn already exist"
+		return
+	}
 
-		// if author exist or after author created, create book author junction
-		if err = buc.repository.CreateBookAuthorJunction(res.Book, author); err != nil {
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	for i := uint(0); i < req.Quantity; i++ {
+		if err = buc.repository.CreateBookItem(res.Book); err != nil {
 			code, message = http.StatusInternalServerError, "internal server error"
 			return
 		}
+	}
+
+	// close acquisition candidate of the same title and authors, wishers are notified
+	names = []string{}
 
-		res.Book.Author = append(res.Book.Author, author)
+	for _, author := range res.Book.Author {
+		names = append(names, author.Name)
 	}
 
-	for i := uint(0); i < req.Quantity; i++ {
-		if err = buc.repository.CreateBookItem(res.Book); err != nil {
+	candidate, err := buc.acquisitionRepo.GetCandidateByKey(_helper.CandidateKey(res.Book.Title, names))
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if candidate.Id != 0 {
+		// record acquisition as event
+		event := _entity.Event{}
+		event.Type = "acquisition.received"
+		event.AggregateType = "acquisition"
+		event.Payload = map[string]interface{}{"title": res.Book.Title, "book_id": res.Book.Id}
+
+		if err = buc.acquisitionRepo.CloseCandidate(candidate.Id, res.Book.Id, event); err != nil {
 			code, message = http.StatusInternalServerError, "internal server error"
 			return
 		}
@@ -172,7 +252,64 @@ func (buc BookUseCase) CreateBook(req _model.CreateBookRequest) (res _model.Crea
 	return
 }
 
-func (buc BookUseCase) GetAllBooks(query url.Values) (res _model.GetAllBooksResponse, code int, message string) {
+// CreateBookFromISBN fills every field left empty from metadata provider, then creates book as usual
+func (buc BookUseCase) CreateBookFromISBN(req _model.CreateBookRequest) (res _model.CreateBookResponse, code int, message string) {
+	isbn13, err := _helper.ParseISBN(req.ISBN13)
+
+	if err != nil {
+		log.Println(err)
+		code, message = http.StatusBadRequest, "invalid isbn"
+		return
+	}
+
+	metadata, found, err := buc.provider.LookupISBN(isbn13)
+
+	// detect failure in provider
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if !found {
+		log.Println("isbn not found")
+		code, message = http.StatusNotFound, "isbn not found"
+		return
+	}
+
+	req.ISBN13 = isbn13
+
+	if strings.TrimSpace(req.Title) == "" {
+		req.Title = metadata.Title
+	}
+
+	if len(req.Author) == 0 {
+		req.Author = metadata.Author
+	}
+
+	if strings.TrimSpace(req.Publisher) == "" {
+		req.Publisher = metadata.Publisher
+	}
+
+	if strings.TrimSpace(req.Language) == "" {
+		req.Language = metadata.Language
+	}
+
+	if req.Pages == 0 {
+		req.Pages = metadata.Pages
+	}
+
+	if strings.TrimSpace(req.Category) == "" {
+		req.Category = metadata.Category
+	}
+
+	if strings.TrimSpace(req.Description) == "" {
+		req.Description = metadata.Description
+	}
+
+	return buc.CreateBook(req)
+}
+
+func (buc BookUseCase) GetAllBooks(query url.Values, languages []string) (res _model.GetAllBooksResponse, code int, message string) {
 	// default parameters
 	params := _model.GetAllBooksRequest{}
 	params.Page = 1
@@ -252,8 +389,54 @@ func (buc BookUseCase) GetAllBooks(query url.Values) (res _model.GetAllBooksResp
 		params.SortMode = value[0]
 	}
 
-	// calling repository
-	books, err := buc.repository.GetAllBooks(params)
+	// keyword search is limited to one language on request
+	if value, exist := query["lang"]; exist {
+		params.Locale = _helper.NormalizeLocale(value[0])
+
+		if params.Locale == "" {
+			log.Println("invalid language")
+			code, message = http.StatusBadRequest, "invalid language"
+			return
+		}
+
+		params.Language = _helper.LanguageFromTag(params.Locale)
+	}
+
+	books := []_entity.Book{}
+	var err error
+
+	if value, exist := query["isbn"]; exist {
+		// lookup by ISBN accepts either form, at most one active book matches
+		isbn13, err := _helper.ParseISBN(value[0])
+
+		if err != nil {
+			log.Println(err)
+			code, message = http.StatusBadRequest, "invalid isbn"
+			return
+		}
+
+		// both forms and registration group are returned even when no book matches
+		res.ISBN = &_model.ISBNDetail{ISBN13: isbn13}
+		res.ISBN.ISBN10, _ = _helper.ISBN13To10(isbn13)
+		res.ISBN.Group, res.ISBN.Area, _ = _helper.ISBNGroup(isbn13)
+
+		book, err := buc.repository.GetBookByISBN(isbn13)
+
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		if book.Id != 0 {
+			books = append(books, book)
+		}
+	} else if params.Keyword != "*" {
+		// keyword is matched against title, description and authors in every language
+		books, err = buc.repository.SearchBooks(params)
+	} else {
+		// calling repository
+		books, err = buc.repository.GetAllBooks(params)
+	}
 
 	// detect failure in repository
 	if err != nil {
@@ -299,6 +482,11 @@ func (buc BookUseCase) GetAllBooks(query url.Values) (res _model.GetAllBooksResp
 			book.AverageStar = averageStar
 		}
 
+		if err = buc.localize(&book, languages); err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
 		res.Books = append(res.Books, book)
 	}
 
@@ -308,7 +496,7 @@ func (buc BookUseCase) GetAllBooks(query url.Values) (res _model.GetAllBooksResp
 	return
 }
 
-func (buc BookUseCase) GetBookById(bookId uint) (res _model.GetBookByIdResponse, code int, message string) {
+func (buc BookUseCase) GetBookById(bookId uint, languages []string) (res _model.GetBookByIdResponse, code int, message string) {
 	// calling repository
 	book, err := buc.repository.GetBookById(bookId)
 
@@ -364,6 +552,24 @@ func (buc BookUseCase) GetBookById(bookId uint) (res _model.GetBookByIdResponse,
 
 	book.AverageStar = _helper.NilHandler(averageStar)
 
+	// get cover, book without cover has none
+	cover, err := buc.repository.GetCover(book.Id)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if cover.BookId != 0 {
+		_coverUseCase.SetURLs(&cover)
+		res.Book.Cover = &cover
+	}
+
+	if err = buc.localize(&res.Book, languages); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
 	// formatting response
 	res.Book.CreatedAt, _ = _helper.TimeFormatter(res.Book.CreatedAt)
 	res.Book.UpdatedAt, _ = _helper.TimeFormatter(res.Book.UpdatedAt)
@@ -438,7 +644,30 @@ func (buc BookUseCase) UpdateBook(req _model.UpdateBookRequest, bookId uint) (re
 		flag = false
 	}
 
+	if isbn13 != "" {
+		// ISBN is kept in its 13 digit form without hyphen
+		if isbn13, err = _helper.ParseISBN(isbn13); err != nil {
+			log.Println(err)
+			code, message = http.StatusBadRequest, "invalid isbn"
+			return
+		}
+	}
+
 	if isbn13 != "" && isbn13 != book.ISBN13 {
+		// check if ISBN is already used by another book
+		sameISBN, err := buc.repository.GetBookByISBN(isbn13)
+
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		if sameISBN.Id != 0 && sameISBN.Id != book.Id {
+			log.Println("isbn already exist")
+			code, message = http.StatusConflict, "isbn already exist"
+			return
+		}
+
 		book.ISBN13 = isbn13
 		flag = false
 	}
@@ -505,8 +734,8 @@ func (buc BookUseCase) UpdateBook(req _model.UpdateBookRequest, bookId uint) (re
 
 	// create author if len(createdAuthors) > 0
 	for _, _author := range createdAuthors {
-		// calling repository
-		author, err := buc.repository.GetAuthorByName(_author.Name)
+		// reuse author of the same normalized name, otherwise create new
+		author, similar, err := buc.detector.ResolveAuthor(_author.Name)
 
 		// detect failure in repository
 		if err != nil {
@@ -514,19 +743,7 @@ func (buc BookUseCase) UpdateBook(req _model.UpdateBookRequest, bookId uint) (re
 			return
 		}
 
-		// if author does not exist, create new
-		if author.Name == "" {
-			author.Name = _author.Name
-
-			// calling repository
-			author, err = buc.repository.CreateNewAuthor(author)
-
-			// detect failure in repository
-			if err != nil {
-				code, message = http.StatusInternalServerError, "internal server error"
-				return
-			}
-		}
+		res.PossibleDuplicates = append(res.PossibleDuplicates, similar...)
 
 		// if author exist or after author created, create book author junction
 		if err = buc.repository.CreateBookAuthorJunction(book, author); err != nil {
@@ -549,9 +766,22 @@ func (buc BookUseCase) UpdateBook(req _model.UpdateBookRequest, bookId uint) (re
 		book.Author = _helper.RemoveAuthor(book.Author, _author)
 	}
 
+	// record update as event
+	event := _entity.Event{}
+	event.Type = "book.updated"
+	event.AggregateType = "book"
+	event.Payload = map[string]interface{}{"title": book.Title, "isbn13": book.ISBN13}
+
 	// calling repository
 	book.UpdatedAt = time.Now()
-	res.Book, err = buc.repository.UpdateBook(book)
+	res.Book, err = buc.repository.UpdateBook(book, event)
+
+	// book of the same ISBN saved at the same time
+	if errors.Is(err, _bookRepository.ErrDuplicateISBN) {
+		log.Println("isbn already exist")
+		code, message = http.StatusConflict, "isbn already exist"
+		return
+	}
 
 	// detect failure in repository
 	if err != nil {
@@ -600,8 +830,14 @@ func (buc BookUseCase) DeleteBook(bookId uint) (code int, message string) {
 		return
 	}
 
+	// record deletion as event
+	event := _entity.Event{}
+	event.Type = "book.deleted"
+	event.AggregateType = "book"
+	event.Payload = map[string]interface{}{"title": book.Title}
+
 	// calling repository
-	err = buc.repository.DeleteBook(bookId)
+	err = buc.repository.DeleteBook(bookId, event)
 
 	if err != nil {
 		code, message = http.StatusInternalServerError, "internal server error"
@@ -612,3 +848,272 @@ func (buc BookUseCase) DeleteBook(bookId uint) (code int, message string) {
 
 	return
 }
+
+func (buc BookUseCase) MergeBook(librarianId uint, bookId uint, req _model.MergeBookRequest) (res _model.MergeBookResponse, code int, message string) {
+	if req.TargetId == 0 || req.TargetId == bookId {
+		log.Println("invalid target")
+		code, message = http.StatusBadRequest, "target must be another book"
+		return
+	}
+
+	// check both books existence
+	for _, id := range []uint{bookId, req.TargetId} {
+		book, err := buc.repository.GetBookById(id)
+
+		// detect failure in repository
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		if book.Title == "" {
+			log.Println("book not found")
+			code, message = http.StatusNotFound, "book not found"
+			return
+		}
+	}
+
+	// record merge as event
+	event := _entity.Event{}
+	event.Type = "book.merged"
+	event.AggregateType = "book"
+	event.UserId = librarianId
+	event.Payload = map[string]interface{}{"source_id": bookId, "target_id": req.TargetId}
+
+	// calling repository
+	if err := buc.repository.MergeBooks(bookId, req.TargetId, event); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// formatting response
+	book, code, message := buc.GetBookById(req.TargetId, nil)
+
+	if code != http.StatusOK {
+		return
+	}
+
+	res.Book = book.Book
+	code, message = http.StatusOK, "success merge book"
+
+	return
+}
+
+// localize puts the translation best matching preferred languages in place of title and description,
+// original metadata is kept when no translation matches, locale tells which language is served
+func (buc BookUseCase) localize(book *_entity.Book, languages []string) (err error) {
+	original := _helper.LanguageTag(book.Language)
+	book.Locale = original
+
+	if len(languages) == 0 {
+		return
+	}
+
+	translations, err := buc.repository.GetBookTranslations(book.Id)
+
+	if err != nil {
+		return
+	}
+
+	// original language comes first, so wildcard keeps the original
+	available := []string{original}
+
+	for _, translation := range translations {
+		available = append(available, translation.Locale)
+	}
+
+	locale := _helper.MatchLocale(languages, available)
+
+	for _, translation := range translations {
+		if locale == "" || locale == original || translation.Locale != locale {
+			continue
+		}
+
+		book.Title = translation.Title
+		book.Locale = locale
+
+		// translation without description falls back to the original one
+		if translation.Description != "" {
+			book.Description = translation.Description
+		}
+	}
+
+	return
+}
+
+func (buc BookUseCase) GetTranslations(bookId uint) (res _model.GetBookTranslationsResponse, code int, message string) {
+	// check book existence
+	book, err := buc.repository.GetBookById(bookId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if book.Title == "" {
+		log.Println("book not found")
+		code, message = http.StatusNotFound, "book not found"
+		return
+	}
+
+	// calling repository
+	translations, err := buc.repository.GetBookTranslations(bookId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// formatting response
+	res.Translations = []_entity.BookTranslation{}
+
+	for _, translation := range translations {
+		translation.CreatedAt, _ = _helper.TimeFormatter(translation.CreatedAt)
+		translation.UpdatedAt, _ = _helper.TimeFormatter(translation.UpdatedAt)
+		res.Translations = append(res.Translations, translation)
+	}
+
+	res.Count = uint(len(res.Translations))
+	code, message = http.StatusOK, "success get book translations"
+
+	return
+}
+
+func (buc BookUseCase) SaveTranslation(bookId uint, locale string, req _model.SaveBookTranslationRequest) (res _model.SaveBookTranslationResponse, code int, message string) {
+	// prepare input string
+	locale = _helper.NormalizeLocale(locale)
+	title := strings.TrimSpace(req.Title)
+	description := strings.TrimSpace(req.Description)
+
+	if locale == "" {
+		log.Println("invalid locale")
+		code, message = http.StatusBadRequest, "invalid locale"
+		return
+	}
+
+	// check if required input is empty
+	if title == "" {
+		log.Println("empty input")
+		code, message = http.StatusBadRequest, "empty input"
+		return
+	}
+
+	// check if there is any forbidden character
+	for _, s := range []string{title, description} {
+		if strings.Contains(strings.ReplaceAll(s, " ", ""), ";--") {
+			log.Println("forbidden character")
+			code, message = http.StatusBadRequest, "forbidden character"
+			return
+		}
+	}
+
+	// check book existence
+	book, err := buc.repository.GetBookById(bookId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if book.Title == "" {
+		log.Println("book not found")
+		code, message = http.StatusNotFound, "book not found"
+		return
+	}
+
+	// original language is edited on the book itself
+	if locale == _helper.LanguageTag(book.Language) {
+		log.Println("translation in original language")
+		code, message = http.StatusConflict, "locale is the original language of the book"
+		return
+	}
+
+	// calling repository
+	now := time.Now()
+	newTranslation := _entity.BookTranslation{BookId: bookId, Locale: locale, Title: title, Description: description, CreatedAt: now, UpdatedAt: now}
+
+	// record translation as event
+	event := _entity.Event{}
+	event.Type = "book.translated"
+	event.AggregateType = "book"
+	event.Payload = map[string]interface{}{"locale": locale, "title": title}
+
+	res.Translation, err = buc.repository.SaveBookTranslation(newTranslation, event)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// formatting response
+	res.Translation.CreatedAt, _ = _helper.TimeFormatter(res.Translation.CreatedAt)
+	res.Translation.UpdatedAt, _ = _helper.TimeFormatter(res.Translation.UpdatedAt)
+	code, message = http.StatusOK, "success save book translation"
+
+	return
+}
+
+func (buc BookUseCase) DeleteTranslation(bookId uint, locale string) (code int, message string) {
+	locale = _helper.NormalizeLocale(locale)
+
+	if locale == "" {
+		log.Println("invalid locale")
+		code, message = http.StatusBadRequest, "invalid locale"
+		return
+	}
+
+	// check translation existence
+	translations, err := buc.repository.GetBookTranslations(bookId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	exist := false
+
+	for _, translation := range translations {
+		if translation.Locale == locale {
+			exist = true
+		}
+	}
+
+	if !exist {
+		log.Println("translation not found")
+		code, message = http.StatusNotFound, "translation not found"
+		return
+	}
+
+	// record translation removal as event
+	event := _entity.Event{}
+	event.Type = "book.translation_deleted"
+	event.AggregateType = "book"
+	event.Payload = map[string]interface{}{"locale": locale}
+
+	// calling repository
+	if err = buc.repository.DeleteBookTranslation(bookId, locale, event); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	code, message = http.StatusOK, "success delete book translation"
+
+	return
+}
+
+// RebuildSearchIndex indexes the whole catalog again, index is otherwise kept by search subscriber
+func (buc BookUseCase) RebuildSearchIndex() (code int, message string) {
+	if err := buc.repository.RebuildSearchIndex(0); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	code, message = http.StatusOK, "success rebuild search index"
+
+	return
+}
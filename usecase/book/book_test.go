@@ -0,0 +1,132 @@
+package book
+
+import (
+	"net/http"
+	_acquisitionRepository "plain-go/public-library/datastore/acquisition"
+	_bookRepository "plain-go/public-library/datastore/book"
+	_entity "plain-go/public-library/entity"
+	_model "plain-go/public-library/model"
+	_enrichment "plain-go/public-library/usecase/enrichment"
+	"testing"
+)
+
+// fakeBookRepository keeps created books in memory, methods not used by book creation are left unimplemented
+type fakeBookRepository struct {
+	_bookRepository.Book
+	books []_entity.Book
+	items uint
+}
+
+func (fbr *fakeBookRepository) GetBookByTitle(title string) (book _entity.Book, err error) {
+	for _, book := range fbr.books {
+		if book.Title == title {
+			return book, nil
+		}
+	}
+
+	return
+}
+
+func (fbr *fakeBookRepository) GetBookByISBN(isbn13 string) (book _entity.Book, err error) {
+	for _, book := range fbr.books {
+		if book.ISBN13 == isbn13 {
+			return book, nil
+		}
+	}
+
+	return
+}
+
+func (fbr *fakeBookRepository) CreateNewBook(newBook _entity.Book, events ..._entity.Event) (book _entity.Book, err error) {
+	newBook.Id = uint(len(fbr.books) + 1)
+	fbr.books = append(fbr.books, newBook)
+	return newBook, nil
+}
+
+func (fbr *fakeBookRepository) CreateBookItem(book _entity.Book) (err error) {
+	fbr.items++
+	return
+}
+
+type fakeAcquisitionRepository struct {
+	_acquisitionRepository.Acquisition
+}
+
+func (far fakeAcquisitionRepository) GetCandidateByKey(key string) (candidate _entity.AcquisitionCandidate, err error) {
+	return
+}
+
+type fakeDetector struct{}
+
+func (fd fakeDetector) FindBooks(title string, authors []string) (exact _entity.Book, similar []_entity.PossibleDuplicate, err error) {
+	return
+}
+
+func (fd fakeDetector) FindSuggestions(title string) (similar []_entity.PossibleDuplicate, err error) {
+	return
+}
+
+func (fd fakeDetector) ResolveAuthor(name string) (author _entity.Author, similar []_entity.PossibleDuplicate, err error) {
+	author.Name = name
+	return
+}
+
+func TestCreateBookFromISBN(t *testing.T) {
+	provider := _enrichment.NewStubProvider(_model.CreateBookRequest{
+		Title:       "the pragmatic programmer",
+		Author:      []_model.CreateAuthorRequest{{Name: "Andrew Hunt"}, {Name: "David Thomas"}},
+		Publisher:   "Addison-Wesley",
+		Language:    "English",
+		Pages:       352,
+		Category:    "Computers",
+		ISBN13:      "978-0-306-40615-7",
+		Description: "From journeyman to master.",
+	})
+
+	tests := []struct {
+		name      string
+		req       _model.CreateBookRequest
+		code      int
+		title     string
+		publisher string
+	}{
+		{"filled from provider by isbn-10", _model.CreateBookRequest{ISBN13: "0-306-40615-2", Quantity: 2}, http.StatusCreated, "The Pragmatic Programmer", "Addison-Wesley"},
+		{"request is preferred over provider", _model.CreateBookRequest{ISBN13: "9780306406157", Publisher: "Pearson", Quantity: 1}, http.StatusCreated, "The Pragmatic Programmer", "Pearson"},
+		{"unknown isbn", _model.CreateBookRequest{ISBN13: "9780804429573", Quantity: 1}, http.StatusNotFound, "", ""},
+		{"invalid isbn", _model.CreateBookRequest{ISBN13: "9780306406158", Quantity: 1}, http.StatusBadRequest, "", ""},
+		{"provider does not fill quantity", _model.CreateBookRequest{ISBN13: "9780306406157"}, http.StatusBadRequest, "", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repository := &fakeBookRepository{}
+			buc := New(repository, fakeAcquisitionRepository{}, fakeDetector{}, provider)
+
+			res, code, message := buc.CreateBookFromISBN(test.req)
+
+			if code != test.code {
+				t.Fatalf("code = %d (%s), want %d", code, message, test.code)
+			}
+
+			if code != http.StatusCreated {
+				if len(repository.books) != 0 {
+					t.Errorf("book is created on failure")
+				}
+
+				return
+			}
+
+			if res.Book.Title != test.title || res.Book.Publisher != test.publisher || res.Book.ISBN13 != "9780306406157" {
+				t.Errorf("unexpected book: %+v", res.Book)
+			}
+
+			if len(res.Book.Author) != 2 || res.Book.Author[0].Name != "Andrew Hunt" {
+				t.Errorf("unexpected authors: %+v", res.Book.Author)
+			}
+
+			if repository.items != test.req.Quantity {
+				t.Errorf("items = %d, want %d", repository.items, test.req.Quantity)
+			}
+		})
+	}
+}
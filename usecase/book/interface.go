@@ -7,8 +7,14 @@ import (
 
 type Book interface {
 	CreateBook(req _model.CreateBookRequest) (res _model.CreateBookResponse, code int, message string)
-	GetAllBooks(query url.Values) (res _model.GetAllBooksResponse, code int, message string)
-	GetBookById(bookId uint) (res _model.GetBookByIdResponse, code int, message string)
+	CreateBookFromISBN(req _model.CreateBookRequest) (res _model.CreateBookResponse, code int, message string)
+	GetAllBooks(query url.Values, languages []string) (res _model.GetAllBooksResponse, code int, message string)
+	GetBookById(bookId uint, languages []string) (res _model.GetBookByIdResponse, code int, message string)
 	UpdateBook(req _model.UpdateBookRequest, bookId uint) (res _model.UpdateBookResponse, code int, message string)
 	DeleteBook(bookId uint) (code int, message string)
+	MergeBook(librarianId uint, bookId uint, req _model.MergeBookRequest) (res _model.MergeBookResponse, code int, message string)
+	GetTranslations(bookId uint) (res _model.GetBookTranslationsResponse, code int, message string)
+	SaveTranslation(bookId uint, locale string, req _model.SaveBookTranslationRequest) (res _model.SaveBookTranslationResponse, code int, message string)
+	DeleteTranslation(bookId uint, locale string) (code int, message string)
+	RebuildSearchIndex() (code int, message string)
 }
@@ -0,0 +1,124 @@
+package catalog
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	_bookRepository "plain-go/public-library/datastore/book"
+	_entity "plain-go/public-library/entity"
+	_model "plain-go/public-library/model"
+	_bookUseCase "plain-go/public-library/usecase/book"
+	"strconv"
+	"strings"
+)
+
+type CatalogUseCase struct {
+	bookRepo _bookRepository.Book
+	book     _bookUseCase.Book
+}
+
+func New(bookRepo _bookRepository.Book, book _bookUseCase.Book) *CatalogUseCase {
+	return &CatalogUseCase{bookRepo: bookRepo, book: book}
+}
+
+var formats = map[string]interface{}{
+	"marc21":  nil,
+	"marcxml": nil,
+}
+
+// maximum copies created for each imported record
+const maxCopies = 100
+
+func (cuc CatalogUseCase) ImportBooks(query url.Values, body io.Reader) (res _model.ImportBooksResponse, code int, message string) {
+	input := bufio.NewReader(body)
+
+	// format is detected from content when not given
+	format := strings.ToLower(strings.TrimSpace(query.Get("format")))
+
+	if format == "" {
+		format = "marc21"
+
+		if head, _ := input.Peek(512); bytes.HasPrefix(bytes.TrimLeft(head, "\xef\xbb\xbf \t\r\n"), []byte("<")) {
+			format = "marcxml"
+		}
+	}
+
+	if _, exist := formats[format]; !exist {
+		log.Println("invalid format")
+		code, message = http.StatusBadRequest, "invalid format"
+		return
+	}
+
+	copies := 1
+
+	if query.Get("copies") != "" {
+		var err error
+
+		if copies, err = strconv.Atoi(query.Get("copies")); err != nil || copies < 1 || copies > maxCopies {
+			log.Println("invalid number of copies")
+			code, message = http.StatusBadRequest, "invalid number of copies"
+			return
+		}
+	}
+
+	var reader Reader
+
+	if format == "marcxml" {
+		reader = NewMARCXMLReader(input)
+	} else {
+		reader = NewMARC21Reader(input)
+	}
+
+	res.Records = []_entity.ImportedRecord{}
+
+	for index := uint(1); ; index++ {
+		record, err := reader.Read()
+
+		if err == io.EOF {
+			break
+		}
+
+		report := _entity.ImportedRecord{Index: index}
+
+		// malformed record is reported and the rest is still imported
+		if err != nil {
+			log.Println(err)
+			report.Status, report.Message = "failed", err.Error()
+			res.Failed++
+			res.Records = append(res.Records, report)
+			continue
+		}
+
+		req := toBookRequest(record, uint(copies))
+		report.Title, report.ISBN13 = req.Title, req.ISBN13
+
+		// each record goes through the same validation and duplicate check as single book creation
+		created, createCode, createMessage := cuc.book.CreateBook(req)
+
+		switch createCode {
+		case http.StatusCreated:
+			report.Status, report.BookId = "created", created.Book.Id
+			res.Created++
+		case http.StatusConflict:
+			report.Status, report.Message = "skipped", createMessage
+			res.Skipped++
+		default:
+			report.Status, report.Message = "failed", createMessage
+			res.Failed++
+		}
+
+		res.Records = append(res.Records, report)
+	}
+
+	if len(res.Records) == 0 {
+		log.Println("no record found")
+		code, message = http.StatusBadRequest, "no record found"
+		return
+	}
+
+	code, message = http.StatusOK, "success import books"
+	return
+}
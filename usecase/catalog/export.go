@@ -0,0 +1,226 @@
+package catalog
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	_entity "plain-go/public-library/entity"
+	_helper "plain-go/public-library/helper"
+	_model "plain-go/public-library/model"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// export format to content type
+var exportFormats = map[string]string{
+	"csv":     "text/csv; charset=utf-8",
+	"jsonl":   "application/x-ndjson",
+	"marc21":  "application/marc",
+	"marcxml": "application/marcxml+xml",
+}
+
+var csvHeader = []string{"id", "title", "authors", "publisher", "language", "pages", "category", "isbn13", "description", "copies", "available", "created_at", "updated_at"}
+
+func (cuc CatalogUseCase) ExportBooks(query url.Values) (export func(w io.Writer) error, contentType string, code int, message string) {
+	format := strings.ToLower(strings.TrimSpace(query.Get("format")))
+
+	if format == "" {
+		format = "jsonl"
+	}
+
+	contentType, exist := exportFormats[format]
+
+	if !exist {
+		log.Println("invalid format")
+		code, message = http.StatusBadRequest, "invalid format"
+		return
+	}
+
+	params := _model.ExportBooksRequest{Category: strings.TrimSpace(query.Get("category"))}
+
+	// date range is inclusive on both ends and applies to the last change of the book
+	var err error
+
+	if from := query.Get("from"); from != "" {
+		if params.From, err = time.ParseInLocation("2006-01-02", from, time.Local); err != nil {
+			log.Println(err)
+			code, message = http.StatusBadRequest, "invalid date"
+			return
+		}
+	}
+
+	if to := query.Get("to"); to != "" {
+		if params.To, err = time.ParseInLocation("2006-01-02", to, time.Local); err != nil {
+			log.Println(err)
+			code, message = http.StatusBadRequest, "invalid date"
+			return
+		}
+
+		params.To = params.To.AddDate(0, 0, 1)
+	}
+
+	if !params.From.IsZero() && !params.To.IsZero() && !params.From.Before(params.To) {
+		log.Println("invalid date range")
+		code, message = http.StatusBadRequest, "invalid date range"
+		return
+	}
+
+	export = func(w io.Writer) (err error) {
+		switch format {
+		case "csv":
+			writer := csv.NewWriter(w)
+
+			if err = writer.Write(csvHeader); err != nil {
+				return
+			}
+
+			err = cuc.bookRepo.ExportBooks(params, func(book _entity.ExportedBook) error {
+				writer.Write(toCSVRow(book))
+				writer.Flush()
+				return writer.Error()
+			})
+		case "jsonl":
+			encoder := json.NewEncoder(w)
+
+			err = cuc.bookRepo.ExportBooks(params, func(book _entity.ExportedBook) error {
+				return encoder.Encode(book)
+			})
+		case "marc21":
+			err = cuc.bookRepo.ExportBooks(params, func(book _entity.ExportedBook) error {
+				// a record exceeding ISO 2709 limit is left out instead of breaking the file
+				if err := WriteMARC21(w, ToRecord(book)); err != nil {
+					log.Println(err)
+				}
+
+				return nil
+			})
+		case "marcxml":
+			writer, err := NewMARCXMLWriter(w)
+
+			if err != nil {
+				return err
+			}
+
+			if err = cuc.bookRepo.ExportBooks(params, func(book _entity.ExportedBook) error {
+				return writer.Write(ToRecord(book))
+			}); err != nil {
+				return err
+			}
+
+			err = writer.Close()
+			return err
+		}
+
+		return
+	}
+
+	code, message = http.StatusOK, "success export books"
+	return
+}
+
+func authorNames(book _entity.ExportedBook) (names []string) {
+	for _, author := range book.Author {
+		names = append(names, author.Name)
+	}
+
+	return
+}
+
+func toCSVRow(book _entity.ExportedBook) []string {
+	return []string{
+		strconv.Itoa(int(book.Id)),
+		book.Title,
+		strings.Join(authorNames(book), "; "),
+		book.Publisher,
+		book.Language,
+		strconv.Itoa(int(book.Pages)),
+		book.Category,
+		book.ISBN13,
+		book.Description,
+		strconv.Itoa(int(book.Quantity)),
+		strconv.Itoa(int(book.Available)),
+		book.CreatedAt.Format("2006-01-02 15:04:05"),
+		book.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// surnameFirst turns "Brian W. Kernighan" into "Kernighan, Brian W.", single name is kept as forename
+func surnameFirst(name string) (ind1 string, inverted string) {
+	words := strings.Fields(name)
+
+	if len(words) < 2 {
+		return "0", name
+	}
+
+	return "1", words[len(words)-1] + ", " + strings.Join(words[:len(words)-1], " ")
+}
+
+// ToRecord is the reverse of toBookRequest, copies and availability go to local field 999
+func ToRecord(book _entity.ExportedBook) (record Record) {
+	record.Leader = "00000nam a2200000   4500"
+
+	// 008 holds date entered and language at position 35-37
+	fixed := []byte(fmt.Sprintf("%-40s", book.CreatedAt.Format("060102")+"s"+book.CreatedAt.Format("2006")))
+	if code := _helper.LanguageCode(book.Language); code != "" {
+		copy(fixed[35:38], code)
+	}
+	fixed[39] = 'd'
+
+	record.Fields = append(record.Fields,
+		Field{Tag: "001", Value: strconv.Itoa(int(book.Id))},
+		Field{Tag: "005", Value: book.UpdatedAt.Format("20060102150405") + ".0"},
+		Field{Tag: "008", Value: string(fixed)},
+	)
+
+	if book.ISBN13 != "" {
+		record.Fields = append(record.Fields, Field{Tag: "020", Subfields: []Subfield{{"a", book.ISBN13}}})
+	}
+
+	// the first author is main entry, the rest are added entries which come after subjects
+	titleInd1, added := "0", []Field{}
+
+	for i, author := range book.Author {
+		ind1, name := surnameFirst(author.Name)
+
+		if i == 0 {
+			titleInd1 = "1"
+			record.Fields = append(record.Fields, Field{Tag: "100", Ind1: ind1, Subfields: []Subfield{{"a", name}}})
+			continue
+		}
+
+		added = append(added, Field{Tag: "700", Ind1: ind1, Subfields: []Subfield{{"a", name}}})
+	}
+
+	title := []Subfield{{"a", book.Title}}
+
+	if parts := strings.SplitN(book.Title, ": ", 2); len(parts) == 2 {
+		title = []Subfield{{"a", parts[0]}, {"b", parts[1]}}
+	}
+
+	record.Fields = append(record.Fields,
+		Field{Tag: "245", Ind1: titleInd1, Ind2: "0", Subfields: title},
+		Field{Tag: "264", Ind2: "1", Subfields: []Subfield{{"b", book.Publisher}}},
+		Field{Tag: "300", Subfields: []Subfield{{"a", strconv.Itoa(int(book.Pages)) + " pages"}}},
+	)
+
+	if book.Description != "" {
+		record.Fields = append(record.Fields, Field{Tag: "520", Subfields: []Subfield{{"a", book.Description}}})
+	}
+
+	if book.Category != "" {
+		record.Fields = append(record.Fields, Field{Tag: "650", Ind2: "4", Subfields: []Subfield{{"a", book.Category}}})
+	}
+
+	record.Fields = append(record.Fields, added...)
+	record.Fields = append(record.Fields, Field{Tag: "999", Subfields: []Subfield{
+		{"c", strconv.Itoa(int(book.Quantity))},
+		{"d", strconv.Itoa(int(book.Available))},
+	}})
+
+	return
+}
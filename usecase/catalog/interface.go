@@ -0,0 +1,12 @@
+package catalog
+
+import (
+	"io"
+	"net/url"
+	_model "plain-go/public-library/model"
+)
+
+type Catalog interface {
+	ExportBooks(query url.Values) (export func(w io.Writer) error, contentType string, code int, message string)
+	ImportBooks(query url.Values, body io.Reader) (res _model.ImportBooksResponse, code int, message string)
+}
@@ -0,0 +1,146 @@
+package catalog
+
+import (
+	_helper "plain-go/public-library/helper"
+	_model "plain-go/public-library/model"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var pagesPattern = regexp.MustCompile(`(\d+)\s*(p\b|p\.|pages|hlm|halaman)`)
+var numberPattern = regexp.MustCompile(`\d+`)
+
+// trimPunctuation strips ISBD punctuation which ends a subfield, such as "Title /" or "Publisher,"
+func trimPunctuation(s string) string {
+	return strings.TrimSpace(strings.TrimRight(strings.TrimSpace(s), " /:;,=."))
+}
+
+// trimName keeps the period of trailing initial such as "Brian W."
+func trimName(s string) string {
+	name := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(s), " ,;:/"))
+	words := strings.Fields(name)
+
+	if strings.HasSuffix(name, ".") && len(words) > 0 && len([]rune(words[len(words)-1])) > 2 {
+		name = strings.TrimSuffix(name, ".")
+	}
+
+	return name
+}
+
+// invertName turns "Kernighan, Brian W." into "Brian W. Kernighan" for personal name entered under surname
+func invertName(field Field) string {
+	name := trimName(field.GetValue("a"))
+
+	if field.Ind1 != "1" {
+		return name
+	}
+
+	parts := strings.SplitN(name, ",", 2)
+
+	if len(parts) != 2 {
+		return name
+	}
+
+	return strings.TrimSpace(parts[1]) + " " + strings.TrimSpace(parts[0])
+}
+
+// toBookRequest maps 245 title, 100/700 authors, 260/264 publisher, 020 ISBN, 300 pages and 650 subjects
+func toBookRequest(record Record, copies uint) (req _model.CreateBookRequest) {
+	req.Quantity = copies
+
+	for _, field := range record.GetFields("245") {
+		req.Title = trimPunctuation(field.GetValue("a"))
+
+		if subtitle := trimPunctuation(field.GetValue("b")); subtitle != "" {
+			req.Title += ": " + subtitle
+		}
+
+		break
+	}
+
+	for _, tag := range []string{"100", "700"} {
+		for _, field := range record.GetFields(tag) {
+			if name := invertName(field); name != "" {
+				req.Author = append(req.Author, _model.CreateAuthorRequest{Name: name})
+			}
+		}
+	}
+
+	// 264 with second indicator 1 is publication, older record uses 260
+	for _, field := range record.GetFields("264") {
+		if field.Ind2 == "1" && req.Publisher == "" {
+			req.Publisher = trimPunctuation(field.GetValue("b"))
+		}
+	}
+
+	for _, field := range record.GetFields("260") {
+		if req.Publisher == "" {
+			req.Publisher = trimPunctuation(field.GetValue("b"))
+		}
+	}
+
+	// the first valid ISBN is taken, qualifier such as "(pbk.)" is dropped
+	for _, field := range record.GetFields("020") {
+		if value := strings.Fields(field.GetValue("a")); len(value) > 0 {
+			if isbn13, err := _helper.ParseISBN(value[0]); err == nil {
+				req.ISBN13 = isbn13
+				break
+			}
+		}
+	}
+
+	for _, field := range record.GetFields("300") {
+		extent := field.GetValue("a")
+
+		if match := pagesPattern.FindStringSubmatch(extent); match != nil {
+			pages, _ := strconv.Atoi(match[1])
+			req.Pages = uint(pages)
+		} else if match := numberPattern.FindString(extent); match != "" {
+			pages, _ := strconv.Atoi(match)
+			req.Pages = uint(pages)
+		}
+
+		break
+	}
+
+	// language is taken from 041, otherwise from position 35-37 of 008
+	code := ""
+
+	for _, field := range record.GetFields("041") {
+		code = field.GetValue("a")
+		break
+	}
+
+	for _, field := range record.GetFields("008") {
+		if code == "" && len(field.Value) >= 38 {
+			code = strings.TrimSpace(field.Value[35:38])
+		}
+	}
+
+	req.Language = _helper.LanguageName(code)
+
+	// the first subject becomes category, summary falls back to the list of subjects
+	subjects := []string{}
+
+	for _, field := range record.GetFields("650") {
+		if subject := trimPunctuation(field.GetValue("a")); subject != "" {
+			subjects = append(subjects, subject)
+		}
+	}
+
+	if len(subjects) > 0 {
+		req.Category = subjects[0]
+	}
+
+	for _, field := range record.GetFields("520") {
+		req.Description = strings.TrimSpace(field.GetValue("a"))
+		break
+	}
+
+	if req.Description == "" {
+		req.Description = strings.Join(subjects, "; ")
+	}
+
+	return
+}
@@ -0,0 +1,338 @@
+package catalog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const marcNamespace = "http://www.loc.gov/MARC21/slim"
+
+// ISO 2709 delimiters
+const (
+	subfieldDelimiter = 0x1F
+	fieldTerminator   = 0x1E
+	recordTerminator  = 0x1D
+)
+
+type Subfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+// field is either control field (tag below 010) holding Value or data field holding indicators and subfields
+type Field struct {
+	Tag       string
+	Ind1      string
+	Ind2      string
+	Value     string
+	Subfields []Subfield
+}
+
+type Record struct {
+	Leader string
+	Fields []Field
+}
+
+// GetFields returns all fields of the tag in record order
+func (r Record) GetFields(tag string) (fields []Field) {
+	for _, field := range r.Fields {
+		if field.Tag == tag {
+			fields = append(fields, field)
+		}
+	}
+
+	return
+}
+
+// GetValue returns the first subfield of the code
+func (f Field) GetValue(code string) string {
+	for _, subfield := range f.Subfields {
+		if subfield.Code == code {
+			return subfield.Value
+		}
+	}
+
+	return ""
+}
+
+func isControlTag(tag string) bool {
+	return tag < "010"
+}
+
+// reader returns io.EOF after the last record, any other error belongs to a single record
+type Reader interface {
+	Read() (record Record, err error)
+}
+
+type marc21Reader struct {
+	reader *bufio.Reader
+}
+
+func NewMARC21Reader(r io.Reader) Reader {
+	return &marc21Reader{reader: bufio.NewReader(r)}
+}
+
+func (mr *marc21Reader) Read() (record Record, err error) {
+	data, err := mr.reader.ReadBytes(recordTerminator)
+
+	// records are sometimes separated by line break
+	data = bytes.TrimLeft(data, "\r\n ")
+
+	if err == io.EOF {
+		if len(bytes.TrimSpace(data)) == 0 {
+			return
+		}
+
+		// the last record is missing its terminator
+		err = nil
+	}
+
+	if err != nil {
+		return
+	}
+
+	return parseMARC21(data)
+}
+
+func parseMARC21(data []byte) (record Record, err error) {
+	if len(data) < 25 {
+		err = errors.New("record is too short")
+		return
+	}
+
+	record.Leader = string(data[:24])
+	base, err := strconv.Atoi(string(data[12:17]))
+
+	if err != nil || base <= 24 || base > len(data) {
+		err = errors.New("invalid base address of data")
+		return
+	}
+
+	// directory entry is tag (3), field length (4) and starting position (5)
+	directory := data[24 : base-1]
+
+	if len(directory)%12 != 0 {
+		err = errors.New("invalid directory")
+		return
+	}
+
+	for i := 0; i < len(directory); i += 12 {
+		entry := directory[i : i+12]
+		length, lengthErr := strconv.Atoi(string(entry[3:7]))
+		start, startErr := strconv.Atoi(string(entry[7:12]))
+
+		// sign is accepted by Atoi, so negative values are rejected before slicing
+		if lengthErr != nil || startErr != nil || length < 1 || start < 0 || base+start+length > len(data) {
+			err = errors.New("invalid directory entry")
+			return
+		}
+
+		field := Field{Tag: string(entry[:3])}
+		value := bytes.TrimRight(data[base+start:base+start+length], string([]byte{fieldTerminator, recordTerminator}))
+
+		if isControlTag(field.Tag) {
+			field.Value = string(value)
+			record.Fields = append(record.Fields, field)
+			continue
+		}
+
+		if len(value) < 2 {
+			err = errors.New("invalid data field " + field.Tag)
+			return
+		}
+
+		field.Ind1, field.Ind2 = string(value[0]), string(value[1])
+
+		for _, part := range bytes.Split(value[2:], []byte{subfieldDelimiter}) {
+			if len(part) == 0 {
+				continue
+			}
+
+			field.Subfields = append(field.Subfields, Subfield{Code: string(part[0]), Value: string(part[1:])})
+		}
+
+		record.Fields = append(record.Fields, field)
+	}
+
+	return
+}
+
+type xmlControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlDataField struct {
+	Tag       string     `xml:"tag,attr"`
+	Ind1      string     `xml:"ind1,attr"`
+	Ind2      string     `xml:"ind2,attr"`
+	Subfields []Subfield `xml:"subfield"`
+}
+
+type xmlRecord struct {
+	XMLName       xml.Name          `xml:"record"`
+	Leader        string            `xml:"leader"`
+	ControlFields []xmlControlField `xml:"controlfield"`
+	DataFields    []xmlDataField    `xml:"datafield"`
+}
+
+type marcXMLReader struct {
+	decoder *xml.Decoder
+	done    bool
+}
+
+func NewMARCXMLReader(r io.Reader) Reader {
+	return &marcXMLReader{decoder: xml.NewDecoder(r)}
+}
+
+func (mr *marcXMLReader) Read() (record Record, err error) {
+	if mr.done {
+		err = io.EOF
+		return
+	}
+
+	// record element is picked up wherever it is, inside collection or standing alone
+	for {
+		token, tokenErr := mr.decoder.Token()
+
+		if tokenErr != nil {
+			// malformed document can not be read any further
+			mr.done, err = true, tokenErr
+			return
+		}
+
+		start, ok := token.(xml.StartElement)
+
+		if !ok || start.Name.Local != "record" {
+			continue
+		}
+
+		raw := xmlRecord{}
+
+		if err = mr.decoder.DecodeElement(&raw, &start); err != nil {
+			mr.done = true
+			return
+		}
+
+		record.Leader = raw.Leader
+
+		for _, field := range raw.ControlFields {
+			record.Fields = append(record.Fields, Field{Tag: field.Tag, Value: field.Value})
+		}
+
+		for _, field := range raw.DataFields {
+			record.Fields = append(record.Fields, Field{Tag: field.Tag, Ind1: field.Ind1, Ind2: field.Ind2, Subfields: field.Subfields})
+		}
+
+		return
+	}
+}
+
+// WriteMARC21 encodes record in ISO 2709, record length and base address of leader are recalculated
+func WriteMARC21(w io.Writer, record Record) (err error) {
+	directory, data := bytes.Buffer{}, bytes.Buffer{}
+
+	for _, field := range record.Fields {
+		value := bytes.Buffer{}
+
+		if isControlTag(field.Tag) {
+			value.WriteString(field.Value)
+		} else {
+			value.WriteString(indicator(field.Ind1) + indicator(field.Ind2))
+
+			for _, subfield := range field.Subfields {
+				value.WriteByte(subfieldDelimiter)
+				value.WriteString(subfield.Code + subfield.Value)
+			}
+		}
+
+		value.WriteByte(fieldTerminator)
+
+		if value.Len() > 9999 || data.Len() > 99999 {
+			err = errors.New("field " + field.Tag + " is too long")
+			return
+		}
+
+		directory.WriteString(fmt.Sprintf("%s%04d%05d", field.Tag, value.Len(), data.Len()))
+		data.Write(value.Bytes())
+	}
+
+	directory.WriteByte(fieldTerminator)
+	data.WriteByte(recordTerminator)
+
+	base := 24 + directory.Len()
+	length := base + data.Len()
+
+	if length > 99999 {
+		err = errors.New("record is too long")
+		return
+	}
+
+	leader := []byte(fmt.Sprintf("%-24s", record.Leader))[:24]
+	copy(leader[0:5], fmt.Sprintf("%05d", length))
+	copy(leader[12:17], fmt.Sprintf("%05d", base))
+
+	_, err = w.Write(append(append(leader, directory.Bytes()...), data.Bytes()...))
+	return
+}
+
+func indicator(ind string) string {
+	if ind == "" {
+		return " "
+	}
+
+	return ind[:1]
+}
+
+type MARCXMLWriter struct {
+	writer  io.Writer
+	encoder *xml.Encoder
+}
+
+// NewMARCXMLWriter starts a collection, Close must be called to end it
+func NewMARCXMLWriter(w io.Writer) (mw *MARCXMLWriter, err error) {
+	mw = &MARCXMLWriter{writer: w, encoder: xml.NewEncoder(w)}
+	_, err = io.WriteString(w, xml.Header+`<collection xmlns="`+marcNamespace+`">`+"\n")
+	return
+}
+
+func toXMLRecord(record Record) (raw xmlRecord) {
+	raw.Leader = record.Leader
+
+	for _, field := range record.Fields {
+		if isControlTag(field.Tag) {
+			raw.ControlFields = append(raw.ControlFields, xmlControlField{Tag: field.Tag, Value: field.Value})
+		} else {
+			raw.DataFields = append(raw.DataFields, xmlDataField{Tag: field.Tag, Ind1: indicator(field.Ind1), Ind2: indicator(field.Ind2), Subfields: field.Subfields})
+		}
+	}
+
+	return
+}
+
+// MarshalMARCXML encodes a single record carrying its own namespace, to be embedded in other document
+func MarshalMARCXML(record Record) ([]byte, error) {
+	raw := toXMLRecord(record)
+	raw.XMLName = xml.Name{Space: marcNamespace, Local: "record"}
+
+	return xml.Marshal(raw)
+}
+
+func (mw *MARCXMLWriter) Write(record Record) (err error) {
+	if err = mw.encoder.Encode(toXMLRecord(record)); err != nil {
+		return
+	}
+
+	_, err = io.WriteString(mw.writer, "\n")
+	return
+}
+
+func (mw *MARCXMLWriter) Close() (err error) {
+	_, err = io.WriteString(mw.writer, "</collection>\n")
+	return
+}
@@ -0,0 +1,80 @@
+package catalog
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// buildMARC21 assembles a record from directory entries and field data, base address is computed
+func buildMARC21(directory string, fields string) []byte {
+	base := 24 + len(directory) + 1
+	leader := fmt.Sprintf("%05dnam a22%05d a 4500", 24+len(directory)+1+len(fields)+1, base)
+
+	return []byte(leader + directory + string(rune(fieldTerminator)) + fields + string(rune(recordTerminator)))
+}
+
+func TestParseMARC21(t *testing.T) {
+	title := "10" + string(rune(subfieldDelimiter)) + "aDune" + string(rune(fieldTerminator))
+	record, err := parseMARC21(buildMARC21(fmt.Sprintf("245%04d%05d", len(title), 0), title))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fields := record.GetFields("245")
+
+	if len(fields) != 1 || fields[0].GetValue("a") != "Dune" || fields[0].Ind1 != "1" || fields[0].Ind2 != "0" {
+		t.Errorf("unexpected fields: %+v", record.Fields)
+	}
+}
+
+func TestParseMARC21Malformed(t *testing.T) {
+	field := "10" + string(rune(subfieldDelimiter)) + "aDune" + string(rune(fieldTerminator))
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"too short", []byte("00024nam a2200025 a 4500")},
+		{"base address not a number", []byte("00030nam a22abcde a 4500" + "xxxxxx")},
+		{"base address inside leader", []byte("00030nam a2200010 a 4500" + "xxxxxx")},
+		{"base address beyond record", []byte("00030nam a2299999 a 4500" + "xxxxxx")},
+		{"directory not multiple of entry", buildMARC21("245001", field)},
+		{"length not a number", buildMARC21("245abcd00000", field)},
+		{"negative length", buildMARC21("245-99900000", field)},
+		{"zero length", buildMARC21("245000000000", field)},
+		{"negative start", buildMARC21("2450011-0001", field)},
+		{"field beyond record", buildMARC21("245009900000", field)},
+		{"data field without indicators", buildMARC21("245000100000", field)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := parseMARC21(test.data); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestMARC21ReaderSkipsMalformedRecord(t *testing.T) {
+	field := "10" + string(rune(subfieldDelimiter)) + "aDune" + string(rune(fieldTerminator))
+	valid := buildMARC21(fmt.Sprintf("245%04d%05d", len(field), 0), field)
+	malformed := buildMARC21("245-99900000", field)
+
+	reader := NewMARC21Reader(strings.NewReader(string(malformed) + "\n" + string(valid)))
+
+	if _, err := reader.Read(); err == nil || err == io.EOF {
+		t.Fatalf("expected error of malformed record, got %v", err)
+	}
+
+	if record, err := reader.Read(); err != nil || record.GetFields("245")[0].GetValue("a") != "Dune" {
+		t.Fatalf("expected valid record after malformed one, got %+v, %v", record, err)
+	}
+
+	if _, err := reader.Read(); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
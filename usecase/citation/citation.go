@@ -0,0 +1,137 @@
+package citation
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	_bookRepository "plain-go/public-library/datastore/book"
+	_entity "plain-go/public-library/entity"
+	_favoriteUseCase "plain-go/public-library/usecase/favorite"
+	_readingListUseCase "plain-go/public-library/usecase/readinglist"
+	"strings"
+)
+
+type CitationUseCase struct {
+	bookRepo _bookRepository.Book
+	list     _readingListUseCase.ReadingList
+	favorite _favoriteUseCase.Favorite
+}
+
+func New(book _bookRepository.Book, list _readingListUseCase.ReadingList, favorite _favoriteUseCase.Favorite) *CitationUseCase {
+	return &CitationUseCase{bookRepo: book, list: list, favorite: favorite}
+}
+
+// parseFormat defaults to CSL-JSON, which every reference manager imports
+func parseFormat(query url.Values) (format string, contentType string, code int, message string) {
+	format = "csl-json"
+
+	if value, exist := query["format"]; exist {
+		format = strings.ToLower(strings.TrimSpace(value[0]))
+	}
+
+	contentType, exist := formats[format]
+
+	if !exist {
+		log.Println("unaccepted citation format")
+		code, message = http.StatusBadRequest, "format must be one of bibtex, ris, csl-json, apa or mla"
+		return
+	}
+
+	return
+}
+
+func (cuc CitationUseCase) cite(format string, contentType string, books []_entity.Book) (res []byte, resContentType string, code int, message string) {
+	res, err := render(format, books)
+
+	if err != nil {
+		log.Println(err)
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	resContentType, code, message = contentType, http.StatusOK, "success create citation"
+
+	return
+}
+
+func (cuc CitationUseCase) CiteBook(bookId uint, query url.Values) (res []byte, contentType string, code int, message string) {
+	format, contentType, code, message := parseFormat(query)
+
+	if code != 0 {
+		return
+	}
+
+	// check book existence
+	book, err := cuc.bookRepo.GetBookById(bookId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if book.Title == "" {
+		log.Println("book not found")
+		code, message = http.StatusNotFound, "book not found"
+		return
+	}
+
+	book.Author, err = cuc.bookRepo.GetBookAuthors(bookId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	return cuc.cite(format, contentType, []_entity.Book{book})
+}
+
+// CiteReadingList follows visibility of the list, link only list needs its share token in query
+func (cuc CitationUseCase) CiteReadingList(viewerId uint, listId uint, query url.Values) (res []byte, contentType string, code int, message string) {
+	format, contentType, code, message := parseFormat(query)
+
+	if code != 0 {
+		return
+	}
+
+	list, code, message := cuc.list.GetListById(viewerId, listId, query.Get("token"))
+
+	if code != http.StatusOK {
+		return
+	}
+
+	books := []_entity.Book{}
+
+	for _, item := range list.List.Items {
+		books = append(books, item.Book)
+	}
+
+	return cuc.cite(format, contentType, books)
+}
+
+func (cuc CitationUseCase) CiteFavorites(userId uint, query url.Values) (res []byte, contentType string, code int, message string) {
+	format, contentType, code, message := parseFormat(query)
+
+	if code != 0 {
+		return
+	}
+
+	favorites, code, message := cuc.favorite.GetAllFavoritesByUserId(userId)
+
+	if code != http.StatusOK {
+		return
+	}
+
+	books := []_entity.Book{}
+
+	for _, favorite := range favorites.Favorites {
+		// omit deleted book
+		if favorite.Book.Title == "" {
+			continue
+		}
+
+		books = append(books, favorite.Book)
+	}
+
+	return cuc.cite(format, contentType, books)
+}
@@ -0,0 +1,324 @@
+package citation
+
+import (
+	"bytes"
+	"encoding/json"
+	_entity "plain-go/public-library/entity"
+	_helper "plain-go/public-library/helper"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// splitName separates family and given name, accepting both "Given Family" and "Family, Given"
+func splitName(name string) (family string, given string) {
+	name = strings.Join(strings.Fields(name), " ")
+
+	if parts := strings.SplitN(name, ",", 2); len(parts) == 2 {
+		return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	}
+
+	if i := strings.LastIndex(name, " "); i >= 0 {
+		return name[i+1:], name[:i]
+	}
+
+	return name, ""
+}
+
+// initials turns "Brian W." into "B. W."
+func initials(given string) string {
+	result := []string{}
+
+	for _, part := range strings.Fields(given) {
+		// hyphenated given name keeps its hyphen, such as J.-P.
+		hyphenated := []string{}
+
+		for _, piece := range strings.Split(part, "-") {
+			if r := []rune(piece); len(r) > 0 {
+				hyphenated = append(hyphenated, string(unicode.ToUpper(r[0]))+".")
+			}
+		}
+
+		result = append(result, strings.Join(hyphenated, "-"))
+	}
+
+	return strings.Join(result, " ")
+}
+
+func authorNames(book _entity.Book) (names []string) {
+	for _, author := range book.Author {
+		if name := strings.TrimSpace(author.Name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return
+}
+
+var bibtexEscaper = strings.NewReplacer(`\`, `\textbackslash{}`, "{", `\{`, "}", `\}`, "&", `\&`, "%", `\%`, "$", `\$`, "#", `\#`, "_", `\_`)
+
+// bibtexKey is family name of the first author and first word of title, book id keeps it unique
+func bibtexKey(book _entity.Book) string {
+	key := ""
+
+	if names := authorNames(book); len(names) > 0 {
+		family, _ := splitName(names[0])
+		key = family
+	}
+
+	for _, word := range strings.Fields(book.Title) {
+		if lower := strings.ToLower(word); lower != "the" && lower != "a" && lower != "an" {
+			key += "_" + word
+			break
+		}
+	}
+
+	clean := []rune{}
+
+	for _, r := range strings.ToLower(key) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			clean = append(clean, r)
+		}
+	}
+
+	return strings.TrimPrefix(string(clean), "_") + "_" + strconv.Itoa(int(book.Id))
+}
+
+func BibTeX(book _entity.Book) string {
+	authors := []string{}
+
+	for _, name := range authorNames(book) {
+		family, given := splitName(name)
+		authors = append(authors, strings.TrimSuffix(family+", "+given, ", "))
+	}
+
+	fields := [][2]string{
+		{"author", strings.Join(authors, " and ")},
+		{"title", book.Title},
+		{"publisher", book.Publisher},
+		{"isbn", book.ISBN13},
+		{"language", book.Language},
+	}
+
+	if book.Pages > 0 {
+		fields = append(fields, [2]string{"pagetotal", strconv.Itoa(int(book.Pages))})
+	}
+
+	entry := strings.Builder{}
+	entry.WriteString("@book{" + bibtexKey(book) + ",\n")
+
+	for _, field := range fields {
+		if field[1] != "" {
+			entry.WriteString("  " + field[0] + " = {" + bibtexEscaper.Replace(field[1]) + "},\n")
+		}
+	}
+
+	entry.WriteString("}\n")
+
+	return entry.String()
+}
+
+// RIS lines are terminated by CRLF as in the specification
+func RIS(book _entity.Book) string {
+	lines := []string{"TY  - BOOK"}
+
+	for _, name := range authorNames(book) {
+		family, given := splitName(name)
+		lines = append(lines, "AU  - "+strings.TrimSuffix(family+", "+given, ", "))
+	}
+
+	for _, field := range [][2]string{{"TI", book.Title}, {"PB", book.Publisher}, {"SN", book.ISBN13}, {"LA", book.Language}} {
+		if field[1] != "" {
+			lines = append(lines, field[0]+"  - "+field[1])
+		}
+	}
+
+	lines = append(lines, "ID  - "+strconv.Itoa(int(book.Id)), "ER  - ")
+
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+type cslName struct {
+	Family string `json:"family"`
+	Given  string `json:"given,omitempty"`
+}
+
+type cslItem struct {
+	Id            string    `json:"id"`
+	Type          string    `json:"type"`
+	Title         string    `json:"title"`
+	Author        []cslName `json:"author,omitempty"`
+	Publisher     string    `json:"publisher,omitempty"`
+	ISBN          string    `json:"ISBN,omitempty"`
+	Language      string    `json:"language,omitempty"`
+	NumberOfPages string    `json:"number-of-pages,omitempty"`
+}
+
+func toCSL(book _entity.Book) (item cslItem) {
+	item.Id = "book-" + strconv.Itoa(int(book.Id))
+	item.Type = "book"
+	item.Title = book.Title
+	item.Publisher = book.Publisher
+	item.ISBN = book.ISBN13
+	item.Language = _helper.LanguageTag(book.Language)
+
+	for _, name := range authorNames(book) {
+		family, given := splitName(name)
+		item.Author = append(item.Author, cslName{Family: family, Given: given})
+	}
+
+	if book.Pages > 0 {
+		item.NumberOfPages = strconv.Itoa(int(book.Pages))
+	}
+
+	return
+}
+
+// CSLJSON always encodes an array, as citation processors expect
+func CSLJSON(books []_entity.Book) ([]byte, error) {
+	items := []cslItem{}
+
+	for _, book := range books {
+		items = append(items, toCSL(book))
+	}
+
+	// title is kept readable, ampersand is not escaped for HTML
+	buffer := bytes.Buffer{}
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	err := encoder.Encode(items)
+
+	return buffer.Bytes(), err
+}
+
+func sentence(s string) string {
+	s = strings.TrimSpace(s)
+
+	if s == "" || strings.HasSuffix(s, ".") || strings.HasSuffix(s, "?") || strings.HasSuffix(s, "!") {
+		return s
+	}
+
+	return s + "."
+}
+
+// APA follows the 7th edition reference list, up to 20 authors are listed,
+// catalog does not record publication year so the date is always n.d.
+func APA(book _entity.Book) string {
+	authors := []string{}
+
+	for _, name := range authorNames(book) {
+		family, given := splitName(name)
+		authors = append(authors, strings.TrimSuffix(family+", "+initials(given), ", "))
+	}
+
+	creator := ""
+
+	switch {
+	case len(authors) == 1:
+		creator = authors[0]
+	case len(authors) == 2:
+		creator = authors[0] + ", & " + authors[1]
+	case len(authors) > 20:
+		// the first 19, an ellipsis and the last author
+		creator = strings.Join(authors[:19], ", ") + ", . . . " + authors[len(authors)-1]
+	case len(authors) > 2:
+		creator = strings.Join(authors[:len(authors)-1], ", ") + ", & " + authors[len(authors)-1]
+	}
+
+	parts := []string{}
+
+	// work without author moves its title to the author position
+	if creator == "" {
+		parts = append(parts, sentence(book.Title), "(n.d.).")
+	} else {
+		parts = append(parts, sentence(creator), "(n.d.).", sentence(book.Title))
+	}
+
+	if book.Publisher != "" {
+		parts = append(parts, sentence(book.Publisher))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// MLA follows the 9th edition works cited list, three or more authors are shortened with et al.
+func MLA(book _entity.Book) string {
+	names := authorNames(book)
+	creator := ""
+
+	if len(names) > 0 {
+		family, given := splitName(names[0])
+		creator = strings.TrimSuffix(family+", "+given, ", ")
+	}
+
+	switch {
+	case len(names) == 2:
+		creator += ", and " + names[1]
+	case len(names) > 2:
+		creator += ", et al"
+	}
+
+	parts := []string{}
+
+	if creator != "" {
+		parts = append(parts, sentence(creator))
+	}
+
+	parts = append(parts, sentence(book.Title))
+
+	if book.Publisher != "" {
+		parts = append(parts, sentence(book.Publisher))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// formats of citation, content type of each is sent with the response
+var formats = map[string]string{
+	"bibtex":   "application/x-bibtex; charset=utf-8",
+	"ris":      "application/x-research-info-systems; charset=utf-8",
+	"csl-json": "application/vnd.citationstyles.csl+json; charset=utf-8",
+	"apa":      "text/plain; charset=utf-8",
+	"mla":      "text/plain; charset=utf-8",
+}
+
+// render writes citations of the books, reference list of APA and MLA is sorted alphabetically
+func render(format string, books []_entity.Book) (res []byte, err error) {
+	switch format {
+	case "csl-json":
+		return CSLJSON(books)
+	case "bibtex", "ris":
+		entries := []string{}
+
+		for _, book := range books {
+			if format == "bibtex" {
+				entries = append(entries, BibTeX(book))
+			} else {
+				entries = append(entries, RIS(book))
+			}
+		}
+
+		res = []byte(strings.Join(entries, "\n"))
+	case "apa", "mla":
+		entries := []string{}
+
+		for _, book := range books {
+			if format == "apa" {
+				entries = append(entries, APA(book))
+			} else {
+				entries = append(entries, MLA(book))
+			}
+		}
+
+		sort.Slice(entries, func(i, j int) bool {
+			return strings.ToLower(entries[i]) < strings.ToLower(entries[j])
+		})
+
+		res = []byte(strings.Join(entries, "\n") + "\n")
+	}
+
+	return
+}
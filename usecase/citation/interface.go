@@ -0,0 +1,11 @@
+package citation
+
+import (
+	"net/url"
+)
+
+type Citation interface {
+	CiteBook(bookId uint, query url.Values) (res []byte, contentType string, code int, message string)
+	CiteReadingList(viewerId uint, listId uint, query url.Values) (res []byte, contentType string, code int, message string)
+	CiteFavorites(userId uint, query url.Values) (res []byte, contentType string, code int, message string)
+}
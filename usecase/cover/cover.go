@@ -0,0 +1,292 @@
+package cover
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"log"
+	"net/http"
+	"os"
+	_blobRepository "plain-go/public-library/datastore/blob"
+	_bookRepository "plain-go/public-library/datastore/book"
+	_entity "plain-go/public-library/entity"
+	_helper "plain-go/public-library/helper"
+	_model "plain-go/public-library/model"
+	"time"
+)
+
+type CoverUseCase struct {
+	bookRepo _bookRepository.Book
+	blob     _blobRepository.Blob
+	maxSize  uint
+}
+
+func New(book _bookRepository.Book, blob _blobRepository.Blob, maxSize uint) *CoverUseCase {
+	return &CoverUseCase{bookRepo: book, blob: blob, maxSize: maxSize}
+}
+
+// thumbnail width in pixels by size name, original is kept as uploaded
+var Sizes = map[string]int{
+	"small":  96,
+	"medium": 240,
+	"large":  600,
+}
+
+var extensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+}
+
+// bound on pixel count so that a tiny file can not decode into a huge image
+const (
+	minDimension = 50
+	maxDimension = 8000
+)
+
+// blobs are found by prefix stored with the cover rather than by book id, cover keeps its blobs when books are merged
+func coverKey(prefix string, size string, contentType string) string {
+	return fmt.Sprintf("%s/%s.%s", prefix, size, extensions[contentType])
+}
+
+// SetURLs points to every size of the cover, version query busts cache on replacement
+func SetURLs(cover *_entity.BookCover) {
+	cover.URLs = map[string]string{"original": fmt.Sprintf("/books/%d/cover?v=%s", cover.BookId, cover.ETag)}
+
+	for size := range Sizes {
+		cover.URLs[size] = fmt.Sprintf("/books/%d/cover?size=%s&v=%s", cover.BookId, size, cover.ETag)
+	}
+}
+
+func (cuc CoverUseCase) UploadCover(bookId uint, data []byte) (res _model.UploadCoverResponse, code int, message string) {
+	// check book existence
+	book, err := cuc.bookRepo.GetBookById(bookId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if book.Title == "" {
+		log.Println("book not found")
+		code, message = http.StatusNotFound, "book not found"
+		return
+	}
+
+	if len(data) == 0 {
+		log.Println("empty input")
+		code, message = http.StatusBadRequest, "empty input"
+		return
+	}
+
+	if uint(len(data)) > cuc.maxSize {
+		log.Println("file too large")
+		code, message = http.StatusRequestEntityTooLarge, "file too large"
+		return
+	}
+
+	// type is decided by content, not by what client claims
+	contentType := http.DetectContentType(data)
+
+	if _, exist := extensions[contentType]; !exist {
+		log.Println("unsupported image type")
+		code, message = http.StatusUnsupportedMediaType, "unsupported image type"
+		return
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+
+	if err != nil {
+		log.Println(err)
+		code, message = http.StatusBadRequest, "invalid image"
+		return
+	}
+
+	if config.Width < minDimension || config.Height < minDimension || config.Width > maxDimension || config.Height > maxDimension {
+		log.Println("invalid image dimension")
+		code, message = http.StatusBadRequest, fmt.Sprintf("image must be from %d to %d pixels on each side", minDimension, maxDimension)
+		return
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+
+	if err != nil {
+		log.Println(err)
+		code, message = http.StatusBadRequest, "invalid image"
+		return
+	}
+
+	previous, err := cuc.bookRepo.GetCover(bookId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// every version of the cover has its own blobs, previous cover stays readable until the new one is saved
+	checksum := sha256.Sum256(data)
+	etag := hex.EncodeToString(checksum[:8])
+	prefix := fmt.Sprintf("covers/%d/%s", bookId, etag)
+
+	// thumbnails keep the type of original
+	for size, width := range Sizes {
+		buffer := bytes.Buffer{}
+		thumbnail := resize(img, width)
+
+		if contentType == "image/png" {
+			err = png.Encode(&buffer, thumbnail)
+		} else {
+			err = jpeg.Encode(&buffer, thumbnail, &jpeg.Options{Quality: 85})
+		}
+
+		if err != nil {
+			log.Println(err)
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		if err = cuc.blob.Put(coverKey(prefix, size, contentType), buffer.Bytes()); err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+	}
+
+	if err = cuc.blob.Put(coverKey(prefix, "original", contentType), data); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// prepare input to repository
+	now := time.Now()
+	newCover := _entity.BookCover{
+		BookId:      bookId,
+		ContentType: contentType,
+		Size:        uint(len(data)),
+		Width:       uint(config.Width),
+		Height:      uint(config.Height),
+		ETag:        etag,
+		BlobPrefix:  prefix,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	// calling repository
+	res.Cover, err = cuc.bookRepo.SaveCover(newCover)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// previous blobs are only garbage now, failing to remove them does not fail the upload
+	if previous.BookId != 0 && previous.BlobPrefix != prefix {
+		if err = cuc.removeBlobs(previous); err != nil {
+			log.Println("previous cover left behind at", previous.BlobPrefix)
+		}
+	}
+
+	SetURLs(&res.Cover)
+
+	// formatting response
+	res.Cover.CreatedAt, _ = _helper.TimeFormatter(res.Cover.CreatedAt)
+	res.Cover.UpdatedAt, _ = _helper.TimeFormatter(res.Cover.UpdatedAt)
+
+	if previous.BookId == 0 {
+		code, message = http.StatusCreated, "success upload cover"
+	} else {
+		code, message = http.StatusOK, "success replace cover"
+	}
+
+	return
+}
+
+func (cuc CoverUseCase) GetCover(bookId uint, size string) (data []byte, cover _entity.BookCover, code int, message string) {
+	if size == "" {
+		size = "original"
+	} else if _, exist := Sizes[size]; !exist {
+		log.Println("invalid size")
+		code, message = http.StatusBadRequest, "invalid size"
+		return
+	}
+
+	// calling repository
+	cover, err := cuc.bookRepo.GetCover(bookId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if cover.BookId == 0 {
+		log.Println("cover not found")
+		code, message = http.StatusNotFound, "cover not found"
+		return
+	}
+
+	data, err = cuc.blob.Get(coverKey(cover.BlobPrefix, size, cover.ContentType))
+
+	if errors.Is(err, os.ErrNotExist) {
+		log.Println("cover not found")
+		code, message = http.StatusNotFound, "cover not found"
+		return
+	}
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	code, message = http.StatusOK, "success get cover"
+	return
+}
+
+func (cuc CoverUseCase) DeleteCover(bookId uint) (code int, message string) {
+	// calling repository
+	cover, err := cuc.bookRepo.GetCover(bookId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if cover.BookId == 0 {
+		log.Println("cover not found")
+		code, message = http.StatusNotFound, "cover not found"
+		return
+	}
+
+	if err = cuc.bookRepo.DeleteCover(bookId); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if err = cuc.removeBlobs(cover); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	code, message = http.StatusOK, "success delete cover"
+	return
+}
+
+func (cuc CoverUseCase) removeBlobs(cover _entity.BookCover) (err error) {
+	if err = cuc.blob.Delete(coverKey(cover.BlobPrefix, "original", cover.ContentType)); err != nil {
+		return
+	}
+
+	for size := range Sizes {
+		if err = cuc.blob.Delete(coverKey(cover.BlobPrefix, size, cover.ContentType)); err != nil {
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,12 @@
+package cover
+
+import (
+	_entity "plain-go/public-library/entity"
+	_model "plain-go/public-library/model"
+)
+
+type Cover interface {
+	UploadCover(bookId uint, data []byte) (res _model.UploadCoverResponse, code int, message string)
+	GetCover(bookId uint, size string) (data []byte, cover _entity.BookCover, code int, message string)
+	DeleteCover(bookId uint) (code int, message string)
+}
@@ -0,0 +1,65 @@
+package cover
+
+import (
+	"image"
+	"image/draw"
+)
+
+// resize scales image down to width keeping aspect ratio, every target pixel averages the source pixels it covers
+func resize(src image.Image, width int) image.Image {
+	bounds := src.Bounds()
+
+	// never scale up
+	if bounds.Dx() <= width {
+		return src
+	}
+
+	height := bounds.Dy() * width / bounds.Dx()
+
+	if height < 1 {
+		height = 1
+	}
+
+	// pixel buffer is read directly instead of through At for speed
+	source := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(source, source.Bounds(), src, bounds.Min, draw.Src)
+
+	target := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0, y1 := y*bounds.Dy()/height, (y+1)*bounds.Dy()/height
+
+		if y1 == y0 {
+			y1 = y0 + 1
+		}
+
+		for x := 0; x < width; x++ {
+			x0, x1 := x*bounds.Dx()/width, (x+1)*bounds.Dx()/width
+
+			if x1 == x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, a, count uint64
+
+			for sy := y0; sy < y1; sy++ {
+				offset := source.PixOffset(x0, sy)
+
+				for sx := x0; sx < x1; sx++ {
+					pixel := source.Pix[offset : offset+4]
+					r, g, b, a = r+uint64(pixel[0]), g+uint64(pixel[1]), b+uint64(pixel[2]), a+uint64(pixel[3])
+					count++
+					offset += 4
+				}
+			}
+
+			offset := target.PixOffset(x, y)
+			target.Pix[offset] = uint8(r / count)
+			target.Pix[offset+1] = uint8(g / count)
+			target.Pix[offset+2] = uint8(b / count)
+			target.Pix[offset+3] = uint8(a / count)
+		}
+	}
+
+	return target
+}
@@ -0,0 +1,160 @@
+package duplicate
+
+import (
+	"math"
+	_acquisitionRepository "plain-go/public-library/datastore/acquisition"
+	_bookRepository "plain-go/public-library/datastore/book"
+	_entity "plain-go/public-library/entity"
+	_helper "plain-go/public-library/helper"
+	"sort"
+	"strings"
+)
+
+// minimum score for two normalized strings to be reported as near-duplicate
+const threshold = 0.8
+
+type DuplicateDetector struct {
+	bookRepo        _bookRepository.Book
+	acquisitionRepo _acquisitionRepository.Acquisition
+}
+
+func New(book _bookRepository.Book, acquisition _acquisitionRepository.Acquisition) *DuplicateDetector {
+	return &DuplicateDetector{bookRepo: book, acquisitionRepo: acquisition}
+}
+
+// score compares normalized strings, with and without spaces,
+// so "jk rowling" is still close to "j k rowling"
+func score(a string, b string) float64 {
+	return math.Max(_helper.Similarity(a, b), _helper.Similarity(strings.ReplaceAll(a, " ", ""), strings.ReplaceAll(b, " ", "")))
+}
+
+// round keeps response readable
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+func sortByScore(duplicates []_entity.PossibleDuplicate) {
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		return duplicates[i].Score > duplicates[j].Score
+	})
+}
+
+// FindBooks returns book with the same normalized title and authors as exact,
+// and every other book with similar title as near-duplicate
+func (dd DuplicateDetector) FindBooks(title string, authors []string) (exact _entity.Book, similar []_entity.PossibleDuplicate, err error) {
+	normalizedTitle := _helper.NormalizeTitle(title)
+	normalizedAuthors := _helper.NormalizeAuthors(authors)
+
+	// calling repository, only books which may reach the threshold are scored
+	books, err := dd.bookRepo.GetBooksByTitleKey(_helper.KeyRange(_helper.MatchKey(normalizedTitle), threshold))
+
+	if err != nil {
+		return
+	}
+
+	for _, book := range books {
+		bookTitle := _helper.NormalizeTitle(book.Title)
+		s := score(normalizedTitle, bookTitle)
+
+		if s < threshold {
+			continue
+		}
+
+		// same title, authors decide whether it is the same book
+		if bookTitle == normalizedTitle && exact.Id == 0 {
+			bookAuthors, err := dd.bookRepo.GetBookAuthors(book.Id)
+
+			if err != nil {
+				return exact, similar, err
+			}
+
+			names := []string{}
+
+			for _, author := range bookAuthors {
+				names = append(names, author.Name)
+			}
+
+			if _helper.NormalizeAuthors(names) == normalizedAuthors {
+				exact = book
+				exact.Author = bookAuthors
+				continue
+			}
+		}
+
+		similar = append(similar, _entity.PossibleDuplicate{Type: "book", Id: book.Id, Name: book.Title, Score: round(s)})
+	}
+
+	sortByScore(similar)
+
+	return
+}
+
+// FindSuggestions returns open acquisition candidate with similar title,
+// member may upvote it instead of wishing for the same book
+func (dd DuplicateDetector) FindSuggestions(title string) (similar []_entity.PossibleDuplicate, err error) {
+	normalizedTitle := _helper.NormalizeTitle(title)
+
+	// calling repository, only open candidates which may reach the threshold are scored
+	candidates, err := dd.acquisitionRepo.GetOpenCandidatesByTitleKey(_helper.KeyRange(_helper.MatchKey(normalizedTitle), threshold))
+
+	if err != nil {
+		return
+	}
+
+	for _, candidate := range candidates {
+		if s := score(normalizedTitle, _helper.NormalizeTitle(candidate.Title)); s >= threshold {
+			similar = append(similar, _entity.PossibleDuplicate{Type: "suggestion", Id: candidate.Id, Name: candidate.Title, Score: round(s)})
+		}
+	}
+
+	sortByScore(similar)
+
+	return
+}
+
+// ResolveAuthor returns existing author with the same normalized name,
+// otherwise creates new author and reports authors with similar name
+func (dd DuplicateDetector) ResolveAuthor(name string) (author _entity.Author, similar []_entity.PossibleDuplicate, err error) {
+	normalizedName := _helper.NormalizeName(name)
+
+	// calling repository, only authors who may reach the threshold are scored
+	authors, err := dd.bookRepo.GetAuthorsByNameKey(_helper.KeyRange(_helper.MatchKey(normalizedName), threshold))
+
+	if err != nil {
+		return
+	}
+
+	for _, existing := range authors {
+		existingName := _helper.NormalizeName(existing.Name)
+		s := score(normalizedName, existingName)
+
+		if s < threshold {
+			continue
+		}
+
+		if existingName == normalizedName {
+			author = existing
+			similar = nil
+			return
+		}
+
+		similar = append(similar, _entity.PossibleDuplicate{Type: "author", Id: existing.Id, Name: existing.Name, Score: round(s)})
+	}
+
+	// if author does not exist, create new
+	author.Name = name
+
+	// calling repository
+	if author, err = dd.bookRepo.CreateNewAuthor(author); err != nil {
+		return
+	}
+
+	sortByScore(similar)
+
+	return
+}
+
+// BackfillKeys stores match keys of records created before the keys existed, so they can be shortlisted
+func (dd DuplicateDetector) BackfillKeys() (err error) {
+	return dd.bookRepo.BackfillMatchKeys()
+}
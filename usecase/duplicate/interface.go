@@ -0,0 +1,11 @@
+package duplicate
+
+import (
+	_entity "plain-go/public-library/entity"
+)
+
+type Detector interface {
+	FindBooks(title string, authors []string) (exact _entity.Book, similar []_entity.PossibleDuplicate, err error)
+	FindSuggestions(title string) (similar []_entity.PossibleDuplicate, err error)
+	ResolveAuthor(name string) (author _entity.Author, similar []_entity.PossibleDuplicate, err error)
+}
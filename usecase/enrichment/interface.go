@@ -0,0 +1,10 @@
+package enrichment
+
+import (
+	_model "plain-go/public-library/model"
+)
+
+// provider fills book metadata from an outside bibliographic source, found is false for unknown ISBN
+type Provider interface {
+	LookupISBN(isbn13 string) (metadata _model.CreateBookRequest, found bool, err error)
+}
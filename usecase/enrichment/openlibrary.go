@@ -0,0 +1,319 @@
+package enrichment
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	_openLibraryRepository "plain-go/public-library/datastore/openlibrary"
+	_entity "plain-go/public-library/entity"
+	_helper "plain-go/public-library/helper"
+	_model "plain-go/public-library/model"
+	"strings"
+)
+
+type keyRef struct {
+	Key string `json:"key"`
+}
+
+type olEdition struct {
+	Title         string          `json:"title"`
+	Subtitle      string          `json:"subtitle"`
+	Publishers    []string        `json:"publishers"`
+	NumberOfPages uint            `json:"number_of_pages"`
+	ISBN10        []string        `json:"isbn_10"`
+	ISBN13        []string        `json:"isbn_13"`
+	Languages     []keyRef        `json:"languages"`
+	Authors       []keyRef        `json:"authors"`
+	Works         []keyRef        `json:"works"`
+	Description   json.RawMessage `json:"description"`
+	Subjects      []string        `json:"subjects"`
+}
+
+type olWork struct {
+	Description json.RawMessage `json:"description"`
+	Subjects    []string        `json:"subjects"`
+	Authors     []struct {
+		Author keyRef `json:"author"`
+	} `json:"authors"`
+}
+
+type olAuthor struct {
+	Name string `json:"name"`
+}
+
+// number of records written to database at once while indexing
+const indexBatch = 1000
+
+// open library provider answers from dump files (https://openlibrary.org/developers/dumps) indexed into database
+// by IndexDumps beforehand, so lookup never holds the dumps in memory
+type OpenLibraryProvider struct {
+	repository _openLibraryRepository.OpenLibrary
+}
+
+func NewOpenLibraryProvider(repository _openLibraryRepository.OpenLibrary) *OpenLibraryProvider {
+	return &OpenLibraryProvider{repository: repository}
+}
+
+// IndexDumps streams editions dump into database, works and authors dump are optional, file may be gzipped,
+// only editions carrying valid ISBN are kept
+func (olp OpenLibraryProvider) IndexDumps(editionsFile string, worksFile string, authorsFile string) (err error) {
+	editions := []_entity.OpenLibraryEdition{}
+
+	err = readDump(editionsFile, "/type/edition", func(key string, data []byte) (err error) {
+		raw := olEdition{}
+
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil
+		}
+
+		e := _entity.OpenLibraryEdition{Title: raw.Title, Pages: raw.NumberOfPages, Description: textValue(raw.Description)}
+
+		if raw.Subtitle != "" {
+			e.Title += ": " + raw.Subtitle
+		}
+
+		if len(raw.Publishers) > 0 {
+			e.Publisher = raw.Publishers[0]
+		}
+
+		if len(raw.Languages) > 0 {
+			e.Language = strings.TrimPrefix(raw.Languages[0].Key, "/languages/")
+		}
+
+		if len(raw.Subjects) > 0 {
+			e.Subject = raw.Subjects[0]
+		}
+
+		for _, author := range raw.Authors {
+			e.AuthorKeys = append(e.AuthorKeys, author.Key)
+		}
+
+		if len(raw.Works) > 0 {
+			e.WorkKey = raw.Works[0].Key
+		}
+
+		// edition without title is of no use
+		if e.Title == "" {
+			return nil
+		}
+
+		// both forms are indexed under ISBN-13
+		isbns := map[string]interface{}{}
+
+		for _, isbn := range append(raw.ISBN13, raw.ISBN10...) {
+			if isbn13, err := _helper.ParseISBN(isbn); err == nil {
+				isbns[isbn13] = nil
+			}
+		}
+
+		for isbn13 := range isbns {
+			e.ISBN13 = isbn13
+			editions = append(editions, e)
+		}
+
+		if len(editions) < indexBatch {
+			return nil
+		}
+
+		err = olp.repository.SaveEditions(editions)
+		editions = editions[:0]
+		return
+	})
+
+	if err != nil {
+		return
+	}
+
+	if err = olp.repository.SaveEditions(editions); err != nil {
+		return
+	}
+
+	if worksFile != "" {
+		works := []_entity.OpenLibraryWork{}
+
+		err = readDump(worksFile, "/type/work", func(key string, data []byte) (err error) {
+			raw := olWork{}
+
+			if err := json.Unmarshal(data, &raw); err != nil {
+				return nil
+			}
+
+			w := _entity.OpenLibraryWork{Key: key, Description: textValue(raw.Description)}
+
+			if len(raw.Subjects) > 0 {
+				w.Subject = raw.Subjects[0]
+			}
+
+			for _, author := range raw.Authors {
+				w.AuthorKeys = append(w.AuthorKeys, author.Author.Key)
+			}
+
+			if works = append(works, w); len(works) < indexBatch {
+				return nil
+			}
+
+			err = olp.repository.SaveWorks(works)
+			works = works[:0]
+			return
+		})
+
+		if err != nil {
+			return
+		}
+
+		if err = olp.repository.SaveWorks(works); err != nil {
+			return
+		}
+	}
+
+	if authorsFile != "" {
+		authors := []_entity.OpenLibraryAuthor{}
+
+		err = readDump(authorsFile, "/type/author", func(key string, data []byte) (err error) {
+			raw := olAuthor{}
+
+			if err := json.Unmarshal(data, &raw); err != nil || raw.Name == "" {
+				return nil
+			}
+
+			if authors = append(authors, _entity.OpenLibraryAuthor{Key: key, Name: raw.Name}); len(authors) < indexBatch {
+				return nil
+			}
+
+			err = olp.repository.SaveAuthors(authors)
+			authors = authors[:0]
+			return
+		})
+
+		if err != nil {
+			return
+		}
+
+		err = olp.repository.SaveAuthors(authors)
+	}
+
+	return
+}
+
+// readDump walks tab separated dump of type, key, revision, last modified and JSON record, error of handle stops the walk
+func readDump(path string, recordType string, handle func(key string, data []byte) error) (err error) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer file.Close()
+
+	var reader io.Reader = file
+
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+
+		if err != nil {
+			log.Println(err)
+			return err
+		}
+
+		defer gz.Close()
+		reader = gz
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		columns := strings.SplitN(scanner.Text(), "\t", 5)
+
+		if len(columns) != 5 || columns[0] != recordType {
+			continue
+		}
+
+		if err = handle(columns[1], []byte(columns[4])); err != nil {
+			return
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		log.Println(err)
+	}
+
+	return
+}
+
+// textValue reads text which is either plain string or typed value object
+func textValue(raw json.RawMessage) string {
+	text := ""
+
+	if len(raw) == 0 || json.Unmarshal(raw, &text) == nil {
+		return strings.TrimSpace(text)
+	}
+
+	typed := struct {
+		Value string `json:"value"`
+	}{}
+
+	json.Unmarshal(raw, &typed)
+	return strings.TrimSpace(typed.Value)
+}
+
+func (olp OpenLibraryProvider) LookupISBN(isbn13 string) (metadata _model.CreateBookRequest, found bool, err error) {
+	e, err := olp.repository.GetEditionByISBN(isbn13)
+
+	if err != nil || e.ISBN13 == "" {
+		return
+	}
+
+	// edition is preferred, work fills what edition lacks
+	w := _entity.OpenLibraryWork{}
+
+	if e.WorkKey != "" {
+		if w, err = olp.repository.GetWorkByKey(e.WorkKey); err != nil {
+			return
+		}
+	}
+
+	authorKeys, description, subject := e.AuthorKeys, e.Description, e.Subject
+
+	if len(authorKeys) == 0 {
+		authorKeys = w.AuthorKeys
+	}
+
+	if description == "" {
+		description = w.Description
+	}
+
+	if subject == "" {
+		subject = w.Subject
+	}
+
+	names, err := olp.repository.GetAuthorNames(authorKeys)
+
+	if err != nil {
+		return
+	}
+
+	found = true
+	metadata.ISBN13 = isbn13
+	metadata.Title = e.Title
+	metadata.Publisher = e.Publisher
+	metadata.Pages = e.Pages
+	metadata.Description = description
+	metadata.Category = subject
+
+	if e.Language != "" {
+		metadata.Language = _helper.LanguageName(e.Language)
+	}
+
+	for _, key := range authorKeys {
+		if name, exist := names[key]; exist {
+			metadata.Author = append(metadata.Author, _model.CreateAuthorRequest{Name: name})
+		}
+	}
+
+	return
+}
@@ -0,0 +1,30 @@
+package enrichment
+
+import (
+	_helper "plain-go/public-library/helper"
+	_model "plain-go/public-library/model"
+)
+
+// stub provider answers from fixed records, it serves tests and the marcimport command,
+// the server looks metadata up in the Open Library dump indexed by olimport
+type StubProvider struct {
+	records map[string]_model.CreateBookRequest
+}
+
+func NewStubProvider(records ..._model.CreateBookRequest) *StubProvider {
+	sp := &StubProvider{records: map[string]_model.CreateBookRequest{}}
+
+	for _, record := range records {
+		if isbn13, err := _helper.ParseISBN(record.ISBN13); err == nil {
+			record.ISBN13 = isbn13
+			sp.records[isbn13] = record
+		}
+	}
+
+	return sp
+}
+
+func (sp StubProvider) LookupISBN(isbn13 string) (metadata _model.CreateBookRequest, found bool, err error) {
+	metadata, found = sp.records[isbn13]
+	return
+}
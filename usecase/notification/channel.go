@@ -0,0 +1,149 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/smtp"
+	_notificationRepository "plain-go/public-library/datastore/notification"
+	_entity "plain-go/public-library/entity"
+	"time"
+)
+
+type Channel interface {
+	Name() string
+	Send(user _entity.User, notification _entity.Notification) (err error)
+}
+
+// email channel, delivered through plain SMTP
+type EmailChannel struct {
+	host     string
+	port     int
+	username string
+	password string
+	sender   string
+}
+
+func NewEmailChannel(host string, port int, username string, password string, sender string) *EmailChannel {
+	return &EmailChannel{host: host, port: port, username: username, password: password, sender: sender}
+}
+
+func (ec EmailChannel) Name() string {
+	return "email"
+}
+
+func (ec EmailChannel) Send(user _entity.User, notification _entity.Notification) (err error) {
+	if user.Email == "" {
+		return
+	}
+
+	addr := fmt.Sprintf("%s:%d", ec.host, ec.port)
+
+	var auth smtp.Auth
+
+	if ec.username != "" {
+		auth = smtp.PlainAuth("", ec.username, ec.password, ec.host)
+	}
+
+	msg := fmt.Sprintf(
+		"From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		ec.sender, user.Email, notification.Subject, notification.Content,
+	)
+
+	if err = smtp.SendMail(addr, auth, ec.sender, []string{user.Email}, []byte(msg)); err != nil {
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+// sms channel, delivered through any gateway implementing SMSGateway
+type SMSGateway interface {
+	SendSMS(phone string, text string) (err error)
+}
+
+type SMSChannel struct {
+	gateway SMSGateway
+}
+
+func NewSMSChannel(gateway SMSGateway) *SMSChannel {
+	return &SMSChannel{gateway: gateway}
+}
+
+func (sc SMSChannel) Name() string {
+	return "sms"
+}
+
+func (sc SMSChannel) Send(user _entity.User, notification _entity.Notification) (err error) {
+	if user.Phone == "" {
+		return
+	}
+
+	return sc.gateway.SendSMS(user.Phone, notification.Subject+": "+notification.Content)
+}
+
+// generic HTTP gateway posting JSON payload {"to": ..., "text": ...}
+type HTTPSMSGateway struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+func NewHTTPSMSGateway(url string, apiKey string) *HTTPSMSGateway {
+	return &HTTPSMSGateway{url: url, apiKey: apiKey, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (hg HTTPSMSGateway) SendSMS(phone string, text string) (err error) {
+	payload, _ := json.Marshal(map[string]string{"to": phone, "text": text})
+
+	req, err := http.NewRequest(http.MethodPost, hg.url, bytes.NewReader(payload))
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+hg.apiKey)
+
+	res, err := hg.client.Do(req)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		err = errors.New("sms gateway responded with " + res.Status)
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+// in-app channel, stored as notification record of the user
+type InboxChannel struct {
+	repository _notificationRepository.Notification
+}
+
+func NewInboxChannel(notification _notificationRepository.Notification) *InboxChannel {
+	return &InboxChannel{repository: notification}
+}
+
+func (ic InboxChannel) Name() string {
+	return "in_app"
+}
+
+func (ic InboxChannel) Send(user _entity.User, notification _entity.Notification) (err error) {
+	notification.User = user
+	_, err = ic.repository.CreateNotification(notification)
+
+	return
+}
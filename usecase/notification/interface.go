@@ -0,0 +1,22 @@
+package notification
+
+import (
+	"net/url"
+	_entity "plain-go/public-library/entity"
+	_model "plain-go/public-library/model"
+	"time"
+)
+
+type Notification interface {
+	GetPreference(userId uint) (res _model.GetNotificationPreferenceResponse, code int, message string)
+	UpdatePreference(userId uint, req _model.UpdateNotificationPreferenceRequest) (res _model.UpdateNotificationPreferenceResponse, code int, message string)
+	GetAllNotifications(userId uint, query url.Values) (res _model.GetAllNotificationsResponse, code int, message string)
+	MarkNotificationsAsRead(userId uint, req _model.UpdateNotificationsRequest) (code int, message string)
+	DismissNotifications(userId uint, req _model.UpdateNotificationsRequest) (code int, message string)
+}
+
+type Notifier interface {
+	Notify(newNotification _entity.Notification) (err error)
+	SendDueReminders(now time.Time)
+	HandleEvent(event _entity.Event) (err error)
+}
@@ -0,0 +1,582 @@
+package notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	_config "plain-go/public-library/app/config"
+	_acquisitionRepository "plain-go/public-library/datastore/acquisition"
+	_bookRepository "plain-go/public-library/datastore/book"
+	_notificationRepository "plain-go/public-library/datastore/notification"
+	_requestRepository "plain-go/public-library/datastore/request"
+	_userRepository "plain-go/public-library/datastore/user"
+	_entity "plain-go/public-library/entity"
+	_helper "plain-go/public-library/helper"
+	_model "plain-go/public-library/model"
+	"strings"
+	"time"
+)
+
+type NotificationUseCase struct {
+	notificationRepo _notificationRepository.Notification
+	userRepo         _userRepository.User
+	bookRepo         _bookRepository.Book
+	requestRepo      _requestRepository.Request
+	acquisitionRepo  _acquisitionRepository.Acquisition
+	channels         []Channel
+}
+
+func New(notification _notificationRepository.Notification, user _userRepository.User, book _bookRepository.Book, request _requestRepository.Request, acquisition _acquisitionRepository.Acquisition, channels ...Channel) *NotificationUseCase {
+	return &NotificationUseCase{notificationRepo: notification, userRepo: user, bookRepo: book, requestRepo: request, acquisitionRepo: acquisition, channels: channels}
+}
+
+func (nuc NotificationUseCase) getPreference(userId uint) (preference _entity.NotificationPreference, err error) {
+	// calling repository
+	preference, err = nuc.notificationRepo.GetPreferenceByUserId(userId)
+
+	if err != nil {
+		return
+	}
+
+	// user without stored preference gets default preference
+	if preference.UserId == 0 {
+		config, err := _config.GetConfig()
+
+		if err != nil {
+			return preference, err
+		}
+
+		preference.UserId = userId
+		preference.Email = true
+		preference.InApp = true
+		preference.DaysBeforeDue = config.Reminder.DaysBeforeDue
+	}
+
+	return
+}
+
+func (nuc NotificationUseCase) GetPreference(userId uint) (res _model.GetNotificationPreferenceResponse, code int, message string) {
+	// check user existence
+	user, err := nuc.userRepo.GetUserById(userId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if user.Name == "" {
+		log.Println("user not found")
+		code, message = http.StatusNotFound, "user not found"
+		return
+	}
+
+	// calling repository
+	res.Preference, err = nuc.getPreference(userId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// formatting response
+	res.Preference.UpdatedAt, _ = _helper.TimeFormatter(res.Preference.UpdatedAt)
+	code, message = http.StatusOK, "success get notification preference"
+
+	return
+}
+
+func (nuc NotificationUseCase) UpdatePreference(userId uint, req _model.UpdateNotificationPreferenceRequest) (res _model.UpdateNotificationPreferenceResponse, code int, message string) {
+	// check user existence
+	user, err := nuc.userRepo.GetUserById(userId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if user.Name == "" {
+		log.Println("user not found")
+		code, message = http.StatusNotFound, "user not found"
+		return
+	}
+
+	// get current preference
+	preference, err := nuc.getPreference(userId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	flag := true
+
+	if req.Email != nil && *req.Email != preference.Email {
+		preference.Email = *req.Email
+		flag = false
+	}
+
+	if req.SMS != nil && *req.SMS != preference.SMS {
+		preference.SMS = *req.SMS
+		flag = false
+	}
+
+	if req.InApp != nil && *req.InApp != preference.InApp {
+		preference.InApp = *req.InApp
+		flag = false
+	}
+
+	if req.DaysBeforeDue != nil {
+		if *req.DaysBeforeDue < _config.MinDaysBeforeDue || *req.DaysBeforeDue > _config.MaxDaysBeforeDue {
+			log.Println("days before due out of range")
+			code, message = http.StatusBadRequest, "days before due must be from 1 to 6"
+			return
+		}
+
+		if *req.DaysBeforeDue != preference.DaysBeforeDue {
+			preference.DaysBeforeDue = *req.DaysBeforeDue
+			flag = false
+		}
+	}
+
+	// check if no field is updated
+	if flag {
+		log.Println("no update was performed")
+		code, message = http.StatusBadRequest, "no update was performed"
+		return
+	}
+
+	// calling repository
+	preference.UpdatedAt = time.Now()
+	res.Preference, err = nuc.notificationRepo.UpsertPreference(preference)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// formatting response
+	res.Preference.UpdatedAt, _ = _helper.TimeFormatter(res.Preference.UpdatedAt)
+	code, message = http.StatusOK, "success update notification preference"
+
+	return
+}
+
+func (nuc NotificationUseCase) GetAllNotifications(userId uint, query url.Values) (res _model.GetAllNotificationsResponse, code int, message string) {
+	// check user existence
+	user, err := nuc.userRepo.GetUserById(userId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if user.Name == "" {
+		log.Println("user not found")
+		code, message = http.StatusNotFound, "user not found"
+		return
+	}
+
+	unreadOnly := query.Get("unread") == "true"
+
+	// calling repository
+	notifications, err := nuc.notificationRepo.GetAllNotificationsByUserId(userId, unreadOnly)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	res.UnreadCount, err = nuc.notificationRepo.CountUnreadNotificationsByUserId(userId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// formatting response
+	for _, notification := range notifications {
+		notification.CreatedAt, _ = _helper.TimeFormatter(notification.CreatedAt)
+
+		if notification.ReadAt != nil {
+			notification.ReadAt, _ = _helper.TimeFormatter(notification.ReadAt)
+		}
+
+		res.Notifications = append(res.Notifications, notification)
+	}
+
+	code, message = http.StatusOK, "success get all notifications"
+
+	return
+}
+
+func (nuc NotificationUseCase) MarkNotificationsAsRead(userId uint, req _model.UpdateNotificationsRequest) (code int, message string) {
+	// check user existence
+	user, err := nuc.userRepo.GetUserById(userId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if user.Name == "" {
+		log.Println("user not found")
+		code, message = http.StatusNotFound, "user not found"
+		return
+	}
+
+	// calling repository, empty ids marks every notification as read
+	if err = nuc.notificationRepo.MarkNotificationsAsRead(userId, req.NotificationIds); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	code, message = http.StatusOK, "success mark notifications as read"
+
+	return
+}
+
+func (nuc NotificationUseCase) DismissNotifications(userId uint, req _model.UpdateNotificationsRequest) (code int, message string) {
+	// check if required input is empty
+	if len(req.NotificationIds) == 0 {
+		log.Println("empty input")
+		code, message = http.StatusBadRequest, "empty input"
+		return
+	}
+
+	// check user existence
+	user, err := nuc.userRepo.GetUserById(userId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if user.Name == "" {
+		log.Println("user not found")
+		code, message = http.StatusNotFound, "user not found"
+		return
+	}
+
+	// calling repository
+	if err = nuc.notificationRepo.DismissNotifications(userId, req.NotificationIds); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	code, message = http.StatusOK, "success dismiss notifications"
+
+	return
+}
+
+func (nuc NotificationUseCase) Notify(newNotification _entity.Notification) (err error) {
+	// get recipient
+	user, err := nuc.userRepo.GetUserById(newNotification.User.Id)
+
+	if err != nil {
+		return
+	}
+
+	// omit deleted user
+	if user.Name == "" {
+		return
+	}
+
+	// skip notification which has been sent before
+	if newNotification.Reference != "" {
+		sent, err := nuc.notificationRepo.IsNotificationSent(user.Id, newNotification.Reference)
+
+		if err != nil {
+			return err
+		}
+
+		if sent {
+			return nil
+		}
+	}
+
+	preference, err := nuc.getPreference(user.Id)
+
+	if err != nil {
+		return
+	}
+
+	enabled := map[string]bool{"email": preference.Email, "sms": preference.SMS, "in_app": preference.InApp}
+
+	if newNotification.CreatedAt.IsZero() {
+		newNotification.CreatedAt = time.Now()
+	}
+
+	// deliver through every enabled channel, failure in one channel does not stop the others
+	delivered := []string{}
+
+	for _, channel := range nuc.channels {
+		if !enabled[channel.Name()] {
+			continue
+		}
+
+		if err := channel.Send(user, newNotification); err != nil {
+			log.Println(channel.Name(), err)
+			continue
+		}
+
+		delivered = append(delivered, channel.Name())
+	}
+
+	if newNotification.Reference != "" {
+		err = nuc.notificationRepo.CreateNotificationLog(user.Id, newNotification.Reference, strings.Join(delivered, ","))
+	}
+
+	return
+}
+
+func (nuc NotificationUseCase) SendDueReminders(now time.Time) {
+	config, err := _config.GetConfig()
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	// calling repository
+	loans, err := nuc.requestRepo.GetAllActiveLoans()
+
+	if err != nil {
+		return
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	sent, failed := 0, 0
+
+	for _, loan := range loans {
+		finishAt, ok := loan.FinishAt.(time.Time)
+
+		if !ok {
+			continue
+		}
+
+		preference, err := nuc.getPreference(loan.User.Id)
+
+		if err != nil {
+			continue
+		}
+
+		dueDate := time.Date(finishAt.Year(), finishAt.Month(), finishAt.Day(), 0, 0, 0, 0, now.Location())
+		days := int(dueDate.Sub(today).Hours() / 24)
+
+		book, _ := nuc.bookRepo.GetBookByItemId(uint(loan.BookItem.Id))
+
+		notification := _entity.Notification{}
+		notification.User.Id = loan.User.Id
+		notification.CreatedAt = now
+
+		switch {
+		case days > 0 && uint(days) == preference.DaysBeforeDue:
+			notification.Kind = "due_soon"
+			notification.Subject = "Your loan is ending soon"
+			notification.Content = fmt.Sprintf("\"%s\" is due on %s, %d day(s) from now.", book.Title, dueDate.Format("2006-01-02"), days)
+			notification.Reference = fmt.Sprintf("request:%d:due_soon:%s", loan.Id, dueDate.Format("20060102"))
+		case days == 0:
+			notification.Kind = "due_today"
+			notification.Subject = "Your loan is due today"
+			notification.Content = fmt.Sprintf("\"%s\" is due today, please return it to the library.", book.Title)
+			notification.Reference = fmt.Sprintf("request:%d:due_today:%s", loan.Id, dueDate.Format("20060102"))
+		case days < 0 && uint(-days)%config.Reminder.OverdueInterval == 0:
+			notification.Kind = "overdue"
+			notification.Subject = "Your loan is overdue"
+			notification.Content = fmt.Sprintf("\"%s\" was due on %s and is %d day(s) overdue, late return is subject to penalty.", book.Title, dueDate.Format("2006-01-02"), -days)
+			notification.Reference = fmt.Sprintf("request:%d:overdue:%d", loan.Id, -days)
+		default:
+			continue
+		}
+
+		// reminder is not logged as sent, so it is tried again in the next run of the same day
+		if err = nuc.Notify(notification); err != nil {
+			log.Println("failed to send reminder", notification.Reference+":", err)
+			failed++
+			continue
+		}
+
+		sent++
+	}
+
+	if failed > 0 {
+		log.Println(failed, "of", sent+failed, "reminders failed")
+	}
+}
+
+// HandleEvent turns domain events from the outbox into member notifications
+func (nuc NotificationUseCase) HandleEvent(event _entity.Event) (err error) {
+	payload := map[string]interface{}{}
+
+	if raw, err := json.Marshal(event.Payload); err == nil {
+		json.Unmarshal(raw, &payload)
+	}
+
+	switch event.Type {
+	case "request.status_changed":
+		statusId, _ := payload["status_id"].(float64)
+		bookItemId, _ := payload["book_item_id"].(float64)
+		book, err := nuc.bookRepo.GetBookByItemId(uint(bookItemId))
+
+		if err != nil {
+			return err
+		}
+
+		notification := _entity.Notification{}
+		notification.User.Id = event.UserId
+		notification.Kind = "request_status_changed"
+		notification.Subject = "Your request has been updated"
+		notification.Content = fmt.Sprintf("Your request for \"%s\" is now: %s.", book.Title, payload["status"])
+		notification.Reference = fmt.Sprintf("request:%d:status:%d", event.AggregateId, uint(statusId))
+
+		switch uint(statusId) {
+		case 4:
+			notification.Kind = "ready_for_pick_up"
+			notification.Subject = "Your book is ready for pick up"
+			notification.Content = fmt.Sprintf("\"%s\" is ready for pick up at the library desk.", book.Title)
+		case 7:
+			notification.Kind = "fine"
+			notification.Subject = "Late return penalty"
+			notification.Content = "Your borrow period has ended, a penalty must be paid when returning the book."
+		}
+
+		return nuc.Notify(notification)
+	case "acquisition.received", "acquisition.rejected":
+		title, _ := payload["title"].(string)
+		reason, _ := payload["reason"].(string)
+
+		// notify every member wishing for the candidate
+		wishes, err := nuc.acquisitionRepo.GetWishesByCandidateId(event.AggregateId)
+
+		if err != nil {
+			return err
+		}
+
+		for _, wish := range wishes {
+			notification := _entity.Notification{}
+			notification.User.Id = wish.User.Id
+			notification.Kind = "wish_fulfilled"
+			notification.Subject = "Your wish came true"
+			notification.Content = fmt.Sprintf("\"%s\" you wished for is now available in the library.", title)
+			notification.Reference = fmt.Sprintf("wish:%d:fulfilled", wish.Id)
+
+			if event.Type == "acquisition.rejected" {
+				notification.Kind = "wish_rejected"
+				notification.Subject = "Your wish will not be purchased"
+				notification.Content = fmt.Sprintf("Librarian has decided not to purchase \"%s\". Reason: %s", title, reason)
+				notification.Reference = fmt.Sprintf("event:%s", event.Id)
+			}
+
+			if err := nuc.Notify(notification); err != nil {
+				return err
+			}
+		}
+	case "review.flagged", "review.unflagged":
+		bookId, _ := payload["book_id"].(float64)
+		book, err := nuc.bookRepo.GetBookById(uint(bookId))
+
+		if err != nil {
+			return err
+		}
+
+		notification := _entity.Notification{}
+		notification.User.Id = event.UserId
+		notification.Kind = "review_moderated"
+		notification.Subject = "Your review has been moderated"
+		notification.Content = fmt.Sprintf("Your review on \"%s\" has been checked by librarian.", book.Title)
+		notification.Reference = fmt.Sprintf("event:%s", event.Id)
+
+		if event.Type == "review.unflagged" {
+			notification.Content = fmt.Sprintf("Your review on \"%s\" has been reopened for moderation by librarian.", book.Title)
+		}
+
+		return nuc.Notify(notification)
+	case "review.moderated", "reply.moderated":
+		action, _ := payload["action"].(string)
+		note, _ := payload["note"].(string)
+		bookId, _ := payload["book_id"].(float64)
+
+		// approval is silent, reviewer is only told when something happened to the review
+		if action == "approve" {
+			return
+		}
+
+		book, err := nuc.bookRepo.GetBookById(uint(bookId))
+
+		if err != nil {
+			return err
+		}
+
+		target := "review"
+
+		if event.Type == "reply.moderated" {
+			target = "reply"
+		}
+
+		notification := _entity.Notification{}
+		notification.User.Id = event.UserId
+		notification.Kind = target + "_moderated"
+		notification.Reference = fmt.Sprintf("event:%s", event.Id)
+
+		switch action {
+		case "hide":
+			notification.Subject = fmt.Sprintf("Your %s has been hidden", target)
+			notification.Content = fmt.Sprintf("Your %s on \"%s\" has been hidden by librarian.", target, book.Title)
+		case "delete":
+			notification.Subject = fmt.Sprintf("Your %s has been removed", target)
+			notification.Content = fmt.Sprintf("Your %s on \"%s\" has been removed by librarian.", target, book.Title)
+		case "warn":
+			notification.Kind = target + "_warning"
+			notification.Subject = fmt.Sprintf("Warning about your %s", target)
+			notification.Content = fmt.Sprintf("Librarian has warned you about your %s on \"%s\".", target, book.Title)
+		}
+
+		if note != "" {
+			notification.Content += " Note: " + note
+		}
+
+		return nuc.Notify(notification)
+	case "review.replied":
+		reviewerId, _ := payload["reviewer_id"].(float64)
+		bookId, _ := payload["book_id"].(float64)
+		role, _ := payload["role"].(string)
+
+		// no need to tell reviewer about own reply
+		if uint(reviewerId) == event.UserId {
+			return
+		}
+
+		book, err := nuc.bookRepo.GetBookById(uint(bookId))
+
+		if err != nil {
+			return err
+		}
+
+		notification := _entity.Notification{}
+		notification.User.Id = uint(reviewerId)
+		notification.Kind = "review_replied"
+		notification.Subject = "New reply on your review"
+		notification.Content = fmt.Sprintf("Someone replied to your review on \"%s\".", book.Title)
+		notification.Reference = fmt.Sprintf("event:%s", event.Id)
+
+		if role == "Librarian" {
+			notification.Content = fmt.Sprintf("Librarian responded to your review on \"%s\".", book.Title)
+		}
+
+		return nuc.Notify(notification)
+	}
+
+	return
+}
@@ -0,0 +1,9 @@
+package oai
+
+import (
+	"net/url"
+)
+
+type OAI interface {
+	Handle(args url.Values) (res []byte, code int, message string)
+}
@@ -0,0 +1,571 @@
+package oai
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	_bookRepository "plain-go/public-library/datastore/book"
+	_entity "plain-go/public-library/entity"
+	_model "plain-go/public-library/model"
+	_catalog "plain-go/public-library/usecase/catalog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type Repository struct {
+	Name       string
+	Identifier string
+	BaseURL    string
+	AdminEmail string
+}
+
+type OAIUseCase struct {
+	bookRepo   _bookRepository.Book
+	repository Repository
+}
+
+func New(book _bookRepository.Book, repository Repository) *OAIUseCase {
+	return &OAIUseCase{bookRepo: book, repository: repository}
+}
+
+// records returned before resumption token is issued
+const pageSize = 100
+
+const (
+	dayGranularity    = "2006-01-02"
+	secondGranularity = "2006-01-02T15:04:05Z"
+)
+
+var formats = []metadataFormat{
+	{"oai_dc", "http://www.openarchives.org/OAI/2.0/oai_dc.xsd", "http://www.openarchives.org/OAI/2.0/oai_dc/"},
+	{"marc21", "http://www.loc.gov/standards/marcxml/schema/MARC21slim.xsd", "http://www.loc.gov/MARC21/slim"},
+}
+
+// arguments allowed by each verb, true for required one
+var verbs = map[string]map[string]bool{
+	"Identify":            {},
+	"ListMetadataFormats": {"identifier": false},
+	"ListSets":            {"resumptionToken": false},
+	"ListIdentifiers":     {"metadataPrefix": true, "from": false, "until": false, "set": false, "resumptionToken": false},
+	"ListRecords":         {"metadataPrefix": true, "from": false, "until": false, "set": false, "resumptionToken": false},
+	"GetRecord":           {"identifier": true, "metadataPrefix": true},
+}
+
+// harvest is what resumption token carries between requests
+type harvest struct {
+	prefix  string
+	set     string
+	from    time.Time
+	until   time.Time
+	afterId uint
+	cursor  uint
+}
+
+func encodeToken(h harvest) string {
+	parts := []string{h.prefix, h.set, "", "", strconv.Itoa(int(h.afterId)), strconv.Itoa(int(h.cursor))}
+
+	if !h.from.IsZero() {
+		parts[2] = strconv.FormatInt(h.from.Unix(), 10)
+	}
+
+	if !h.until.IsZero() {
+		parts[3] = strconv.FormatInt(h.until.Unix(), 10)
+	}
+
+	return base64.RawURLEncoding.EncodeToString([]byte(strings.Join(parts, "\n")))
+}
+
+func decodeToken(token string) (h harvest, ok bool) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+
+	if err != nil {
+		return
+	}
+
+	parts := strings.Split(string(data), "\n")
+
+	if len(parts) != 6 || !isFormat(parts[0]) {
+		return
+	}
+
+	h.prefix, h.set = parts[0], parts[1]
+
+	for i, t := range []*time.Time{&h.from, &h.until} {
+		if parts[2+i] == "" {
+			continue
+		}
+
+		seconds, err := strconv.ParseInt(parts[2+i], 10, 64)
+
+		if err != nil {
+			return
+		}
+
+		*t = time.Unix(seconds, 0)
+	}
+
+	afterId, err := strconv.Atoi(parts[4])
+
+	if err != nil || afterId < 0 {
+		return
+	}
+
+	cursor, err := strconv.Atoi(parts[5])
+
+	if err != nil || cursor < 0 {
+		return
+	}
+
+	h.afterId, h.cursor, ok = uint(afterId), uint(cursor), true
+	return
+}
+
+func isFormat(prefix string) bool {
+	for _, format := range formats {
+		if format.Prefix == prefix {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setSpec turns category into the limited character set allowed by the protocol
+func setSpec(category string) string {
+	return strings.ToLower(strings.Join(strings.FieldsFunc(category, func(r rune) bool {
+		return r >= unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r))
+	}), "-"))
+}
+
+func (ouc OAIUseCase) identifier(bookId uint) string {
+	return fmt.Sprintf("oai:%s:book/%d", ouc.repository.Identifier, bookId)
+}
+
+// parseIdentifier returns zero for identifier not issued by this repository
+func (ouc OAIUseCase) parseIdentifier(identifier string) uint {
+	id, err := strconv.Atoi(strings.TrimPrefix(identifier, fmt.Sprintf("oai:%s:book/", ouc.repository.Identifier)))
+
+	if err != nil || id <= 0 || identifier != ouc.identifier(uint(id)) {
+		return 0
+	}
+
+	return uint(id)
+}
+
+// parseDatestamp accepts both granularities, until of day granularity covers the whole day
+func parseDatestamp(value string, until bool) (t time.Time, layout string, err error) {
+	layout = dayGranularity
+
+	if len(value) != len(dayGranularity) {
+		layout = secondGranularity
+	}
+
+	if t, err = time.Parse(layout, value); err != nil {
+		return
+	}
+
+	if until && layout == dayGranularity {
+		t = t.AddDate(0, 0, 1)
+	} else if until {
+		t = t.Add(time.Second)
+	}
+
+	return
+}
+
+func (ouc OAIUseCase) Handle(args url.Values) (res []byte, code int, message string) {
+	out := response{
+		XSI:            "http://www.w3.org/2001/XMLSchema-instance",
+		SchemaLocation: "http://www.openarchives.org/OAI/2.0/ http://www.openarchives.org/OAI/2.0/OAI-PMH.xsd",
+		ResponseDate:   time.Now().UTC().Format(secondGranularity),
+		Request:        request{BaseURL: ouc.repository.BaseURL},
+	}
+
+	verb := args.Get("verb")
+	allowed, exist := verbs[verb]
+
+	// request element only echoes arguments of a valid request
+	fail := func(code string, message string) {
+		out.Errors = append(out.Errors, oaiError{Code: code, Message: message})
+	}
+
+	switch {
+	case !exist || len(args["verb"]) > 1:
+		fail("badVerb", "illegal or missing verb")
+	default:
+		for key, values := range args {
+			if _, ok := allowed[key]; !ok && key != "verb" {
+				fail("badArgument", "illegal argument "+key)
+			} else if len(values) > 1 {
+				fail("badArgument", "repeated argument "+key)
+			}
+		}
+
+		// resumption token is exclusive, otherwise required argument must be present
+		if args.Get("resumptionToken") != "" {
+			if len(args) > 2 {
+				fail("badArgument", "resumptionToken is exclusive")
+			}
+		} else {
+			for key, required := range allowed {
+				if required && args.Get(key) == "" {
+					fail("badArgument", "missing argument "+key)
+				}
+			}
+		}
+	}
+
+	if len(out.Errors) == 0 {
+		keys := []string{}
+
+		for key := range args {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			out.Request.Attrs = append(out.Request.Attrs, xml.Attr{Name: xml.Name{Local: key}, Value: args.Get(key)})
+		}
+
+		var err error
+
+		switch verb {
+		case "Identify":
+			err = ouc.identify(&out)
+		case "ListMetadataFormats":
+			err = ouc.listMetadataFormats(&out, args, fail)
+		case "ListSets":
+			err = ouc.listSets(&out, args, fail)
+		case "GetRecord":
+			err = ouc.getRecord(&out, args, fail)
+		default:
+			err = ouc.list(&out, verb, args, fail)
+		}
+
+		// detect failure in repository
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+	}
+
+	body, err := xml.MarshalIndent(out, "", "  ")
+
+	if err != nil {
+		log.Println(err)
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	res = append([]byte(xml.Header), body...)
+	code, message = http.StatusOK, "success"
+	return
+}
+
+func (ouc OAIUseCase) identify(out *response) (err error) {
+	earliest, err := ouc.bookRepo.GetEarliestDatestamp()
+
+	if err != nil {
+		return
+	}
+
+	out.Identify = &identify{
+		RepositoryName:    ouc.repository.Name,
+		BaseURL:           ouc.repository.BaseURL,
+		ProtocolVersion:   "2.0",
+		AdminEmail:        ouc.repository.AdminEmail,
+		EarliestDatestamp: earliest.UTC().Format(secondGranularity),
+		DeletedRecord:     "persistent",
+		Granularity:       "YYYY-MM-DDThh:mm:ssZ",
+	}
+
+	return
+}
+
+func (ouc OAIUseCase) listMetadataFormats(out *response, args url.Values, fail func(string, string)) (err error) {
+	// every record is available in every format, identifier only needs to exist
+	if identifier := args.Get("identifier"); identifier != "" {
+		bookId := ouc.parseIdentifier(identifier)
+		books := []_entity.HarvestedBook{}
+
+		if bookId != 0 {
+			if books, err = ouc.bookRepo.HarvestBooks(_model.HarvestBooksRequest{Id: bookId, Limit: 1}); err != nil {
+				return
+			}
+		}
+
+		if len(books) == 0 {
+			fail("idDoesNotExist", "unknown identifier")
+			return
+		}
+	}
+
+	out.ListMetadataFormats = &listMetadataFormats{Formats: formats}
+	return
+}
+
+func (ouc OAIUseCase) listSets(out *response, args url.Values, fail func(string, string)) (err error) {
+	// all sets fit in one response, so no token is ever issued
+	if args.Get("resumptionToken") != "" {
+		fail("badResumptionToken", "invalid resumption token")
+		return
+	}
+
+	categories, err := ouc.bookRepo.GetAllCategories()
+
+	if err != nil {
+		return
+	}
+
+	sets, seen := []set{}, map[string]interface{}{}
+
+	for _, category := range categories {
+		spec := setSpec(category)
+
+		if _, exist := seen[spec]; exist || spec == "" {
+			continue
+		}
+
+		seen[spec] = nil
+		sets = append(sets, set{Spec: spec, Name: category})
+	}
+
+	if len(sets) == 0 {
+		fail("noSetHierarchy", "repository has no set")
+		return
+	}
+
+	out.ListSets = &listSets{Sets: sets}
+	return
+}
+
+func (ouc OAIUseCase) getRecord(out *response, args url.Values, fail func(string, string)) (err error) {
+	prefix := args.Get("metadataPrefix")
+
+	if !isFormat(prefix) {
+		fail("cannotDisseminateFormat", "unsupported metadata format")
+		return
+	}
+
+	bookId := ouc.parseIdentifier(args.Get("identifier"))
+	books := []_entity.HarvestedBook{}
+
+	if bookId != 0 {
+		if books, err = ouc.bookRepo.HarvestBooks(_model.HarvestBooksRequest{Id: bookId, Limit: 1}); err != nil {
+			return
+		}
+	}
+
+	if len(books) == 0 {
+		fail("idDoesNotExist", "unknown identifier")
+		return
+	}
+
+	rec, err := ouc.toRecord(books[0], prefix)
+
+	if err != nil {
+		return
+	}
+
+	out.GetRecord = &getRecord{Record: rec}
+	return
+}
+
+// list serves both ListIdentifiers and ListRecords
+func (ouc OAIUseCase) list(out *response, verb string, args url.Values, fail func(string, string)) (err error) {
+	h := harvest{}
+
+	if token := args.Get("resumptionToken"); token != "" {
+		ok := false
+
+		if h, ok = decodeToken(token); !ok {
+			fail("badResumptionToken", "invalid resumption token")
+			return
+		}
+	} else {
+		h.prefix, h.set = args.Get("metadataPrefix"), args.Get("set")
+
+		if !isFormat(h.prefix) {
+			fail("cannotDisseminateFormat", "unsupported metadata format")
+			return
+		}
+
+		fromLayout, untilLayout := "", ""
+
+		if from := args.Get("from"); from != "" {
+			if h.from, fromLayout, err = parseDatestamp(from, false); err != nil {
+				err = nil
+				fail("badArgument", "invalid from")
+				return
+			}
+		}
+
+		if until := args.Get("until"); until != "" {
+			if h.until, untilLayout, err = parseDatestamp(until, true); err != nil {
+				err = nil
+				fail("badArgument", "invalid until")
+				return
+			}
+		}
+
+		if fromLayout != "" && untilLayout != "" && fromLayout != untilLayout {
+			fail("badArgument", "from and until must have the same granularity")
+			return
+		}
+
+		if !h.from.IsZero() && !h.until.IsZero() && !h.from.Before(h.until) {
+			fail("badArgument", "from must not be later than until")
+			return
+		}
+	}
+
+	params := _model.HarvestBooksRequest{From: h.from, Until: h.until, AfterId: h.afterId, Limit: pageSize + 1}
+
+	// set is resolved back to every category sharing the same spec
+	if h.set != "" {
+		categories, err := ouc.bookRepo.GetAllCategories()
+
+		if err != nil {
+			return err
+		}
+
+		for _, category := range categories {
+			if setSpec(category) == h.set {
+				params.Categories = append(params.Categories, category)
+			}
+		}
+
+		if len(params.Categories) == 0 {
+			fail("noRecordsMatch", "no record matches the request")
+			return nil
+		}
+	}
+
+	books, err := ouc.bookRepo.HarvestBooks(params)
+
+	if err != nil {
+		return
+	}
+
+	if len(books) == 0 {
+		fail("noRecordsMatch", "no record matches the request")
+		return
+	}
+
+	// one extra book tells whether another page follows, the last page ends with empty token
+	var token *resumptionToken
+
+	if len(books) > pageSize {
+		books = books[:pageSize]
+		next := h
+		next.afterId, next.cursor = books[len(books)-1].Id, h.cursor+pageSize
+		token = &resumptionToken{Cursor: h.cursor, Token: encodeToken(next)}
+	} else if h.cursor > 0 {
+		token = &resumptionToken{Cursor: h.cursor}
+	}
+
+	if verb == "ListIdentifiers" {
+		out.ListIdentifiers = &listIdentifiers{ResumptionToken: token}
+
+		for _, book := range books {
+			out.ListIdentifiers.Headers = append(out.ListIdentifiers.Headers, ouc.toHeader(book))
+		}
+
+		return
+	}
+
+	out.ListRecords = &listRecords{ResumptionToken: token}
+
+	for _, book := range books {
+		rec, err := ouc.toRecord(book, h.prefix)
+
+		if err != nil {
+			return err
+		}
+
+		out.ListRecords.Records = append(out.ListRecords.Records, rec)
+	}
+
+	return
+}
+
+func (ouc OAIUseCase) toHeader(book _entity.HarvestedBook) (h header) {
+	h.Identifier = ouc.identifier(book.Id)
+	h.Datestamp = book.Datestamp.UTC().Format(secondGranularity)
+
+	if spec := setSpec(book.Category); spec != "" {
+		h.SetSpecs = append(h.SetSpecs, spec)
+	}
+
+	if book.Deleted {
+		h.Status = "deleted"
+	}
+
+	return
+}
+
+// toRecord leaves out metadata of deleted book
+func (ouc OAIUseCase) toRecord(book _entity.HarvestedBook, prefix string) (rec record, err error) {
+	rec.Header = ouc.toHeader(book)
+
+	if book.Deleted {
+		return
+	}
+
+	if book.Author, err = ouc.bookRepo.GetBookAuthors(book.Id); err != nil {
+		return
+	}
+
+	data := []byte{}
+
+	if prefix == "marc21" {
+		data, err = _catalog.MarshalMARCXML(_catalog.ToRecord(book.ExportedBook))
+	} else {
+		data, err = xml.Marshal(toDublinCore(book.ExportedBook))
+	}
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	rec.Metadata = &metadata{Inner: data}
+	return
+}
+
+func toDublinCore(book _entity.ExportedBook) (dc dublinCore) {
+	dc.OAIDC = "http://www.openarchives.org/OAI/2.0/oai_dc/"
+	dc.DC = "http://purl.org/dc/elements/1.1/"
+	dc.XSI = "http://www.w3.org/2001/XMLSchema-instance"
+	dc.SchemaLocation = "http://www.openarchives.org/OAI/2.0/oai_dc/ http://www.openarchives.org/OAI/2.0/oai_dc.xsd"
+	dc.Title = book.Title
+	dc.Description = book.Description
+	dc.Publisher = book.Publisher
+	dc.Language = book.Language
+	dc.Type = "Text"
+
+	for _, author := range book.Author {
+		dc.Creators = append(dc.Creators, author.Name)
+	}
+
+	if book.Category != "" {
+		dc.Subjects = append(dc.Subjects, book.Category)
+	}
+
+	if book.Pages > 0 {
+		dc.Format = fmt.Sprintf("%d pages", book.Pages)
+	}
+
+	if book.ISBN13 != "" {
+		dc.Identifiers = append(dc.Identifiers, "urn:isbn:"+book.ISBN13)
+	}
+
+	return
+}
@@ -0,0 +1,113 @@
+package oai
+
+import (
+	"encoding/xml"
+)
+
+type response struct {
+	XMLName             xml.Name `xml:"http://www.openarchives.org/OAI/2.0/ OAI-PMH"`
+	XSI                 string   `xml:"xmlns:xsi,attr"`
+	SchemaLocation      string   `xml:"xsi:schemaLocation,attr"`
+	ResponseDate        string   `xml:"responseDate"`
+	Request             request  `xml:"request"`
+	Errors              []oaiError
+	Identify            *identify            `xml:"Identify,omitempty"`
+	ListMetadataFormats *listMetadataFormats `xml:"ListMetadataFormats,omitempty"`
+	ListSets            *listSets            `xml:"ListSets,omitempty"`
+	ListIdentifiers     *listIdentifiers     `xml:"ListIdentifiers,omitempty"`
+	ListRecords         *listRecords         `xml:"ListRecords,omitempty"`
+	GetRecord           *getRecord           `xml:"GetRecord,omitempty"`
+}
+
+type request struct {
+	Attrs   []xml.Attr `xml:",any,attr"`
+	BaseURL string     `xml:",chardata"`
+}
+
+type oaiError struct {
+	XMLName xml.Name `xml:"error"`
+	Code    string   `xml:"code,attr"`
+	Message string   `xml:",chardata"`
+}
+
+type identify struct {
+	RepositoryName    string `xml:"repositoryName"`
+	BaseURL           string `xml:"baseURL"`
+	ProtocolVersion   string `xml:"protocolVersion"`
+	AdminEmail        string `xml:"adminEmail"`
+	EarliestDatestamp string `xml:"earliestDatestamp"`
+	DeletedRecord     string `xml:"deletedRecord"`
+	Granularity       string `xml:"granularity"`
+}
+
+type metadataFormat struct {
+	Prefix    string `xml:"metadataPrefix"`
+	Schema    string `xml:"schema"`
+	Namespace string `xml:"metadataNamespace"`
+}
+
+type listMetadataFormats struct {
+	Formats []metadataFormat `xml:"metadataFormat"`
+}
+
+type set struct {
+	Spec string `xml:"setSpec"`
+	Name string `xml:"setName"`
+}
+
+type listSets struct {
+	Sets []set `xml:"set"`
+}
+
+type header struct {
+	Status     string   `xml:"status,attr,omitempty"`
+	Identifier string   `xml:"identifier"`
+	Datestamp  string   `xml:"datestamp"`
+	SetSpecs   []string `xml:"setSpec"`
+}
+
+type metadata struct {
+	Inner []byte `xml:",innerxml"`
+}
+
+type record struct {
+	Header   header    `xml:"header"`
+	Metadata *metadata `xml:"metadata,omitempty"`
+}
+
+type resumptionToken struct {
+	Cursor uint   `xml:"cursor,attr"`
+	Token  string `xml:",chardata"`
+}
+
+type listIdentifiers struct {
+	Headers         []header         `xml:"header"`
+	ResumptionToken *resumptionToken `xml:"resumptionToken,omitempty"`
+}
+
+type listRecords struct {
+	Records         []record         `xml:"record"`
+	ResumptionToken *resumptionToken `xml:"resumptionToken,omitempty"`
+}
+
+type getRecord struct {
+	Record record `xml:"record"`
+}
+
+// unqualified Dublin Core as defined by oai_dc schema
+type dublinCore struct {
+	XMLName        xml.Name `xml:"oai_dc:dc"`
+	OAIDC          string   `xml:"xmlns:oai_dc,attr"`
+	DC             string   `xml:"xmlns:dc,attr"`
+	XSI            string   `xml:"xmlns:xsi,attr"`
+	SchemaLocation string   `xml:"xsi:schemaLocation,attr"`
+	Title          string   `xml:"dc:title"`
+	Creators       []string `xml:"dc:creator"`
+	Subjects       []string `xml:"dc:subject"`
+	Description    string   `xml:"dc:description,omitempty"`
+	Publisher      string   `xml:"dc:publisher,omitempty"`
+	Type           string   `xml:"dc:type"`
+	Format         string   `xml:"dc:format,omitempty"`
+	Identifiers    []string `xml:"dc:identifier"`
+	Language       string   `xml:"dc:language,omitempty"`
+}
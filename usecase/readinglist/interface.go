@@ -0,0 +1,19 @@
+package readinglist
+
+import (
+	"net/url"
+	_model "plain-go/public-library/model"
+)
+
+type ReadingList interface {
+	CreateList(userId uint, req _model.CreateReadingListRequest) (res _model.CreateReadingListResponse, code int, message string)
+	GetAllPublicLists(query url.Values) (res _model.GetAllReadingListsResponse, code int, message string)
+	GetListsByUserId(viewerId uint, userId uint) (res _model.GetAllReadingListsResponse, code int, message string)
+	GetListById(viewerId uint, listId uint, token string) (res _model.GetReadingListByIdResponse, code int, message string)
+	UpdateList(userId uint, listId uint, req _model.UpdateReadingListRequest) (res _model.UpdateReadingListResponse, code int, message string)
+	DeleteList(userId uint, listId uint) (code int, message string)
+	SaveItem(userId uint, listId uint, bookId uint, req _model.SaveReadingListItemRequest) (res _model.SaveReadingListItemResponse, code int, message string)
+	RemoveItem(userId uint, listId uint, bookId uint) (code int, message string)
+	ReorderItems(userId uint, listId uint, req _model.ReorderReadingListRequest) (res _model.GetReadingListByIdResponse, code int, message string)
+	FeatureList(listId uint, req _model.FeatureReadingListRequest) (res _model.UpdateReadingListResponse, code int, message string)
+}
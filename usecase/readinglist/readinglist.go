@@ -0,0 +1,654 @@
+package readinglist
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	_bookRepository "plain-go/public-library/datastore/book"
+	_readingListRepository "plain-go/public-library/datastore/readinglist"
+	_userRepository "plain-go/public-library/datastore/user"
+	_entity "plain-go/public-library/entity"
+	_helper "plain-go/public-library/helper"
+	_model "plain-go/public-library/model"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// private list is only seen by its owner, link only list by anyone holding the share token,
+// public list is browsable by every patron
+var visibilities = map[string]interface{}{"private": nil, "link_only": nil, "public": nil}
+
+type ReadingListUseCase struct {
+	listRepo _readingListRepository.ReadingList
+	bookRepo _bookRepository.Book
+	userRepo _userRepository.User
+}
+
+func New(list _readingListRepository.ReadingList, book _bookRepository.Book, user _userRepository.User) *ReadingListUseCase {
+	return &ReadingListUseCase{listRepo: list, bookRepo: book, userRepo: user}
+}
+
+func formatList(list *_entity.ReadingList, viewerId uint) {
+	// share token is a secret of the owner
+	if list.Owner.Id != viewerId {
+		list.ShareToken = ""
+	}
+
+	list.CreatedAt, _ = _helper.TimeFormatter(list.CreatedAt)
+	list.UpdatedAt, _ = _helper.TimeFormatter(list.UpdatedAt)
+}
+
+// getOwnedList returns list which belongs to the user
+func (rluc ReadingListUseCase) getOwnedList(userId uint, listId uint) (list _entity.ReadingList, code int, message string) {
+	// calling repository
+	list, err := rluc.listRepo.GetListById(listId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if list.Name == "" {
+		log.Println("reading list not found")
+		code, message = http.StatusNotFound, "reading list not found"
+		return
+	}
+
+	// check if owner does not match
+	if list.Owner.Id != userId {
+		log.Println("forbidden")
+		code, message = http.StatusForbidden, "forbidden"
+		return
+	}
+
+	return
+}
+
+func (rluc ReadingListUseCase) CreateList(userId uint, req _model.CreateReadingListRequest) (res _model.CreateReadingListResponse, code int, message string) {
+	// prepare input string
+	name := strings.TrimSpace(req.Name)
+	description := strings.TrimSpace(req.Description)
+	visibility := strings.ToLower(strings.TrimSpace(req.Visibility))
+
+	// check if required input is empty
+	if name == "" {
+		log.Println("empty input")
+		code, message = http.StatusBadRequest, "empty input"
+		return
+	}
+
+	for _, s := range []string{name, description} {
+		// check if there is any forbidden character
+		if strings.Contains(strings.ReplaceAll(s, " ", ""), ";--") {
+			log.Println("forbidden character")
+			code, message = http.StatusBadRequest, "forbidden character"
+			return
+		}
+	}
+
+	if len(name) > 100 || len(description) > 1000 {
+		log.Println("input too long")
+		code, message = http.StatusBadRequest, "name must be at most 100 and description at most 1000 characters"
+		return
+	}
+
+	if visibility == "" {
+		visibility = "private"
+	}
+
+	if _, exist := visibilities[visibility]; !exist {
+		log.Println("invalid visibility")
+		code, message = http.StatusBadRequest, "visibility must be one of private, link_only or public"
+		return
+	}
+
+	// check user existence
+	user, err := rluc.userRepo.GetUserById(userId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if user.Name == "" {
+		log.Println("user not found")
+		code, message = http.StatusNotFound, "user not found"
+		return
+	}
+
+	// prepare input to repository
+	now := time.Now()
+	newList := _entity.ReadingList{}
+	newList.Owner.Id = user.Id
+	newList.Owner.Name = user.Name
+	newList.Name = name
+	newList.Description = description
+	newList.Visibility = visibility
+	newList.ShareToken = _helper.GenerateId()
+	newList.CreatedAt = now
+	newList.UpdatedAt = now
+
+	// calling repository
+	res.List, err = rluc.listRepo.CreateList(newList)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	formatList(&res.List, userId)
+	code, message = http.StatusCreated, "success create reading list"
+
+	return
+}
+
+func (rluc ReadingListUseCase) GetAllPublicLists(query url.Values) (res _model.GetAllReadingListsResponse, code int, message string) {
+	// default parameters
+	params := _model.GetAllReadingListsRequest{}
+	params.Page = 1
+	params.Records = 20
+	params.Keyword = strings.TrimSpace(query.Get("keyword"))
+
+	if value, exist := query["page"]; exist {
+		page, err := strconv.Atoi(value[0])
+
+		if err != nil {
+			log.Println(err)
+			code, message = http.StatusBadRequest, "invalid page"
+			return
+		}
+
+		if page < 1 {
+			log.Println("invalid page")
+			code, message = http.StatusBadRequest, "invalid page"
+			return
+		}
+
+		params.Page = page
+	}
+
+	mapRecords := map[int]interface{}{10: nil, 20: nil, 50: nil}
+
+	if value, exist := query["records"]; exist {
+		records, err := strconv.Atoi(value[0])
+
+		if err != nil {
+			log.Println(err)
+			code, message = http.StatusBadRequest, "invalid number of records"
+			return
+		}
+
+		if _, exist := mapRecords[records]; !exist {
+			log.Println("unaccepted number of records")
+			code, message = http.StatusBadRequest, "unaccepted number of records"
+			return
+		}
+
+		params.Records = records
+	}
+
+	if value, exist := query["featured"]; exist {
+		featured, err := strconv.ParseBool(value[0])
+
+		if err != nil {
+			log.Println(err)
+			code, message = http.StatusBadRequest, "invalid featured filter"
+			return
+		}
+
+		params.Featured = featured
+	}
+
+	// calling repository
+	lists, err := rluc.listRepo.GetPublicLists(params)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	for i := range lists {
+		formatList(&lists[i], 0)
+	}
+
+	res.Lists = lists
+	code, message = http.StatusOK, "success get all reading lists"
+
+	return
+}
+
+func (rluc ReadingListUseCase) GetListsByUserId(viewerId uint, userId uint) (res _model.GetAllReadingListsResponse, code int, message string) {
+	// check user existence
+	user, err := rluc.userRepo.GetUserById(userId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if user.Name == "" {
+		log.Println("user not found")
+		code, message = http.StatusNotFound, "user not found"
+		return
+	}
+
+	// other patrons only see public lists
+	lists, err := rluc.listRepo.GetListsByUserId(userId, viewerId != userId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	for i := range lists {
+		formatList(&lists[i], viewerId)
+	}
+
+	res.Lists = lists
+	code, message = http.StatusOK, "success get all reading lists"
+
+	return
+}
+
+func (rluc ReadingListUseCase) GetListById(viewerId uint, listId uint, token string) (res _model.GetReadingListByIdResponse, code int, message string) {
+	// calling repository
+	list, err := rluc.listRepo.GetListById(listId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// list which cannot be seen is reported as not found, so its existence is not leaked
+	visible := list.Owner.Id == viewerId || list.Visibility == "public" || (list.Visibility == "link_only" && token != "" && token == list.ShareToken)
+
+	if list.Name == "" || !visible {
+		log.Println("reading list not found")
+		code, message = http.StatusNotFound, "reading list not found"
+		return
+	}
+
+	list.Items, err = rluc.getItems(listId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	res.List = list
+	formatList(&res.List, viewerId)
+	code, message = http.StatusOK, "success get reading list"
+
+	return
+}
+
+func (rluc ReadingListUseCase) getItems(listId uint) (items []_entity.ReadingListItem, err error) {
+	// calling repository
+	items, err = rluc.listRepo.GetItems(listId)
+
+	if err != nil {
+		return
+	}
+
+	for i := range items {
+		// getting each book's authors
+		items[i].Book.Author, err = rluc.bookRepo.GetBookAuthors(items[i].Book.Id)
+
+		if err != nil {
+			return
+		}
+
+		items[i].Book.CreatedAt, _ = _helper.TimeFormatter(items[i].Book.CreatedAt)
+		items[i].Book.UpdatedAt, _ = _helper.TimeFormatter(items[i].Book.UpdatedAt)
+		items[i].CreatedAt, _ = _helper.TimeFormatter(items[i].CreatedAt)
+		items[i].UpdatedAt, _ = _helper.TimeFormatter(items[i].UpdatedAt)
+	}
+
+	return
+}
+
+func (rluc ReadingListUseCase) UpdateList(userId uint, listId uint, req _model.UpdateReadingListRequest) (res _model.UpdateReadingListResponse, code int, message string) {
+	// check list ownership
+	list, code, message := rluc.getOwnedList(userId, listId)
+
+	if code != 0 {
+		return
+	}
+
+	// prepare input string
+	name := strings.TrimSpace(req.Name)
+	description := strings.TrimSpace(req.Description)
+	visibility := strings.ToLower(strings.TrimSpace(req.Visibility))
+	flag := true
+
+	for _, s := range []string{name, description} {
+		// check if there is any forbidden character
+		if strings.Contains(strings.ReplaceAll(s, " ", ""), ";--") {
+			log.Println("forbidden character")
+			code, message = http.StatusBadRequest, "forbidden character"
+			return
+		}
+	}
+
+	if len(name) > 100 || len(description) > 1000 {
+		log.Println("input too long")
+		code, message = http.StatusBadRequest, "name must be at most 100 and description at most 1000 characters"
+		return
+	}
+
+	if _, exist := visibilities[visibility]; visibility != "" && !exist {
+		log.Println("invalid visibility")
+		code, message = http.StatusBadRequest, "visibility must be one of private, link_only or public"
+		return
+	}
+
+	if name != "" && name != list.Name {
+		list.Name = name
+		flag = false
+	}
+
+	if description != "" && description != list.Description {
+		list.Description = description
+		flag = false
+	}
+
+	if visibility != "" && visibility != list.Visibility {
+		list.Visibility = visibility
+		flag = false
+
+		// only public list can stay featured
+		if visibility != "public" {
+			list.Featured = false
+		}
+	}
+
+	// check if no field is updated
+	if flag {
+		log.Println("no update was performed")
+		code, message = http.StatusBadRequest, "no update was performed"
+		return
+	}
+
+	// calling repository
+	list.UpdatedAt = time.Now()
+	updatedList, err := rluc.listRepo.UpdateList(list)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	res.List = updatedList
+	formatList(&res.List, userId)
+	code, message = http.StatusOK, "success update reading list"
+
+	return
+}
+
+func (rluc ReadingListUseCase) DeleteList(userId uint, listId uint) (code int, message string) {
+	// check list ownership
+	_, code, message = rluc.getOwnedList(userId, listId)
+
+	if code != 0 {
+		return
+	}
+
+	// calling repository
+	if err := rluc.listRepo.DeleteList(listId); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	code, message = http.StatusOK, "success delete reading list"
+
+	return
+}
+
+func (rluc ReadingListUseCase) SaveItem(userId uint, listId uint, bookId uint, req _model.SaveReadingListItemRequest) (res _model.SaveReadingListItemResponse, code int, message string) {
+	// prepare input string
+	note := strings.TrimSpace(req.Note)
+
+	// check if there is any forbidden character
+	if strings.Contains(strings.ReplaceAll(note, " ", ""), ";--") {
+		log.Println("forbidden character")
+		code, message = http.StatusBadRequest, "forbidden character"
+		return
+	}
+
+	if len(note) > 500 {
+		log.Println("note too long")
+		code, message = http.StatusBadRequest, "note must be at most 500 characters"
+		return
+	}
+
+	// check list ownership
+	_, code, message = rluc.getOwnedList(userId, listId)
+
+	if code != 0 {
+		return
+	}
+
+	// check book existence
+	book, err := rluc.bookRepo.GetBookById(bookId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if book.Title == "" {
+		log.Println("book not found")
+		code, message = http.StatusNotFound, "book not found"
+		return
+	}
+
+	item, err := rluc.listRepo.GetItem(listId, bookId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	now := time.Now()
+
+	// book already in list only gets its note updated
+	if item.Book.Id != 0 {
+		if note == item.Note {
+			log.Println("no update was performed")
+			code, message = http.StatusBadRequest, "no update was performed"
+			return
+		}
+
+		item.Note = note
+		item.UpdatedAt = now
+
+		// calling repository
+		res.Item, err = rluc.listRepo.UpdateItem(listId, item)
+		code, message = http.StatusOK, "success update book in reading list"
+	} else {
+		item.Book.Id = bookId
+		item.Note = note
+		item.CreatedAt = now
+		item.UpdatedAt = now
+
+		// calling repository
+		res.Item, err = rluc.listRepo.AddItem(listId, item)
+		code, message = http.StatusCreated, "success add book to reading list"
+	}
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	res.Item.Book = book
+	res.Item.Book.Author, err = rluc.bookRepo.GetBookAuthors(bookId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	res.Item.Book.CreatedAt, _ = _helper.TimeFormatter(res.Item.Book.CreatedAt)
+	res.Item.Book.UpdatedAt, _ = _helper.TimeFormatter(res.Item.Book.UpdatedAt)
+	res.Item.CreatedAt, _ = _helper.TimeFormatter(res.Item.CreatedAt)
+	res.Item.UpdatedAt, _ = _helper.TimeFormatter(res.Item.UpdatedAt)
+
+	return
+}
+
+func (rluc ReadingListUseCase) RemoveItem(userId uint, listId uint, bookId uint) (code int, message string) {
+	// check list ownership
+	_, code, message = rluc.getOwnedList(userId, listId)
+
+	if code != 0 {
+		return
+	}
+
+	// check item existence
+	item, err := rluc.listRepo.GetItem(listId, bookId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if item.Book.Id == 0 {
+		log.Println("book not found in reading list")
+		code, message = http.StatusNotFound, "book not found in reading list"
+		return
+	}
+
+	// calling repository
+	if err = rluc.listRepo.RemoveItem(listId, bookId); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	code, message = http.StatusOK, "success remove book from reading list"
+
+	return
+}
+
+func (rluc ReadingListUseCase) ReorderItems(userId uint, listId uint, req _model.ReorderReadingListRequest) (res _model.GetReadingListByIdResponse, code int, message string) {
+	// check list ownership
+	list, code, message := rluc.getOwnedList(userId, listId)
+
+	if code != 0 {
+		return
+	}
+
+	items, err := rluc.listRepo.GetItems(listId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// new order must mention every book in the list exactly once
+	existingBooks := map[uint]interface{}{}
+
+	for _, item := range items {
+		existingBooks[item.Book.Id] = nil
+	}
+
+	orderedBooks := map[uint]interface{}{}
+
+	for _, bookId := range req.BookIds {
+		if _, exist := existingBooks[bookId]; !exist {
+			log.Println("book not found in reading list")
+			code, message = http.StatusBadRequest, "book not found in reading list"
+			return
+		}
+
+		orderedBooks[bookId] = nil
+	}
+
+	if len(orderedBooks) != len(req.BookIds) || len(orderedBooks) != len(existingBooks) {
+		log.Println("incomplete order")
+		code, message = http.StatusBadRequest, "order must contain every book in the reading list exactly once"
+		return
+	}
+
+	// calling repository
+	if err = rluc.listRepo.ReorderItems(listId, req.BookIds); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	list.Items, err = rluc.getItems(listId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	res.List = list
+	formatList(&res.List, userId)
+	code, message = http.StatusOK, "success reorder reading list"
+
+	return
+}
+
+func (rluc ReadingListUseCase) FeatureList(listId uint, req _model.FeatureReadingListRequest) (res _model.UpdateReadingListResponse, code int, message string) {
+	if req.Featured == nil {
+		log.Println("empty input")
+		code, message = http.StatusBadRequest, "empty input"
+		return
+	}
+
+	// calling repository
+	list, err := rluc.listRepo.GetListById(listId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// librarian only curates what patrons already shared publicly
+	if list.Name == "" || list.Visibility != "public" {
+		log.Println("reading list not found")
+		code, message = http.StatusNotFound, "reading list not found"
+		return
+	}
+
+	if list.Featured == *req.Featured {
+		log.Println("no update was performed")
+		code, message = http.StatusBadRequest, "no update was performed"
+		return
+	}
+
+	// calling repository
+	list.Featured = *req.Featured
+	list.UpdatedAt = time.Now()
+	res.List, err = rluc.listRepo.UpdateList(list)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	formatList(&res.List, 0)
+	code, message = http.StatusOK, "success update featured reading list"
+
+	return
+}
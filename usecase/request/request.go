@@ -3,23 +3,56 @@ package request
 import (
 	"log"
 	"net/http"
+	_event "plain-go/public-library/app/event"
 	_bookRepository "plain-go/public-library/datastore/book"
 	_requestRepository "plain-go/public-library/datastore/request"
 	_userRepository "plain-go/public-library/datastore/user"
+	_workRepository "plain-go/public-library/datastore/work"
 	_entity "plain-go/public-library/entity"
 	_helper "plain-go/public-library/helper"
 	_model "plain-go/public-library/model"
 	"time"
 )
 
+var requestStatus = map[uint]string{
+	1: "waiting in queue",
+	2: "book is being prepared",
+	3: "request is cancelled",
+	4: "book is ready for pick up",
+	5: "book is borrowed",
+	6: "request is extended",
+	7: "book is late, penalty applies",
+	8: "book is returned",
+	9: "book is returned, penalty paid",
+}
+
 type RequestUseCase struct {
 	bookRepo    _bookRepository.Book
 	userRepo    _userRepository.User
 	requestRepo _requestRepository.Request
+	workRepo    _workRepository.Work
+	publisher   _event.Publisher
 }
 
-func New(book _bookRepository.Book, user _userRepository.User, request _requestRepository.Request) *RequestUseCase {
-	return &RequestUseCase{bookRepo: book, userRepo: user, requestRepo: request}
+func New(book _bookRepository.Book, user _userRepository.User, request _requestRepository.Request, work _workRepository.Work, publisher _event.Publisher) *RequestUseCase {
+	return &RequestUseCase{bookRepo: book, userRepo: user, requestRepo: request, workRepo: work, publisher: publisher}
+}
+
+// event id is given here so the outbox record and the live stream share it
+func requestEvent(eventType string, request _entity.Request) (event _entity.Event) {
+	event.Id = _helper.GenerateId()
+	event.OccurredAt = time.Now()
+	event.Type = eventType
+	event.AggregateType = "request"
+	event.AggregateId = request.Id
+	event.UserId = request.User.Id
+	event.Payload = map[string]interface{}{
+		"book_item_id": request.BookItem.Id,
+		"status_id":    request.Status.Id,
+		"status":       requestStatus[request.Status.Id],
+	}
+
+	return
 }
 
 func (ruc RequestUseCase) GetAllRequests() (res _model.GetAllRequestResponse, code int, message string) {
@@ -224,36 +257,80 @@ func (ruc RequestUseCase) CreateRequest(userId uint, req _model.CreateRequestReq
 		return
 	}
 
-	// check book existence
-	book, err := ruc.bookRepo.GetBookById(req.BookId)
+	book, bookItemId := _entity.Book{}, uint(0)
 
-	// detect failure in repository
-	if err != nil {
-		code, message = http.StatusInternalServerError, "internal server error"
-		return
-	}
+	if req.WorkId != 0 {
+		// check work existence
+		work, err := ruc.workRepo.GetWorkById(req.WorkId)
 
-	if book.Title == "" {
-		log.Println("book not found")
-		code, message = http.StatusNotFound, "book not found"
-		return
-	}
+		// detect failure in repository
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
 
-	// check book availability
-	bookItemId, err := ruc.bookRepo.GetAvailableBookByBookId(req.BookId)
+		if work.Title == "" {
+			log.Println("work not found")
+			code, message = http.StatusNotFound, "work not found"
+			return
+		}
 
-	// detect failure in repository
-	if err != nil {
-		code, message = http.StatusInternalServerError, "internal server error"
-		return
+		// any edition of the work will do
+		bookItemId, err = ruc.workRepo.GetAvailableBookItemByWorkId(req.WorkId)
+
+		// detect failure in repository
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		if bookItemId != 0 {
+			book, err = ruc.bookRepo.GetBookByItemId(bookItemId)
+
+			// detect failure in repository
+			if err != nil {
+				code, message = http.StatusInternalServerError, "internal server error"
+				return
+			}
+		}
+	} else {
+		// check book existence
+		book, err = ruc.bookRepo.GetBookById(req.BookId)
+
+		// detect failure in repository
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		if book.Title == "" {
+			log.Println("book not found")
+			code, message = http.StatusNotFound, "book not found"
+			return
+		}
+
+		// check book availability
+		bookItemId, err = ruc.bookRepo.GetAvailableBookByBookId(req.BookId)
+
+		// detect failure in repository
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
 	}
 
 	// prepare input to repository
 	now := time.Now()
 	newRequest := _entity.Request{}
 	newRequest.User.Id = userId
+	newRequest.WorkId = req.WorkId
 
 	if bookItemId == 0 {
+		// request on a book waits for that book, hold on work for any of its editions
+		if req.WorkId == 0 {
+			newRequest.BookId = req.BookId
+		}
+
 		newRequest.BookItem.Id = -1
 		newRequest.Status.Id = 1
 		newRequest.Status.Description = "waiting in queue"
@@ -267,27 +344,35 @@ func (ruc RequestUseCase) CreateRequest(userId uint, req _model.CreateRequestReq
 	newRequest.UpdatedAt = now
 
 	// calling repository
-	res.Request, err = ruc.requestRepo.CreateNewRequest(newRequest)
+	event := requestEvent("request.created", newRequest)
+	res.Request, err = ruc.requestRepo.CreateNewRequest(newRequest, event)
 
 	if err != nil {
 		code, message = http.StatusInternalServerError, "internal server error"
 		return
 	}
 
+	// live stream is fed once the request is committed
+	event.AggregateId = res.Request.Id
+	ruc.publisher.Publish(event)
+
 	// formatting response
 	user.Password = ""
 	user.CreatedAt, _ = _helper.TimeFormatter(user.CreatedAt)
 	user.UpdatedAt, _ = _helper.TimeFormatter(user.UpdatedAt)
 	res.Request.User = user
 
-	book.CreatedAt, _ = _helper.TimeFormatter(book.CreatedAt)
-	book.UpdatedAt, _ = _helper.TimeFormatter(book.UpdatedAt)
-	averageStar, _ := ruc.bookRepo.CountStarsByBookId(req.BookId)
-	book.AverageStar = _helper.NilHandler(averageStar)
-	book.FavoriteCount, _ = ruc.bookRepo.CountFavoritesByBookId(req.BookId)
-	book.Author, _ = ruc.bookRepo.GetBookAuthors(req.BookId)
-	book.Quantity, _ = ruc.bookRepo.CountBookById(req.BookId)
-	res.Request.BookItem.Book = book
+	// hold on work waiting in queue has no edition yet
+	if book.Id != 0 {
+		book.CreatedAt, _ = _helper.TimeFormatter(book.CreatedAt)
+		book.UpdatedAt, _ = _helper.TimeFormatter(book.UpdatedAt)
+		averageStar, _ := ruc.bookRepo.CountStarsByBookId(book.Id)
+		book.AverageStar = _helper.NilHandler(averageStar)
+		book.FavoriteCount, _ = ruc.bookRepo.CountFavoritesByBookId(book.Id)
+		book.Author, _ = ruc.bookRepo.GetBookAuthors(book.Id)
+		book.Quantity, _ = ruc.bookRepo.CountBookById(book.Id)
+		res.Request.BookItem.Book = book
+	}
 
 	res.Request.CreatedAt, _ = _helper.TimeFormatter(res.Request.CreatedAt)
 	res.Request.UpdatedAt, _ = _helper.TimeFormatter(res.Request.UpdatedAt)
@@ -320,6 +405,7 @@ func (ruc RequestUseCase) UpdateRequest(userId uint, requestId uint, role string
 	// global timestamp
 	now := time.Now()
 	request.UpdatedAt = now
+	previousStatus := request.Status.Id
 
 	switch role {
 	case "Member":
@@ -412,7 +498,14 @@ func (ruc RequestUseCase) UpdateRequest(userId uint, requestId uint, role string
 	}
 
 	// calling repository
-	res.Request, err = ruc.requestRepo.Update(request)
+	// record status change as event
+	events := []_entity.Event{}
+
+	if request.Status.Id != previousStatus {
+		events = append(events, requestEvent("request.status_changed", request))
+	}
+
+	res.Request, err = ruc.requestRepo.Update(request, events...)
 
 	// detect failure in repository
 	if err != nil {
@@ -420,6 +513,22 @@ func (ruc RequestUseCase) UpdateRequest(userId uint, requestId uint, role string
 		return
 	}
 
+	// live stream is fed once the change is committed
+	for _, event := range events {
+		ruc.publisher.Publish(event)
+	}
+
+	// copy released by return or cancellation goes to the oldest request waiting for its book or work
+	// failing to promote does not undo the update, the request just stays in queue
+	switch res.Request.Status.Id {
+	case 3, 8, 9:
+		if previousStatus != res.Request.Status.Id && res.Request.BookItem.Id > 0 {
+			if err := ruc.promoteHold(res.Request.BookItem.Id, now); err != nil {
+				log.Println("failed to promote request in queue:", err)
+			}
+		}
+	}
+
 	// check for late return
 	if res.Request.Status.Id == 5 || res.Request.Status.Id == 6 {
 		borrowDuration := time.Until(res.Request.FinishAt.(time.Time))
@@ -429,7 +538,11 @@ func (ruc RequestUseCase) UpdateRequest(userId uint, requestId uint, role string
 			if futureReq.Status.Id == 5 || futureReq.Status.Id == 6 {
 				futureReq.Status.Id = 7
 				futureReq.UpdatedAt = futureReq.FinishAt.(time.Time)
-				ruc.requestRepo.Update(futureReq)
+				event := requestEvent("request.status_changed", futureReq)
+
+				if _, err := ruc.requestRepo.Update(futureReq, event); err == nil {
+					ruc.publisher.Publish(event)
+				}
 			}
 		})
 
@@ -461,3 +574,39 @@ func (ruc RequestUseCase) UpdateRequest(userId uint, requestId uint, role string
 
 	return
 }
+
+func (ruc RequestUseCase) promoteHold(bookItemId int, now time.Time) (err error) {
+	book, err := ruc.bookRepo.GetBookByItemId(uint(bookItemId))
+
+	if err != nil || book.Id == 0 {
+		return
+	}
+
+	// book outside any work only has its own queue
+	workId, err := ruc.workRepo.GetWorkIdByBookId(book.Id)
+
+	if err != nil {
+		return
+	}
+
+	hold, err := ruc.requestRepo.GetOldestWaitingRequest(book.Id, workId)
+
+	if err != nil || hold.Id == 0 {
+		return
+	}
+
+	// prepare input to repository
+	hold.BookItem.Id = bookItemId
+	hold.Status.Id = 2 // "book is being prepared"
+	hold.UpdatedAt = now
+
+	event := requestEvent("request.status_changed", hold)
+
+	if _, err = ruc.requestRepo.AssignBookItem(hold, event); err != nil {
+		return
+	}
+
+	ruc.publisher.Publish(event)
+
+	return
+}
@@ -1,6 +1,7 @@
 package review
 
 import (
+	"net/url"
 	_model "plain-go/public-library/model"
 )
 
@@ -8,8 +9,19 @@ type Review interface {
 	GetAllReviews() (res _model.GetAllReviewsResponse, code int, message string)
 	CreateReview(userId uint, bookId uint, req _model.CreateReviewRequest) (res _model.CreateReviewResponse, code int, message string)
 	GetReviewByReviewId(bookId uint, reviewId uint) (res _model.GetReviewByIdResponse, code int, message string)
-	GetAllReviewsByBookId(bookId uint) (res _model.GetAllReviewsByBookIdResponse, code int, message string)
+	GetAllReviewsByBookId(bookId uint, query url.Values) (res _model.GetAllReviewsByBookIdResponse, code int, message string)
 	UpdateReview(userId uint, bookId uint, reviewId uint, req _model.UpdateReviewRequest) (res _model.UpdateReviewResponse, code int, message string)
 	UpdateStatus(bookId uint, reviewId uint, req _model.UpdateReviewRequest) (code int, message string)
 	DeleteReview(userId uint, bookId uint, reviewId uint) (code int, message string)
+	VoteReview(userId uint, bookId uint, reviewId uint, req _model.VoteReviewRequest) (res _model.VoteReviewResponse, code int, message string)
+	RemoveVote(userId uint, bookId uint, reviewId uint) (code int, message string)
+	ReportReview(userId uint, bookId uint, reviewId uint, req _model.ReportReviewRequest) (res _model.ReportReviewResponse, code int, message string)
+	GetModerationQueue() (res _model.GetModerationQueueResponse, code int, message string)
+	ModerateReview(librarianId uint, reviewId uint, req _model.ModerateReviewRequest) (res _model.ModerateReviewResponse, code int, message string)
+	CreateReply(userId uint, bookId uint, reviewId uint, req _model.CreateReplyRequest) (res _model.CreateReplyResponse, code int, message string)
+	UpdateReply(userId uint, bookId uint, reviewId uint, replyId uint, req _model.UpdateReplyRequest) (res _model.UpdateReplyResponse, code int, message string)
+	DeleteReply(userId uint, bookId uint, reviewId uint, replyId uint) (code int, message string)
+	ReportReply(userId uint, bookId uint, reviewId uint, replyId uint, req _model.ReportReviewRequest) (res _model.ReportReviewResponse, code int, message string)
+	GetReplyModerationQueue() (res _model.GetReplyModerationQueueResponse, code int, message string)
+	ModerateReply(librarianId uint, replyId uint, req _model.ModerateReviewRequest) (res _model.ModerateReviewResponse, code int, message string)
 }
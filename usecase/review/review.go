@@ -3,22 +3,45 @@ package review
 import (
 	"log"
 	"net/http"
+	"net/url"
+	_config "plain-go/public-library/app/config"
 	_bookRepository "plain-go/public-library/datastore/book"
+	_moderationRepository "plain-go/public-library/datastore/moderation"
+	_requestRepository "plain-go/public-library/datastore/request"
 	_userRepository "plain-go/public-library/datastore/user"
 	_entity "plain-go/public-library/entity"
 	_helper "plain-go/public-library/helper"
 	_model "plain-go/public-library/model"
+	"strconv"
 	"strings"
 	"time"
 )
 
 type ReviewUseCase struct {
-	bookRepo _bookRepository.Book
-	userRepo _userRepository.User
+	bookRepo       _bookRepository.Book
+	userRepo       _userRepository.User
+	requestRepo    _requestRepository.Request
+	moderationRepo _moderationRepository.Moderation
+	screener       Screener
 }
 
-func New(book _bookRepository.Book, user _userRepository.User) *ReviewUseCase {
-	return &ReviewUseCase{bookRepo: book, userRepo: user}
+func New(book _bookRepository.Book, user _userRepository.User, request _requestRepository.Request, moderation _moderationRepository.Moderation, screener Screener) *ReviewUseCase {
+	return &ReviewUseCase{bookRepo: book, userRepo: user, requestRepo: request, moderationRepo: moderation, screener: screener}
+}
+
+var reportReasons = map[string]interface{}{
+	"spam":      nil,
+	"offensive": nil,
+	"spoiler":   nil,
+	"off_topic": nil,
+	"other":     nil,
+}
+
+var moderationActions = map[string]interface{}{
+	"approve": nil,
+	"hide":    nil,
+	"delete":  nil,
+	"warn":    nil,
 }
 
 func (ruc ReviewUseCase) GetAllReviews() (res _model.GetAllReviewsResponse, code int, message string) {
@@ -89,6 +112,15 @@ func (ruc ReviewUseCase) CreateReview(userId uint, bookId uint, req _model.Creat
 		return
 	}
 
+	// screen review content
+	screening := ruc.screener.Screen(content)
+
+	if screening.Verdict == Rejected {
+		log.Println("review rejected by screening")
+		code, message = http.StatusBadRequest, strings.Join(screening.Reasons, ", ")
+		return
+	}
+
 	// check reviewer existence
 	user, err := ruc.userRepo.GetUserById(userId)
 
@@ -117,8 +149,29 @@ func (ruc ReviewUseCase) CreateReview(userId uint, bookId uint, req _model.Creat
 		return
 	}
 
-	// check if review already made
-	reviews, err := ruc.bookRepo.GetAllReviewsByBookId(bookId)
+	// check if reviewer has borrowed and returned the book
+	borrowed, err := ruc.requestRepo.HasReturnedBook(userId, bookId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	config, err := _config.GetConfig()
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if config.Review.BorrowersOnly && !borrowed {
+		log.Println("reviewer never borrowed the book")
+		code, message = http.StatusForbidden, "only member who has borrowed this book can review it"
+		return
+	}
+
+	// check if review already made, hidden review included
+	review, err := ruc.bookRepo.GetReviewByUserId(userId, bookId)
 
 	// detect failure in repository
 	if err != nil {
@@ -126,13 +179,11 @@ func (ruc ReviewUseCase) CreateReview(userId uint, bookId uint, req _model.Creat
 		return
 	}
 
-	for _, review := range reviews {
-		// member can only make 1 review per book
-		if review.User.Id == userId {
-			log.Println("review already exist")
-			code, message = http.StatusConflict, "review already exist"
-			return
-		}
+	// member can only make 1 review per book
+	if review.Id != 0 {
+		log.Println("review already exist")
+		code, message = http.StatusConflict, "review already exist"
+		return
 	}
 
 	// prepare input to repository
@@ -142,11 +193,26 @@ func (ruc ReviewUseCase) CreateReview(userId uint, bookId uint, req _model.Creat
 	newReview.Book.Id = bookId
 	newReview.Content = content
 	newReview.Star = req.Star
+	newReview.VerifiedBorrower = borrowed
+	newReview.Status = "published"
 	newReview.CreatedAt = now
 	newReview.UpdatedAt = now
 
+	// suspicious review waits for librarian instead of being published
+	if screening.Verdict == Suspicious {
+		newReview.Status = "pending"
+		newReview.ScreeningReason = strings.Join(screening.Reasons, ", ")
+	}
+
+	// record review creation as event
+	event := _entity.Event{}
+	event.Type = "review.created"
+	event.AggregateType = "review"
+	event.UserId = userId
+	event.Payload = map[string]interface{}{"book_id": bookId, "star": newReview.Star, "status": newReview.Status}
+
 	// calling repository
-	res.Review, err = ruc.bookRepo.CreateReview(newReview)
+	res.Review, err = ruc.bookRepo.CreateReview(newReview, event)
 
 	// detect failure in repository
 	if err != nil {
@@ -165,6 +231,10 @@ func (ruc ReviewUseCase) CreateReview(userId uint, bookId uint, req _model.Creat
 	res.Review.UpdatedAt, _ = _helper.TimeFormatter(res.Review.UpdatedAt)
 	code, message = http.StatusCreated, "success create review"
 
+	if res.Review.Status == "pending" {
+		message = "review is pending moderation"
+	}
+
 	return
 }
 
@@ -200,7 +270,8 @@ func (ruc ReviewUseCase) GetReviewByReviewId(bookId uint, reviewId uint) (res _m
 		return
 	}
 
-	if review.Content == "" {
+	// pending and hidden review is not public
+	if review.Content == "" || review.Status != "published" {
 		log.Println("review not found")
 		code, message = http.StatusNotFound, "review not found"
 		return
@@ -219,7 +290,118 @@ func (ruc ReviewUseCase) GetReviewByReviewId(bookId uint, reviewId uint) (res _m
 	return
 }
 
-func (ruc ReviewUseCase) GetAllReviewsByBookId(bookId uint) (res _model.GetAllReviewsByBookIdResponse, code int, message string) {
+// ParseReviewParams reads page, records and sorting of a review listing, the same for reviews of a book and of a work
+func ParseReviewParams(query url.Values) (params _model.GetAllReviewsByBookIdRequest, code int, message string) {
+	// default parameters
+	params.Page = 1
+	params.Records = 10
+	params.SortBy = "newest"
+
+	if value, exist := query["page"]; exist {
+		page, err := strconv.Atoi(value[0])
+
+		if err != nil {
+			log.Println(err)
+			code, message = http.StatusBadRequest, "invalid page"
+			return
+		}
+
+		if page < 1 {
+			log.Println("invalid page")
+			code, message = http.StatusBadRequest, "invalid page"
+			return
+		}
+
+		params.Page = page
+	}
+
+	mapRecords := map[int]interface{}{10: nil, 20: nil, 50: nil}
+
+	if value, exist := query["records"]; exist {
+		records, err := strconv.Atoi(value[0])
+
+		if err != nil {
+			log.Println(err)
+			code, message = http.StatusBadRequest, "invalid number of records"
+			return
+		}
+
+		if _, exist := mapRecords[records]; !exist {
+			log.Println("unaccepted number of records")
+			code, message = http.StatusBadRequest, "unaccepted number of records"
+			return
+		}
+
+		params.Records = records
+	}
+
+	if value, exist := query["sort"]; exist {
+		if _, exist := _bookRepository.ReviewOrders[value[0]]; !exist {
+			log.Println("unaccepted sorting criteria")
+			code, message = http.StatusBadRequest, "unaccepted sorting criteria"
+			return
+		}
+
+		params.SortBy = value[0]
+	}
+
+	return
+}
+
+// FormatReviews attaches reviewer and replies to every review, users of the whole page are read at once
+func FormatReviews(reviews []_entity.Review, bookRepo _bookRepository.Book, userRepo _userRepository.User) (err error) {
+	userIds := []uint{}
+
+	for i := range reviews {
+		// get replies
+		reviews[i].Replies, err = bookRepo.GetRepliesByReviewId(reviews[i].Id)
+
+		if err != nil {
+			return
+		}
+
+		userIds = append(userIds, reviews[i].User.Id)
+
+		for _, reply := range reviews[i].Replies {
+			userIds = append(userIds, reply.User.Id)
+		}
+	}
+
+	// get reviewers and repliers
+	users, err := userRepo.GetUsersByIds(userIds)
+
+	if err != nil {
+		return
+	}
+
+	for i := range reviews {
+		for j := range reviews[i].Replies {
+			reviews[i].Replies[j].User = users[reviews[i].Replies[j].User.Id]
+			reviews[i].Replies[j].User.Password = ""
+			reviews[i].Replies[j].User.CreatedAt, _ = _helper.TimeFormatter(reviews[i].Replies[j].User.CreatedAt)
+			reviews[i].Replies[j].User.UpdatedAt, _ = _helper.TimeFormatter(reviews[i].Replies[j].User.UpdatedAt)
+			reviews[i].Replies[j].CreatedAt, _ = _helper.TimeFormatter(reviews[i].Replies[j].CreatedAt)
+			reviews[i].Replies[j].UpdatedAt, _ = _helper.TimeFormatter(reviews[i].Replies[j].UpdatedAt)
+		}
+
+		reviews[i].User = users[reviews[i].User.Id]
+		reviews[i].User.Password = ""
+		reviews[i].User.CreatedAt, _ = _helper.TimeFormatter(reviews[i].User.CreatedAt)
+		reviews[i].User.UpdatedAt, _ = _helper.TimeFormatter(reviews[i].User.UpdatedAt)
+		reviews[i].CreatedAt, _ = _helper.TimeFormatter(reviews[i].CreatedAt)
+		reviews[i].UpdatedAt, _ = _helper.TimeFormatter(reviews[i].UpdatedAt)
+	}
+
+	return
+}
+
+func (ruc ReviewUseCase) GetAllReviewsByBookId(bookId uint, query url.Values) (res _model.GetAllReviewsByBookIdResponse, code int, message string) {
+	params, code, message := ParseReviewParams(query)
+
+	if code != 0 {
+		return
+	}
+
 	// check book existence
 	book, err := ruc.bookRepo.GetBookById(bookId)
 
@@ -236,7 +418,7 @@ func (ruc ReviewUseCase) GetAllReviewsByBookId(bookId uint) (res _model.GetAllRe
 	}
 
 	// calling repository
-	reviews, err := ruc.bookRepo.GetAllReviewsByBookId(bookId)
+	reviews, err := ruc.bookRepo.GetAllReviewsByBookId(bookId, params)
 
 	// detect failure in repository
 	if err != nil {
@@ -244,20 +426,21 @@ func (ruc ReviewUseCase) GetAllReviewsByBookId(bookId uint) (res _model.GetAllRe
 		return
 	}
 
-	for _, review := range reviews {
-		// get reviewer
-		review.User, err = ruc.userRepo.GetUserById(review.User.Id)
+	if err = FormatReviews(reviews, ruc.bookRepo, ruc.userRepo); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
 
-		if err != nil {
-			code, message = http.StatusInternalServerError, "internal server error"
-			return
-		}
+	// rating histogram covers all published reviews, not only current page
+	res.Histogram, err = ruc.bookRepo.CountReviewsByStar(bookId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
 
-		review.User.Password = ""
-		review.User.CreatedAt, _ = _helper.TimeFormatter(review.User.CreatedAt)
-		review.User.UpdatedAt, _ = _helper.TimeFormatter(review.User.UpdatedAt)
-		review.CreatedAt, _ = _helper.TimeFormatter(review.CreatedAt)
-		review.UpdatedAt, _ = _helper.TimeFormatter(review.UpdatedAt)
+	for _, count := range res.Histogram {
+		res.Count += count
 	}
 
 	res.Reviews = reviews
@@ -301,6 +484,26 @@ func (ruc ReviewUseCase) UpdateReview(userId uint, bookId uint, reviewId uint, r
 	}
 
 	if content != "" && content != review.Content {
+		// screen updated content
+		screening := ruc.screener.Screen(content)
+
+		if screening.Verdict == Rejected {
+			log.Println("review rejected by screening")
+			code, message = http.StatusBadRequest, strings.Join(screening.Reasons, ", ")
+			return
+		}
+
+		// review hidden by librarian stays hidden
+		if review.Status != "hidden" {
+			review.Status = "published"
+			review.ScreeningReason = ""
+
+			if screening.Verdict == Suspicious {
+				review.Status = "pending"
+				review.ScreeningReason = strings.Join(screening.Reasons, ", ")
+			}
+		}
+
 		review.Content = content
 		flag = false
 	}
@@ -360,6 +563,10 @@ func (ruc ReviewUseCase) UpdateReview(userId uint, bookId uint, reviewId uint, r
 	res.Review.UpdatedAt, _ = _helper.TimeFormatter(res.Review.UpdatedAt)
 	code, message = http.StatusOK, "success update review"
 
+	if res.Review.Status == "pending" {
+		message = "review is pending moderation"
+	}
+
 	return
 }
 
@@ -389,8 +596,19 @@ func (ruc ReviewUseCase) UpdateStatus(bookId uint, reviewId uint, req _model.Upd
 		return
 	}
 
+	// record moderation outcome as event
+	event := _entity.Event{}
+	event.Type = "review.flagged"
+	event.AggregateType = "review"
+	event.UserId = review.User.Id
+	event.Payload = map[string]interface{}{"book_id": bookId}
+
+	if flag == 0 {
+		event.Type = "review.unflagged"
+	}
+
 	// calling repository
-	if err = ruc.bookRepo.UpdateReviewStatus(flag, reviewId); err != nil {
+	if err = ruc.bookRepo.UpdateReviewStatus(flag, reviewId, event); err != nil {
 		code, message = http.StatusInternalServerError, "internal server error"
 		return
 	}
@@ -431,3 +649,794 @@ func (ruc ReviewUseCase) DeleteReview(userId uint, bookId uint, reviewId uint) (
 
 	return
 }
+
+func (ruc ReviewUseCase) ReportReview(userId uint, bookId uint, reviewId uint, req _model.ReportReviewRequest) (res _model.ReportReviewResponse, code int, message string) {
+	// prepare input string
+	reason := strings.ToLower(strings.TrimSpace(req.Reason))
+	note := strings.TrimSpace(req.Note)
+
+	if _, exist := reportReasons[reason]; !exist {
+		log.Println("invalid reason")
+		code, message = http.StatusBadRequest, "reason must be one of spam, offensive, spoiler, off_topic or other"
+		return
+	}
+
+	// reason other must be explained
+	if reason == "other" && note == "" {
+		log.Println("empty note")
+		code, message = http.StatusBadRequest, "note is required for reason other"
+		return
+	}
+
+	if len(note) > 500 {
+		log.Println("note too long")
+		code, message = http.StatusBadRequest, "note must be at most 500 characters"
+		return
+	}
+
+	// check review existence
+	review, err := ruc.bookRepo.GetReviewByReviewId(reviewId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// deleted, pending and hidden review cannot be reported
+	if review.Book.Id != bookId || review.Status != "published" {
+		log.Println("this book has no such review")
+		code, message = http.StatusNotFound, "this book has no such review"
+		return
+	}
+
+	// member cannot report own review
+	if review.User.Id == userId {
+		log.Println("forbidden")
+		code, message = http.StatusForbidden, "cannot report own review"
+		return
+	}
+
+	// check if member has reported the review
+	report, err := ruc.moderationRepo.GetOpenReportByUserId(userId, reviewId, 0)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if report.Id != 0 {
+		log.Println("report already exist")
+		code, message = http.StatusConflict, "review already reported"
+		return
+	}
+
+	// prepare input to repository
+	newReport := _entity.ReviewReport{}
+	newReport.ReviewId = reviewId
+	newReport.User.Id = userId
+	newReport.Reason = reason
+	newReport.Note = note
+	newReport.CreatedAt = time.Now()
+
+	// record report as event
+	event := _entity.Event{}
+	event.Type = "review.reported"
+	event.AggregateType = "review"
+	event.UserId = userId
+	event.Payload = map[string]interface{}{"book_id": bookId, "reason": reason}
+
+	// calling repository
+	res.Report, err = ruc.moderationRepo.CreateReport(newReport, event)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// formatting response
+	res.Report.CreatedAt, _ = _helper.TimeFormatter(res.Report.CreatedAt)
+	code, message = http.StatusCreated, "success report review"
+
+	return
+}
+
+func (ruc ReviewUseCase) GetModerationQueue() (res _model.GetModerationQueueResponse, code int, message string) {
+	// calling repository
+	items, err := ruc.moderationRepo.GetModerationQueue()
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	for i := range items {
+		// get reports
+		items[i].Reports, err = ruc.moderationRepo.GetOpenReportsByReviewId(items[i].Review.Id)
+
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		// get reviewer
+		items[i].Review.User, err = ruc.userRepo.GetUserById(items[i].Review.User.Id)
+
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		// get book
+		items[i].Review.Book, err = ruc.bookRepo.GetBookById(items[i].Review.Book.Id)
+
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		// formatting response
+		for j := range items[i].Reports {
+			items[i].Reports[j].CreatedAt, _ = _helper.TimeFormatter(items[i].Reports[j].CreatedAt)
+		}
+
+		items[i].Review.User.Password = ""
+		items[i].Review.User.CreatedAt, _ = _helper.TimeFormatter(items[i].Review.User.CreatedAt)
+		items[i].Review.User.UpdatedAt, _ = _helper.TimeFormatter(items[i].Review.User.UpdatedAt)
+		items[i].Review.Book.CreatedAt, _ = _helper.TimeFormatter(items[i].Review.Book.CreatedAt)
+		items[i].Review.Book.UpdatedAt, _ = _helper.TimeFormatter(items[i].Review.Book.UpdatedAt)
+		items[i].Review.CreatedAt, _ = _helper.TimeFormatter(items[i].Review.CreatedAt)
+		items[i].Review.UpdatedAt, _ = _helper.TimeFormatter(items[i].Review.UpdatedAt)
+		items[i].LastReportedAt, _ = _helper.TimeFormatter(items[i].LastReportedAt)
+	}
+
+	res.Reviews = items
+	code, message = http.StatusOK, "success get moderation queue"
+
+	return
+}
+
+func (ruc ReviewUseCase) ModerateReview(librarianId uint, reviewId uint, req _model.ModerateReviewRequest) (res _model.ModerateReviewResponse, code int, message string) {
+	// prepare input string
+	action := strings.ToLower(strings.TrimSpace(req.Action))
+	note := strings.TrimSpace(req.Note)
+
+	if _, exist := moderationActions[action]; !exist {
+		log.Println("invalid action")
+		code, message = http.StatusBadRequest, "action must be one of approve, hide, delete or warn"
+		return
+	}
+
+	// reviewer must be told what to fix when warned
+	if action == "warn" && note == "" {
+		log.Println("empty note")
+		code, message = http.StatusBadRequest, "note is required for warn action"
+		return
+	}
+
+	if len(note) > 500 {
+		log.Println("note too long")
+		code, message = http.StatusBadRequest, "note must be at most 500 characters"
+		return
+	}
+
+	// check review existence
+	review, err := ruc.bookRepo.GetReviewByReviewId(reviewId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if review.Content == "" {
+		log.Println("review not found")
+		code, message = http.StatusNotFound, "review not found"
+		return
+	}
+
+	// prepare input to repository
+	newAction := _entity.ModerationAction{}
+	newAction.ReviewId = reviewId
+	newAction.Librarian.Id = librarianId
+	newAction.Action = action
+	newAction.Note = note
+	newAction.CreatedAt = time.Now()
+
+	// record moderation as event
+	event := _entity.Event{}
+	event.Type = "review.moderated"
+	event.AggregateType = "review"
+	event.UserId = review.User.Id
+	event.Payload = map[string]interface{}{"book_id": review.Book.Id, "action": action, "note": note}
+
+	// calling repository
+	res.Action, err = ruc.moderationRepo.CreateAction(newAction, event)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// formatting response
+	res.Action.CreatedAt, _ = _helper.TimeFormatter(res.Action.CreatedAt)
+	code, message = http.StatusCreated, "success moderate review"
+
+	return
+}
+
+func (ruc ReviewUseCase) VoteReview(userId uint, bookId uint, reviewId uint, req _model.VoteReviewRequest) (res _model.VoteReviewResponse, code int, message string) {
+	// check if required input is empty
+	if req.Helpful == nil {
+		log.Println("empty input")
+		code, message = http.StatusBadRequest, "helpful must be true or false"
+		return
+	}
+
+	// check review existence
+	review, err := ruc.bookRepo.GetReviewByReviewId(reviewId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if review.Book.Id != bookId || review.Status != "published" {
+		log.Println("this book has no such review")
+		code, message = http.StatusNotFound, "this book has no such review"
+		return
+	}
+
+	// member cannot vote own review
+	if review.User.Id == userId {
+		log.Println("forbidden")
+		code, message = http.StatusForbidden, "cannot vote own review"
+		return
+	}
+
+	// prepare input to repository, vote made before is replaced
+	now := time.Now()
+	newVote := _entity.ReviewVote{}
+	newVote.ReviewId = reviewId
+	newVote.UserId = userId
+	newVote.Helpful = *req.Helpful
+	newVote.CreatedAt = now
+	newVote.UpdatedAt = now
+
+	// calling repository
+	res.Vote, err = ruc.bookRepo.VoteReview(newVote)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	res.HelpfulCount, res.UnhelpfulCount, err = ruc.bookRepo.CountVotesByReviewId(reviewId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// formatting response
+	res.Vote.CreatedAt, _ = _helper.TimeFormatter(res.Vote.CreatedAt)
+	res.Vote.UpdatedAt, _ = _helper.TimeFormatter(res.Vote.UpdatedAt)
+	code, message = http.StatusOK, "success vote review"
+
+	return
+}
+
+func (ruc ReviewUseCase) RemoveVote(userId uint, bookId uint, reviewId uint) (code int, message string) {
+	// check review existence
+	review, err := ruc.bookRepo.GetReviewByReviewId(reviewId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if review.Book.Id != bookId {
+		log.Println("this book has no such review")
+		code, message = http.StatusNotFound, "this book has no such review"
+		return
+	}
+
+	// calling repository
+	if err = ruc.bookRepo.RemoveReviewVote(userId, reviewId); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	code, message = http.StatusOK, "success remove vote"
+
+	return
+}
+
+func (ruc ReviewUseCase) getPublishedReview(bookId uint, reviewId uint) (review _entity.SimplifiedReview, code int, message string) {
+	// check review existence
+	review, err := ruc.bookRepo.GetReviewByReviewId(reviewId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if review.Content == "" || review.Book.Id != bookId || review.Status != "published" {
+		log.Println("this book has no such review")
+		code, message = http.StatusNotFound, "this book has no such review"
+		return
+	}
+
+	return
+}
+
+func (ruc ReviewUseCase) CreateReply(userId uint, bookId uint, reviewId uint, req _model.CreateReplyRequest) (res _model.CreateReplyResponse, code int, message string) {
+	// prepare input string
+	content := strings.TrimSpace(req.Content)
+
+	// check if required input is empty
+	if content == "" {
+		log.Println("empty input")
+		code, message = http.StatusBadRequest, "empty input"
+		return
+	}
+
+	// screen reply content
+	screening := ruc.screener.Screen(content)
+
+	if screening.Verdict == Rejected {
+		log.Println("reply rejected by screening")
+		code, message = http.StatusBadRequest, strings.Join(screening.Reasons, ", ")
+		return
+	}
+
+	// only published review can be replied
+	review, code, message := ruc.getPublishedReview(bookId, reviewId)
+
+	if code != 0 {
+		return
+	}
+
+	// check author existence
+	user, err := ruc.userRepo.GetUserById(userId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if user.Name == "" {
+		log.Println("user not found")
+		code, message = http.StatusNotFound, "user not found"
+		return
+	}
+
+	// prepare input to repository
+	now := time.Now()
+	newReply := _entity.ReviewReply{}
+	newReply.ReviewId = reviewId
+	newReply.User.Id = userId
+	newReply.Content = content
+	newReply.Status = "published"
+	newReply.CreatedAt = now
+	newReply.UpdatedAt = now
+
+	// suspicious reply waits for librarian instead of being published
+	if screening.Verdict == Suspicious {
+		newReply.Status = "pending"
+		newReply.ScreeningReason = strings.Join(screening.Reasons, ", ")
+	}
+
+	// reviewer is only told about published reply
+	events := []_entity.Event{}
+
+	if newReply.Status == "published" {
+		event := _entity.Event{}
+		event.Type = "review.replied"
+		event.AggregateType = "review"
+		event.UserId = userId
+		event.Payload = map[string]interface{}{"book_id": bookId, "reviewer_id": review.User.Id, "role": user.Role}
+		events = append(events, event)
+	}
+
+	// calling repository
+	res.Reply, err = ruc.bookRepo.CreateReply(newReply, events...)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// formatting response
+	res.Reply.User = user
+	res.Reply.User.Password = ""
+	res.Reply.User.CreatedAt, _ = _helper.TimeFormatter(res.Reply.User.CreatedAt)
+	res.Reply.User.UpdatedAt, _ = _helper.TimeFormatter(res.Reply.User.UpdatedAt)
+	res.Reply.CreatedAt, _ = _helper.TimeFormatter(res.Reply.CreatedAt)
+	res.Reply.UpdatedAt, _ = _helper.TimeFormatter(res.Reply.UpdatedAt)
+	code, message = http.StatusCreated, "success create reply"
+
+	if res.Reply.Status == "pending" {
+		message = "reply is pending moderation"
+	}
+
+	return
+}
+
+func (ruc ReviewUseCase) UpdateReply(userId uint, bookId uint, reviewId uint, replyId uint, req _model.UpdateReplyRequest) (res _model.UpdateReplyResponse, code int, message string) {
+	// check review existence
+	review, err := ruc.bookRepo.GetReviewByReviewId(reviewId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if review.Book.Id != bookId {
+		log.Println("this book has no such review")
+		code, message = http.StatusNotFound, "this book has no such review"
+		return
+	}
+
+	// check reply existence
+	reply, err := ruc.bookRepo.GetReplyById(replyId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if reply.ReviewId != reviewId {
+		log.Println("this review has no such reply")
+		code, message = http.StatusNotFound, "this review has no such reply"
+		return
+	}
+
+	// check if author id not match
+	if reply.User.Id != userId {
+		log.Println("forbidden")
+		code, message = http.StatusForbidden, "forbidden"
+		return
+	}
+
+	// prepare input string
+	content := strings.TrimSpace(req.Content)
+
+	if content == "" || content == reply.Content {
+		log.Println("no update was performed")
+		code, message = http.StatusBadRequest, "no update was performed"
+		return
+	}
+
+	// screen updated content
+	screening := ruc.screener.Screen(content)
+
+	if screening.Verdict == Rejected {
+		log.Println("reply rejected by screening")
+		code, message = http.StatusBadRequest, strings.Join(screening.Reasons, ", ")
+		return
+	}
+
+	// reply hidden by librarian stays hidden
+	if reply.Status != "hidden" {
+		reply.Status = "published"
+		reply.ScreeningReason = ""
+
+		if screening.Verdict == Suspicious {
+			reply.Status = "pending"
+			reply.ScreeningReason = strings.Join(screening.Reasons, ", ")
+		}
+	}
+
+	reply.Content = content
+	reply.UpdatedAt = time.Now()
+
+	// calling repository
+	res.Reply, err = ruc.bookRepo.UpdateReply(reply)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// get author
+	res.Reply.User, err = ruc.userRepo.GetUserById(userId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// formatting response
+	res.Reply.User.Password = ""
+	res.Reply.User.CreatedAt, _ = _helper.TimeFormatter(res.Reply.User.CreatedAt)
+	res.Reply.User.UpdatedAt, _ = _helper.TimeFormatter(res.Reply.User.UpdatedAt)
+	res.Reply.CreatedAt, _ = _helper.TimeFormatter(res.Reply.CreatedAt)
+	res.Reply.UpdatedAt, _ = _helper.TimeFormatter(res.Reply.UpdatedAt)
+	code, message = http.StatusOK, "success update reply"
+
+	if res.Reply.Status == "pending" {
+		message = "reply is pending moderation"
+	}
+
+	return
+}
+
+func (ruc ReviewUseCase) DeleteReply(userId uint, bookId uint, reviewId uint, replyId uint) (code int, message string) {
+	// check review existence
+	review, err := ruc.bookRepo.GetReviewByReviewId(reviewId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if review.Book.Id != bookId {
+		log.Println("this book has no such review")
+		code, message = http.StatusNotFound, "this book has no such review"
+		return
+	}
+
+	// check reply existence
+	reply, err := ruc.bookRepo.GetReplyById(replyId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if reply.ReviewId != reviewId {
+		log.Println("this review has no such reply")
+		code, message = http.StatusNotFound, "this review has no such reply"
+		return
+	}
+
+	// check if author id not match
+	if reply.User.Id != userId {
+		log.Println("forbidden")
+		code, message = http.StatusForbidden, "forbidden"
+		return
+	}
+
+	if err = ruc.bookRepo.DeleteReply(replyId); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	code, message = http.StatusOK, "success delete reply"
+
+	return
+}
+
+func (ruc ReviewUseCase) ReportReply(userId uint, bookId uint, reviewId uint, replyId uint, req _model.ReportReviewRequest) (res _model.ReportReviewResponse, code int, message string) {
+	// prepare input string
+	reason := strings.ToLower(strings.TrimSpace(req.Reason))
+	note := strings.TrimSpace(req.Note)
+
+	if _, exist := reportReasons[reason]; !exist {
+		log.Println("invalid reason")
+		code, message = http.StatusBadRequest, "reason must be one of spam, offensive, spoiler, off_topic or other"
+		return
+	}
+
+	// reason other must be explained
+	if reason == "other" && note == "" {
+		log.Println("empty note")
+		code, message = http.StatusBadRequest, "note is required for reason other"
+		return
+	}
+
+	if len(note) > 500 {
+		log.Println("note too long")
+		code, message = http.StatusBadRequest, "note must be at most 500 characters"
+		return
+	}
+
+	// check review existence
+	review, err := ruc.bookRepo.GetReviewByReviewId(reviewId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if review.Book.Id != bookId {
+		log.Println("this book has no such review")
+		code, message = http.StatusNotFound, "this book has no such review"
+		return
+	}
+
+	// check reply existence
+	reply, err := ruc.bookRepo.GetReplyById(replyId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if reply.ReviewId != reviewId {
+		log.Println("this review has no such reply")
+		code, message = http.StatusNotFound, "this review has no such reply"
+		return
+	}
+
+	// member cannot report own reply
+	if reply.User.Id == userId {
+		log.Println("forbidden")
+		code, message = http.StatusForbidden, "cannot report own reply"
+		return
+	}
+
+	// check if member has reported the reply
+	report, err := ruc.moderationRepo.GetOpenReportByUserId(userId, reviewId, replyId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if report.Id != 0 {
+		log.Println("report already exist")
+		code, message = http.StatusConflict, "reply already reported"
+		return
+	}
+
+	// prepare input to repository
+	newReport := _entity.ReviewReport{}
+	newReport.ReviewId = reviewId
+	newReport.ReplyId = replyId
+	newReport.User.Id = userId
+	newReport.Reason = reason
+	newReport.Note = note
+	newReport.CreatedAt = time.Now()
+
+	// record report as event
+	event := _entity.Event{}
+	event.Type = "review.reported"
+	event.AggregateType = "review"
+	event.UserId = userId
+	event.Payload = map[string]interface{}{"book_id": bookId, "reply_id": replyId, "reason": reason}
+
+	// calling repository
+	res.Report, err = ruc.moderationRepo.CreateReport(newReport, event)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// formatting response
+	res.Report.CreatedAt, _ = _helper.TimeFormatter(res.Report.CreatedAt)
+	code, message = http.StatusCreated, "success report reply"
+
+	return
+}
+
+func (ruc ReviewUseCase) GetReplyModerationQueue() (res _model.GetReplyModerationQueueResponse, code int, message string) {
+	// calling repository
+	items, err := ruc.moderationRepo.GetReplyModerationQueue()
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	for i := range items {
+		// get reports
+		items[i].Reports, err = ruc.moderationRepo.GetOpenReportsByReplyId(items[i].Reply.Id)
+
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		// get author
+		items[i].Reply.User, err = ruc.userRepo.GetUserById(items[i].Reply.User.Id)
+
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		// formatting response
+		for j := range items[i].Reports {
+			items[i].Reports[j].CreatedAt, _ = _helper.TimeFormatter(items[i].Reports[j].CreatedAt)
+		}
+
+		items[i].Reply.User.Password = ""
+		items[i].Reply.User.CreatedAt, _ = _helper.TimeFormatter(items[i].Reply.User.CreatedAt)
+		items[i].Reply.User.UpdatedAt, _ = _helper.TimeFormatter(items[i].Reply.User.UpdatedAt)
+		items[i].Reply.CreatedAt, _ = _helper.TimeFormatter(items[i].Reply.CreatedAt)
+		items[i].Reply.UpdatedAt, _ = _helper.TimeFormatter(items[i].Reply.UpdatedAt)
+		items[i].LastReportedAt, _ = _helper.TimeFormatter(items[i].LastReportedAt)
+	}
+
+	res.Replies = items
+	code, message = http.StatusOK, "success get reply moderation queue"
+
+	return
+}
+
+func (ruc ReviewUseCase) ModerateReply(librarianId uint, replyId uint, req _model.ModerateReviewRequest) (res _model.ModerateReviewResponse, code int, message string) {
+	// prepare input string
+	action := strings.ToLower(strings.TrimSpace(req.Action))
+	note := strings.TrimSpace(req.Note)
+
+	if _, exist := moderationActions[action]; !exist {
+		log.Println("invalid action")
+		code, message = http.StatusBadRequest, "action must be one of approve, hide, delete or warn"
+		return
+	}
+
+	// author must be told what to fix when warned
+	if action == "warn" && note == "" {
+		log.Println("empty note")
+		code, message = http.StatusBadRequest, "note is required for warn action"
+		return
+	}
+
+	if len(note) > 500 {
+		log.Println("note too long")
+		code, message = http.StatusBadRequest, "note must be at most 500 characters"
+		return
+	}
+
+	// check reply existence
+	reply, err := ruc.bookRepo.GetReplyById(replyId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if reply.Content == "" {
+		log.Println("reply not found")
+		code, message = http.StatusNotFound, "reply not found"
+		return
+	}
+
+	// get replied review
+	review, err := ruc.bookRepo.GetReviewByReviewId(reply.ReviewId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// prepare input to repository
+	newAction := _entity.ModerationAction{}
+	newAction.ReviewId = reply.ReviewId
+	newAction.ReplyId = replyId
+	newAction.Librarian.Id = librarianId
+	newAction.Action = action
+	newAction.Note = note
+	newAction.CreatedAt = time.Now()
+
+	// record moderation as event
+	event := _entity.Event{}
+	event.Type = "reply.moderated"
+	event.AggregateType = "review"
+	event.UserId = reply.User.Id
+	event.Payload = map[string]interface{}{"book_id": review.Book.Id, "reply_id": replyId, "action": action, "note": note}
+
+	// calling repository
+	res.Action, err = ruc.moderationRepo.CreateAction(newAction, event)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// formatting response
+	res.Action.CreatedAt, _ = _helper.TimeFormatter(res.Action.CreatedAt)
+	code, message = http.StatusCreated, "success moderate reply"
+
+	return
+}
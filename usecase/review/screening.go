@@ -0,0 +1,260 @@
+package review
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// screening verdicts, ordered from the least to the most severe
+const (
+	Pass = iota
+	Suspicious
+	Rejected
+)
+
+type Result struct {
+	Verdict int
+	Reasons []string
+}
+
+type Screener interface {
+	Screen(content string) (result Result)
+}
+
+// pipeline runs every screener, the most severe verdict wins and all reasons are kept
+type Pipeline []Screener
+
+func NewPipeline(screeners ...Screener) Pipeline {
+	return Pipeline(screeners)
+}
+
+func (p Pipeline) Screen(content string) (result Result) {
+	for _, screener := range p {
+		res := screener.Screen(content)
+
+		if res.Verdict > result.Verdict {
+			result.Verdict = res.Verdict
+		}
+
+		result.Reasons = append(result.Reasons, res.Reasons...)
+	}
+
+	return
+}
+
+// length screener rejects review which is too short or too long
+type LengthScreener struct {
+	min uint
+	max uint
+}
+
+func NewLengthScreener(min uint, max uint) *LengthScreener {
+	return &LengthScreener{min: min, max: max}
+}
+
+func (ls LengthScreener) Screen(content string) (result Result) {
+	length := uint(utf8.RuneCountInString(content))
+
+	if length < ls.min || length > ls.max {
+		result.Verdict = Rejected
+		result.Reasons = append(result.Reasons, fmt.Sprintf("review must be from %d to %d characters", ls.min, ls.max))
+	}
+
+	return
+}
+
+// default blocked words, english and bahasa indonesia
+var DefaultWordList = []string{
+	// english
+	"asshole", "bastard", "bitch", "bullshit", "cunt", "dickhead", "fuck", "fucking", "motherfucker", "shit", "slut", "whore",
+	// bahasa indonesia
+	"bajingan", "bangsat", "brengsek", "goblok", "jancuk", "kampret", "keparat", "kontol", "memek", "ngentot", "tolol",
+}
+
+// common character substitution used to dodge word filter
+var leetReplacer = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s")
+
+// word list screener holds review containing blocked word or phrase
+type WordListScreener struct {
+	words []string
+}
+
+func NewWordListScreener(words ...string) *WordListScreener {
+	ws := &WordListScreener{}
+
+	for _, word := range words {
+		if word = normalizeText(word); word != "" {
+			ws.words = append(ws.words, word)
+		}
+	}
+
+	return ws
+}
+
+// LoadWordList reads blocked words from file, one word or phrase per line, line started with # is ignored
+func LoadWordList(path string) (words []string, err error) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line != "" && !strings.HasPrefix(line, "#") {
+			words = append(words, line)
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		log.Println(err)
+	}
+
+	return
+}
+
+func (ws WordListScreener) Screen(content string) (result Result) {
+	// pad with space so that only whole word or phrase is matched
+	text := " " + normalizeText(content) + " "
+
+	for _, word := range ws.words {
+		if strings.Contains(text, " "+word+" ") {
+			result.Verdict = Suspicious
+			result.Reasons = append(result.Reasons, "contains blocked word")
+			return
+		}
+	}
+
+	return
+}
+
+// normalizeText lowercases text, undoes character substitution and collapses everything but letter into single space
+func normalizeText(text string) string {
+	text = leetReplacer.Replace(strings.ToLower(text))
+
+	return strings.Join(strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}), " ")
+}
+
+var linkPattern = regexp.MustCompile(`(?i)(https?://\S+|www\.\S+|\b[a-z0-9-]+\.(com|net|org|info|biz|xyz|io|co|id|ly|me)\b(/\S*)?)`)
+
+// link screener holds review containing more links than allowed
+type LinkScreener struct {
+	max uint
+}
+
+func NewLinkScreener(max uint) *LinkScreener {
+	return &LinkScreener{max: max}
+}
+
+func (ls LinkScreener) Screen(content string) (result Result) {
+	if links := linkPattern.FindAllString(content, -1); uint(len(links)) > ls.max {
+		result.Verdict = Suspicious
+		result.Reasons = append(result.Reasons, "contains link")
+	}
+
+	return
+}
+
+// promotional phrases, english and bahasa indonesia
+var spamPhrases = []string{
+	"buy now", "click here", "free money", "visit my", "whatsapp",
+	"beli sekarang", "klik di sini", "hubungi kami", "judi online", "slot gacor", "pinjaman online",
+}
+
+var phonePattern = regexp.MustCompile(`\+?\d[\d\s-]{8,}\d`)
+
+// spam screener holds review showing common spam pattern
+type SpamScreener struct{}
+
+func NewSpamScreener() *SpamScreener {
+	return &SpamScreener{}
+}
+
+func (ss SpamScreener) Screen(content string) (result Result) {
+	text := " " + normalizeText(content) + " "
+
+	for _, phrase := range spamPhrases {
+		if strings.Contains(text, " "+phrase+" ") {
+			result.Reasons = append(result.Reasons, "contains promotional phrase")
+			break
+		}
+	}
+
+	if phonePattern.MatchString(content) {
+		result.Reasons = append(result.Reasons, "contains phone number")
+	}
+
+	// regexp package has no backreference, so repeated character is checked by hand
+	if hasRepeatedRune(content, 6) {
+		result.Reasons = append(result.Reasons, "contains repeated character")
+	}
+
+	// shouting, mostly uppercase letters
+	letters, uppers := 0, 0
+
+	for _, r := range content {
+		if unicode.IsLetter(r) {
+			letters++
+
+			if unicode.IsUpper(r) {
+				uppers++
+			}
+		}
+	}
+
+	if letters >= 20 && uppers*10 > letters*7 {
+		result.Reasons = append(result.Reasons, "mostly uppercase")
+	}
+
+	// single word repeated over and over
+	words := strings.Fields(strings.TrimSpace(text))
+	counts := map[string]int{}
+
+	for _, word := range words {
+		counts[word]++
+
+		if len(words) >= 6 && counts[word]*2 > len(words) {
+			result.Reasons = append(result.Reasons, "repetitive content")
+			break
+		}
+	}
+
+	if len(result.Reasons) > 0 {
+		result.Verdict = Suspicious
+	}
+
+	return
+}
+
+func hasRepeatedRune(content string, limit int) bool {
+	var last rune
+	count := 0
+
+	for _, r := range content {
+		if r == last && !unicode.IsSpace(r) {
+			count++
+		} else {
+			last, count = r, 1
+		}
+
+		if count >= limit {
+			return true
+		}
+	}
+
+	return false
+}
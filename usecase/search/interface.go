@@ -0,0 +1,10 @@
+package search
+
+import (
+	_entity "plain-go/public-library/entity"
+)
+
+type Subscriber interface {
+	BackfillIndex() (err error)
+	HandleEvent(event _entity.Event) (err error)
+}
@@ -0,0 +1,45 @@
+package search
+
+import (
+	_authorRepository "plain-go/public-library/datastore/author"
+	_bookRepository "plain-go/public-library/datastore/book"
+	_entity "plain-go/public-library/entity"
+)
+
+// SearchUseCase keeps book search index in step with the catalog, it only reacts to outbox events,
+// so a failed reindex is retried by the dispatcher without failing the request that changed the book
+type SearchUseCase struct {
+	bookRepo   _bookRepository.Book
+	authorRepo _authorRepository.Author
+}
+
+func New(book _bookRepository.Book, author _authorRepository.Author) *SearchUseCase {
+	return &SearchUseCase{bookRepo: book, authorRepo: author}
+}
+
+// BackfillIndex indexes books missing from the index, so search finds them without manual rebuild
+func (suc SearchUseCase) BackfillIndex() (err error) {
+	return suc.bookRepo.BackfillSearchIndex()
+}
+
+func (suc SearchUseCase) HandleEvent(event _entity.Event) (err error) {
+	switch event.Type {
+	case "book.created", "book.updated", "book.deleted", "book.merged", "book.translated", "book.translation_deleted":
+		return suc.bookRepo.RebuildSearchIndex(event.AggregateId)
+	case "author.updated", "author.merged":
+		// author name is indexed with every book of the author
+		books, err := suc.authorRepo.GetBibliography(event.AggregateId)
+
+		if err != nil {
+			return err
+		}
+
+		for _, book := range books {
+			if err = suc.bookRepo.RebuildSearchIndex(book.Id); err != nil {
+				return err
+			}
+		}
+	}
+
+	return
+}
@@ -0,0 +1,19 @@
+package subject
+
+import (
+	"net/url"
+	_model "plain-go/public-library/model"
+)
+
+type Subject interface {
+	GetAllSubjects(query url.Values) (res _model.GetAllSubjectsResponse, code int, message string)
+	CreateSubject(req _model.CreateSubjectRequest) (res _model.CreateSubjectResponse, code int, message string)
+	SeedDewey() (res _model.SeedDeweyResponse, code int, message string)
+	GetSubjectById(subjectId uint) (res _model.GetSubjectByIdResponse, code int, message string)
+	UpdateSubject(subjectId uint, req _model.UpdateSubjectRequest) (res _model.UpdateSubjectResponse, code int, message string)
+	DeleteSubject(subjectId uint) (code int, message string)
+	GetBooksBySubject(subjectId uint, query url.Values) (res _model.GetBooksBySubjectResponse, code int, message string)
+	GetBookClassification(bookId uint) (res _model.GetBookClassificationResponse, code int, message string)
+	SetBookSubjects(bookId uint, req _model.SetBookSubjectsRequest) (res _model.GetBookClassificationResponse, code int, message string)
+	SetCallNumber(bookId uint, req _model.SetCallNumberRequest) (res _model.GetBookClassificationResponse, code int, message string)
+}
@@ -0,0 +1,844 @@
+package subject
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	_bookRepository "plain-go/public-library/datastore/book"
+	_subjectRepository "plain-go/public-library/datastore/subject"
+	_entity "plain-go/public-library/entity"
+	_helper "plain-go/public-library/helper"
+	_model "plain-go/public-library/model"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dewey subject carries a class number as code, local subject heading may have any code or none
+var schemes = map[string]interface{}{"dewey": nil, "local": nil}
+
+type SubjectUseCase struct {
+	subjectRepo _subjectRepository.Subject
+	bookRepo    _bookRepository.Book
+}
+
+func New(subject _subjectRepository.Subject, book _bookRepository.Book) *SubjectUseCase {
+	return &SubjectUseCase{subjectRepo: subject, bookRepo: book}
+}
+
+func formatSubject(subject *_entity.Subject) {
+	subject.CreatedAt, _ = _helper.TimeFormatter(subject.CreatedAt)
+	subject.UpdatedAt, _ = _helper.TimeFormatter(subject.UpdatedAt)
+}
+
+// validateSubject checks code and name against the scheme
+func validateSubject(scheme string, subjectCode string, name string) (code int, message string) {
+	// check if required input is empty
+	if name == "" || (scheme == "dewey" && subjectCode == "") {
+		log.Println("empty input")
+		code, message = http.StatusBadRequest, "empty input"
+		return
+	}
+
+	for _, s := range []string{subjectCode, name} {
+		// check if there is any forbidden character
+		if strings.Contains(strings.ReplaceAll(s, " ", ""), ";--") {
+			log.Println("forbidden character")
+			code, message = http.StatusBadRequest, "forbidden character"
+			return
+		}
+	}
+
+	if len(subjectCode) > 20 || len(name) > 255 {
+		log.Println("input too long")
+		code, message = http.StatusBadRequest, "code must be at most 20 and name at most 255 characters"
+		return
+	}
+
+	if scheme == "dewey" && !_helper.IsValidDewey(subjectCode) {
+		log.Println("invalid dewey class number")
+		code, message = http.StatusBadRequest, "invalid dewey class number"
+		return
+	}
+
+	return
+}
+
+// getAncestors returns subjects on the path from the root down to the parent
+func (suc SubjectUseCase) getAncestors(subject _entity.Subject) (ancestors []_entity.Subject, err error) {
+	for _, part := range strings.Split(strings.Trim(subject.Path, "/"), "/") {
+		id, _ := strconv.Atoi(part)
+
+		if id == 0 || uint(id) == subject.Id {
+			continue
+		}
+
+		ancestor, err := suc.subjectRepo.GetSubjectById(uint(id))
+
+		if err != nil {
+			return ancestors, err
+		}
+
+		formatSubject(&ancestor)
+		ancestors = append(ancestors, ancestor)
+	}
+
+	return
+}
+
+// deweyParent finds the nearest broader dewey class already in the tree
+func (suc SubjectUseCase) deweyParent(code string) (parent _entity.Subject, err error) {
+	for _, broader := range _helper.DeweyAncestors(code) {
+		parent, err = suc.subjectRepo.GetSubjectByCode("dewey", broader)
+
+		if err != nil || parent.Id != 0 {
+			return
+		}
+	}
+
+	return
+}
+
+func (suc SubjectUseCase) GetAllSubjects(query url.Values) (res _model.GetAllSubjectsResponse, code int, message string) {
+	params := _model.GetAllSubjectsRequest{}
+
+	if value, exist := query["scheme"]; exist {
+		if _, exist := schemes[value[0]]; !exist {
+			log.Println("unaccepted scheme")
+			code, message = http.StatusBadRequest, "scheme must be either dewey or local"
+			return
+		}
+
+		params.Scheme = value[0]
+	}
+
+	if value, exist := query["parent"]; exist {
+		parentId, err := strconv.Atoi(value[0])
+
+		if err != nil || parentId < 0 {
+			log.Println("invalid parent")
+			code, message = http.StatusBadRequest, "invalid parent"
+			return
+		}
+
+		params.ParentId = uint(parentId)
+	}
+
+	if value, exist := query["keyword"]; exist {
+		params.Keyword = strings.TrimSpace(value[0])
+	}
+
+	// calling repository
+	subjects, err := suc.subjectRepo.GetSubjects(params)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	for i := range subjects {
+		formatSubject(&subjects[i])
+	}
+
+	res.Subjects = subjects
+	code, message = http.StatusOK, "success get all subjects"
+
+	return
+}
+
+func (suc SubjectUseCase) CreateSubject(req _model.CreateSubjectRequest) (res _model.CreateSubjectResponse, code int, message string) {
+	// prepare input string
+	scheme := strings.ToLower(strings.TrimSpace(req.Scheme))
+	subjectCode := strings.TrimSpace(req.Code)
+	name := strings.TrimSpace(req.Name)
+
+	if scheme == "" {
+		scheme = "local"
+	}
+
+	if _, exist := schemes[scheme]; !exist {
+		log.Println("unaccepted scheme")
+		code, message = http.StatusBadRequest, "scheme must be either dewey or local"
+		return
+	}
+
+	if code, message = validateSubject(scheme, subjectCode, name); code != 0 {
+		return
+	}
+
+	// check code uniqueness within scheme
+	if subjectCode != "" {
+		existing, err := suc.subjectRepo.GetSubjectByCode(scheme, subjectCode)
+
+		// detect failure in repository
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		if existing.Id != 0 {
+			log.Println("subject code already exist")
+			code, message = http.StatusConflict, "subject code already exist"
+			return
+		}
+	}
+
+	// find parent, dewey class without parent is placed under its nearest broader class
+	parent := _entity.Subject{}
+	var err error
+
+	if req.ParentId != 0 {
+		parent, err = suc.subjectRepo.GetSubjectById(req.ParentId)
+
+		// detect failure in repository
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		if parent.Id == 0 {
+			log.Println("parent subject not found")
+			code, message = http.StatusNotFound, "parent subject not found"
+			return
+		}
+
+		// local heading may narrow a dewey class, but not the other way round
+		if scheme == "dewey" && parent.Scheme != "dewey" {
+			log.Println("dewey class under local subject")
+			code, message = http.StatusBadRequest, "dewey class can only be placed under another dewey class"
+			return
+		}
+	} else if scheme == "dewey" {
+		parent, err = suc.deweyParent(subjectCode)
+
+		// detect failure in repository
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+	}
+
+	// prepare input to repository
+	now := time.Now()
+	newSubject := _entity.Subject{}
+	newSubject.ParentId = parent.Id
+	newSubject.Scheme = scheme
+	newSubject.Code = subjectCode
+	newSubject.Name = name
+	newSubject.Path = "/"
+	newSubject.CreatedAt = now
+	newSubject.UpdatedAt = now
+
+	if parent.Id != 0 {
+		newSubject.Path = parent.Path
+	}
+
+	// calling repository
+	res.Subject, err = suc.subjectRepo.CreateSubject(newSubject)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	formatSubject(&res.Subject)
+	code, message = http.StatusCreated, "success create subject"
+
+	return
+}
+
+// SeedDewey creates main classes which are not in the tree yet
+func (suc SubjectUseCase) SeedDewey() (res _model.SeedDeweyResponse, code int, message string) {
+	codes := []string{}
+
+	for classNumber := range _helper.DeweyClasses {
+		codes = append(codes, classNumber)
+	}
+
+	sort.Strings(codes)
+
+	for _, classNumber := range codes {
+		// check class existence
+		existing, err := suc.subjectRepo.GetSubjectByCode("dewey", classNumber)
+
+		// detect failure in repository
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		if existing.Id != 0 {
+			continue
+		}
+
+		// prepare input to repository
+		now := time.Now()
+		newSubject := _entity.Subject{}
+		newSubject.Scheme = "dewey"
+		newSubject.Code = classNumber
+		newSubject.Name = _helper.DeweyClasses[classNumber]
+		newSubject.Path = "/"
+		newSubject.CreatedAt = now
+		newSubject.UpdatedAt = now
+
+		// calling repository
+		subject, err := suc.subjectRepo.CreateSubject(newSubject)
+
+		// detect failure in repository
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		formatSubject(&subject)
+		res.Subjects = append(res.Subjects, subject)
+	}
+
+	code, message = http.StatusOK, "success seed dewey classes"
+
+	return
+}
+
+func (suc SubjectUseCase) GetSubjectById(subjectId uint) (res _model.GetSubjectByIdResponse, code int, message string) {
+	// calling repository
+	subject, err := suc.subjectRepo.GetSubjectById(subjectId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if subject.Id == 0 {
+		log.Println("subject not found")
+		code, message = http.StatusNotFound, "subject not found"
+		return
+	}
+
+	// breadcrumb from the root
+	subject.Ancestors, err = suc.getAncestors(subject)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	subject.Children, err = suc.subjectRepo.GetSubjects(_model.GetAllSubjectsRequest{ParentId: subjectId})
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	for i := range subject.Children {
+		formatSubject(&subject.Children[i])
+	}
+
+	formatSubject(&subject)
+	res.Subject = subject
+	code, message = http.StatusOK, "success get subject"
+
+	return
+}
+
+func (suc SubjectUseCase) UpdateSubject(subjectId uint, req _model.UpdateSubjectRequest) (res _model.UpdateSubjectResponse, code int, message string) {
+	// check subject existence
+	subject, err := suc.subjectRepo.GetSubjectById(subjectId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if subject.Id == 0 {
+		log.Println("subject not found")
+		code, message = http.StatusNotFound, "subject not found"
+		return
+	}
+
+	oldPath := subject.Path
+
+	// empty field keeps its current value
+	if subjectCode := strings.TrimSpace(req.Code); subjectCode != "" && subjectCode != subject.Code {
+		existing, err := suc.subjectRepo.GetSubjectByCode(subject.Scheme, subjectCode)
+
+		// detect failure in repository
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		if existing.Id != 0 {
+			log.Println("subject code already exist")
+			code, message = http.StatusConflict, "subject code already exist"
+			return
+		}
+
+		subject.Code = subjectCode
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		subject.Name = name
+	}
+
+	if code, message = validateSubject(subject.Scheme, subject.Code, subject.Name); code != 0 {
+		return
+	}
+
+	// move subject together with its descendants
+	if req.Root {
+		subject.ParentId = 0
+		subject.Path = "/" + strconv.Itoa(int(subject.Id)) + "/"
+	} else if req.ParentId != 0 && req.ParentId != subject.ParentId {
+		parent, err := suc.subjectRepo.GetSubjectById(req.ParentId)
+
+		// detect failure in repository
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		if parent.Id == 0 {
+			log.Println("parent subject not found")
+			code, message = http.StatusNotFound, "parent subject not found"
+			return
+		}
+
+		if strings.HasPrefix(parent.Path, subject.Path) {
+			log.Println("subject moved under its own descendant")
+			code, message = http.StatusBadRequest, "subject can not be moved under itself or its descendant"
+			return
+		}
+
+		if subject.Scheme == "dewey" && parent.Scheme != "dewey" {
+			log.Println("dewey class under local subject")
+			code, message = http.StatusBadRequest, "dewey class can only be placed under another dewey class"
+			return
+		}
+
+		subject.ParentId = parent.Id
+		subject.Path = parent.Path + strconv.Itoa(int(subject.Id)) + "/"
+	}
+
+	// prepare input to repository
+	subject.UpdatedAt = time.Now()
+
+	// calling repository
+	res.Subject, err = suc.subjectRepo.UpdateSubject(subject, oldPath)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	formatSubject(&res.Subject)
+	code, message = http.StatusOK, "success update subject"
+
+	return
+}
+
+func (suc SubjectUseCase) DeleteSubject(subjectId uint) (code int, message string) {
+	// check subject existence
+	subject, err := suc.subjectRepo.GetSubjectById(subjectId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if subject.Id == 0 {
+		log.Println("subject not found")
+		code, message = http.StatusNotFound, "subject not found"
+		return
+	}
+
+	// narrower subjects must be moved or deleted first
+	if subject.ChildCount > 0 {
+		log.Println("subject still has children")
+		code, message = http.StatusConflict, "subject still has children"
+		return
+	}
+
+	// calling repository
+	if err = suc.subjectRepo.DeleteSubject(subjectId); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	code, message = http.StatusOK, "success delete subject"
+
+	return
+}
+
+func (suc SubjectUseCase) GetBooksBySubject(subjectId uint, query url.Values) (res _model.GetBooksBySubjectResponse, code int, message string) {
+	// default parameters, narrower subjects are included unless asked otherwise
+	params := _model.GetBooksBySubjectRequest{}
+	params.Page = 1
+	params.Records = 10
+	params.Descendants = true
+
+	if value, exist := query["page"]; exist {
+		page, err := strconv.Atoi(value[0])
+
+		if err != nil {
+			log.Println(err)
+			code, message = http.StatusBadRequest, "invalid page"
+			return
+		}
+
+		if page < 1 {
+			log.Println("invalid page")
+			code, message = http.StatusBadRequest, "invalid page"
+			return
+		}
+
+		params.Page = page
+	}
+
+	mapRecords := map[int]interface{}{10: nil, 20: nil, 50: nil}
+
+	if value, exist := query["records"]; exist {
+		records, err := strconv.Atoi(value[0])
+
+		if err != nil {
+			log.Println(err)
+			code, message = http.StatusBadRequest, "invalid number of records"
+			return
+		}
+
+		if _, exist := mapRecords[records]; !exist {
+			log.Println("unaccepted number of records")
+			code, message = http.StatusBadRequest, "unaccepted number of records"
+			return
+		}
+
+		params.Records = records
+	}
+
+	if value, exist := query["descendants"]; exist {
+		descendants, err := strconv.ParseBool(value[0])
+
+		if err != nil {
+			log.Println(err)
+			code, message = http.StatusBadRequest, "descendants must be true or false"
+			return
+		}
+
+		params.Descendants = descendants
+	}
+
+	// check subject existence
+	subject, err := suc.subjectRepo.GetSubjectById(subjectId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if subject.Id == 0 {
+		log.Println("subject not found")
+		code, message = http.StatusNotFound, "subject not found"
+		return
+	}
+
+	// calling repository
+	books, err := suc.subjectRepo.GetBooksBySubject(subject, params)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	res.Count, err = suc.subjectRepo.CountBooksBySubject(subject, params.Descendants)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	for i := range books {
+		// formatting response
+		books[i].CreatedAt, _ = _helper.TimeFormatter(books[i].CreatedAt)
+		books[i].UpdatedAt, _ = _helper.TimeFormatter(books[i].UpdatedAt)
+		books[i].Quantity, _ = suc.bookRepo.CountBookById(books[i].Id)
+		books[i].Author, _ = suc.bookRepo.GetBookAuthors(books[i].Id)
+		books[i].FavoriteCount, _ = suc.bookRepo.CountFavoritesByBookId(books[i].Id)
+		averageStar, _ := suc.bookRepo.CountStarsByBookId(books[i].Id)
+		books[i].AverageStar = _helper.NilHandler(averageStar)
+	}
+
+	formatSubject(&subject)
+	res.Subject = subject
+	res.Books = books
+	code, message = http.StatusOK, "success get books by subject"
+
+	return
+}
+
+// getClassification returns subjects and call number of an existing book
+func (suc SubjectUseCase) getClassification(bookId uint) (classification _entity.BookClassification, err error) {
+	classification.BookId = bookId
+	classification.Subjects, err = suc.subjectRepo.GetBookSubjects(bookId)
+
+	if err != nil {
+		return
+	}
+
+	for i := range classification.Subjects {
+		formatSubject(&classification.Subjects[i].Subject)
+	}
+
+	classification.CallNumber, err = suc.subjectRepo.GetCallNumber(bookId)
+
+	return
+}
+
+// generateCallNumber takes class number from the primary subject, or the nearest dewey class above it,
+// falling back to any other dewey subject of the book
+func (suc SubjectUseCase) generateCallNumber(book _entity.Book, subjects []_entity.Subject) (callNumber string, err error) {
+	classNumber := ""
+
+	for _, subject := range subjects {
+		if subject.Scheme == "dewey" {
+			classNumber = subject.Code
+			break
+		}
+
+		ancestors, err := suc.getAncestors(subject)
+
+		if err != nil {
+			return callNumber, err
+		}
+
+		for i := len(ancestors) - 1; i >= 0; i-- {
+			if ancestors[i].Scheme == "dewey" {
+				classNumber = ancestors[i].Code
+				break
+			}
+		}
+
+		if classNumber != "" {
+			break
+		}
+	}
+
+	if classNumber == "" {
+		return
+	}
+
+	authors, err := suc.bookRepo.GetBookAuthors(book.Id)
+
+	if err != nil {
+		return
+	}
+
+	author := ""
+
+	if len(authors) > 0 {
+		author = authors[0].Name
+	}
+
+	callNumber = _helper.CallNumber(classNumber, author, book.Title)
+
+	return
+}
+
+func (suc SubjectUseCase) GetBookClassification(bookId uint) (res _model.GetBookClassificationResponse, code int, message string) {
+	// check book existence
+	book, err := suc.bookRepo.GetBookById(bookId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if book.Title == "" {
+		log.Println("book not found")
+		code, message = http.StatusNotFound, "book not found"
+		return
+	}
+
+	// calling repository
+	res.Classification, err = suc.getClassification(bookId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	code, message = http.StatusOK, "success get book classification"
+
+	return
+}
+
+// SetBookSubjects replaces subjects of the book and generates its call number again
+func (suc SubjectUseCase) SetBookSubjects(bookId uint, req _model.SetBookSubjectsRequest) (res _model.GetBookClassificationResponse, code int, message string) {
+	// check book existence
+	book, err := suc.bookRepo.GetBookById(bookId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if book.Title == "" {
+		log.Println("book not found")
+		code, message = http.StatusNotFound, "book not found"
+		return
+	}
+
+	// check subjects existence, primary subject comes first
+	subjects, subjectIds, seen := []_entity.Subject{}, []uint{}, map[uint]interface{}{}
+	primaryId := req.PrimaryId
+
+	if primaryId == 0 && len(req.SubjectIds) > 0 {
+		primaryId = req.SubjectIds[0]
+	}
+
+	for _, subjectId := range req.SubjectIds {
+		if _, exist := seen[subjectId]; exist {
+			continue
+		}
+
+		seen[subjectId] = nil
+
+		subject, err := suc.subjectRepo.GetSubjectById(subjectId)
+
+		// detect failure in repository
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		if subject.Id == 0 {
+			log.Println("subject not found")
+			code, message = http.StatusNotFound, "subject not found"
+			return
+		}
+
+		if subjectId == primaryId {
+			subjects = append([]_entity.Subject{subject}, subjects...)
+		} else {
+			subjects = append(subjects, subject)
+		}
+
+		subjectIds = append(subjectIds, subjectId)
+	}
+
+	if _, exist := seen[primaryId]; len(subjectIds) > 0 && !exist {
+		log.Println("primary subject not in subjects")
+		code, message = http.StatusBadRequest, "primary subject must be one of the subjects"
+		return
+	}
+
+	callNumber, err := suc.generateCallNumber(book, subjects)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	category := ""
+
+	if len(subjects) > 0 {
+		category = subjects[0].Name
+	}
+
+	// calling repository
+	if err = suc.subjectRepo.SetBookSubjects(bookId, subjectIds, primaryId, callNumber, category); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	res.Classification, err = suc.getClassification(bookId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	code, message = http.StatusOK, "success set book subjects"
+
+	return
+}
+
+// SetCallNumber overrides call number of the book, empty call number generates it again from the subjects
+func (suc SubjectUseCase) SetCallNumber(bookId uint, req _model.SetCallNumberRequest) (res _model.GetBookClassificationResponse, code int, message string) {
+	// check book existence
+	book, err := suc.bookRepo.GetBookById(bookId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if book.Title == "" {
+		log.Println("book not found")
+		code, message = http.StatusNotFound, "book not found"
+		return
+	}
+
+	callNumber := strings.Join(strings.Fields(req.CallNumber), " ")
+
+	if strings.Contains(strings.ReplaceAll(callNumber, " ", ""), ";--") {
+		log.Println("forbidden character")
+		code, message = http.StatusBadRequest, "forbidden character"
+		return
+	}
+
+	if len(callNumber) > 50 {
+		log.Println("input too long")
+		code, message = http.StatusBadRequest, "call number must be at most 50 characters"
+		return
+	}
+
+	if callNumber == "" {
+		bookSubjects, err := suc.subjectRepo.GetBookSubjects(bookId)
+
+		// detect failure in repository
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		subjects := []_entity.Subject{}
+
+		for _, subject := range bookSubjects {
+			subjects = append(subjects, subject.Subject)
+		}
+
+		if callNumber, err = suc.generateCallNumber(book, subjects); err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+	}
+
+	// calling repository
+	if err = suc.subjectRepo.SetCallNumber(bookId, callNumber); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	res.Classification, err = suc.getClassification(bookId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	code, message = http.StatusOK, "success set call number"
+
+	return
+}
@@ -0,0 +1,20 @@
+package webhook
+
+import (
+	_entity "plain-go/public-library/entity"
+	_model "plain-go/public-library/model"
+)
+
+type Webhook interface {
+	CreateWebhook(req _model.CreateWebhookRequest) (res _model.CreateWebhookResponse, code int, message string)
+	GetAllWebhooks() (res _model.GetAllWebhooksResponse, code int, message string)
+	GetWebhookById(webhookId uint) (res _model.GetWebhookByIdResponse, code int, message string)
+	UpdateWebhook(webhookId uint, req _model.UpdateWebhookRequest) (res _model.UpdateWebhookResponse, code int, message string)
+	DeleteWebhook(webhookId uint) (code int, message string)
+	GetAllDeliveries(webhookId uint) (res _model.GetAllWebhookDeliveriesResponse, code int, message string)
+	ReplayDelivery(webhookId uint, deliveryId uint) (res _model.ReplayWebhookDeliveryResponse, code int, message string)
+}
+
+type Subscriber interface {
+	HandleEvent(event _entity.Event) (err error)
+}
@@ -0,0 +1,556 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	_webhookRepository "plain-go/public-library/datastore/webhook"
+	_entity "plain-go/public-library/entity"
+	_helper "plain-go/public-library/helper"
+	_model "plain-go/public-library/model"
+	"strings"
+	"sync"
+	"time"
+)
+
+// event types which can be subscribed by webhook
+var eventTypes = map[string]interface{}{
+	"acquisition.received":     nil,
+	"acquisition.rejected":     nil,
+	"author.merged":            nil,
+	"author.updated":           nil,
+	"book.created":             nil,
+	"book.deleted":             nil,
+	"book.merged":              nil,
+	"book.translated":          nil,
+	"book.translation_deleted": nil,
+	"book.updated":             nil,
+	"request.created":          nil,
+	"request.status_changed":   nil,
+	"review.created":           nil,
+	"review.flagged":           nil,
+	"review.moderated":         nil,
+	"review.replied":           nil,
+	"review.reported":          nil,
+	"reply.moderated":          nil,
+	"review.unflagged":         nil,
+	"wish.created":             nil,
+}
+
+// failed delivery is attempted again after 30s, 1m, 2m and 4m
+const (
+	maxAttempts  = 5
+	firstBackoff = 30 * time.Second
+)
+
+type WebhookUseCase struct {
+	repository _webhookRepository.Webhook
+	client     *http.Client
+	sending    *sync.Map // webhook ids whose deliveries are being sent
+}
+
+func New(webhook _webhookRepository.Webhook) *WebhookUseCase {
+	return &WebhookUseCase{repository: webhook, client: &http.Client{Timeout: 10 * time.Second}, sending: &sync.Map{}}
+}
+
+func checkURL(rawURL string) (err error) {
+	parsed, err := url.Parse(rawURL)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		err = errors.New("url must be absolute http or https url")
+		log.Println(err)
+		return
+	}
+
+	return
+}
+
+func checkEvents(events []string) (cleaned []string, ok bool) {
+	unique := map[string]interface{}{}
+
+	for _, event := range events {
+		event = strings.TrimSpace(event)
+
+		if _, exist := eventTypes[event]; !exist {
+			return nil, false
+		}
+
+		if _, exist := unique[event]; !exist {
+			unique[event] = nil
+			cleaned = append(cleaned, event)
+		}
+	}
+
+	return cleaned, len(cleaned) > 0
+}
+
+func (wuc WebhookUseCase) CreateWebhook(req _model.CreateWebhookRequest) (res _model.CreateWebhookResponse, code int, message string) {
+	// prepare input string
+	rawURL := strings.TrimSpace(req.URL)
+	secret := strings.TrimSpace(req.Secret)
+
+	// check if url is valid
+	if err := checkURL(rawURL); err != nil {
+		code, message = http.StatusBadRequest, "invalid url"
+		return
+	}
+
+	// check if events are supported
+	events, ok := checkEvents(req.Events)
+
+	if !ok {
+		log.Println("unsupported event")
+		code, message = http.StatusBadRequest, "unsupported event"
+		return
+	}
+
+	// generate secret if not provided
+	if secret == "" {
+		key := make([]byte, 32)
+
+		if _, err := rand.Read(key); err != nil {
+			log.Println(err)
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		secret = hex.EncodeToString(key)
+	}
+
+	// prepare input to repository
+	now := time.Now()
+	newWebhook := _entity.Webhook{}
+	newWebhook.URL = rawURL
+	newWebhook.Secret = secret
+	newWebhook.Events = events
+	newWebhook.Active = true
+	newWebhook.CreatedAt = now
+	newWebhook.UpdatedAt = now
+
+	// calling repository
+	webhook, err := wuc.repository.CreateWebhook(newWebhook)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// formatting response, secret is only shown once
+	res.Webhook = webhook
+	res.Webhook.CreatedAt, _ = _helper.TimeFormatter(res.Webhook.CreatedAt)
+	res.Webhook.UpdatedAt, _ = _helper.TimeFormatter(res.Webhook.UpdatedAt)
+	code, message = http.StatusCreated, "success create webhook"
+
+	return
+}
+
+func (wuc WebhookUseCase) GetAllWebhooks() (res _model.GetAllWebhooksResponse, code int, message string) {
+	// calling repository
+	webhooks, err := wuc.repository.GetAllWebhooks()
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// formatting response
+	for _, webhook := range webhooks {
+		webhook.Secret = ""
+		webhook.CreatedAt, _ = _helper.TimeFormatter(webhook.CreatedAt)
+		webhook.UpdatedAt, _ = _helper.TimeFormatter(webhook.UpdatedAt)
+		res.Webhooks = append(res.Webhooks, webhook)
+	}
+
+	code, message = http.StatusOK, "success get all webhooks"
+
+	return
+}
+
+func (wuc WebhookUseCase) GetWebhookById(webhookId uint) (res _model.GetWebhookByIdResponse, code int, message string) {
+	// calling repository
+	webhook, err := wuc.repository.GetWebhookById(webhookId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if webhook.URL == "" {
+		log.Println("webhook not found")
+		code, message = http.StatusNotFound, "webhook not found"
+		return
+	}
+
+	// formatting response
+	res.Webhook = webhook
+	res.Webhook.Secret = ""
+	res.Webhook.CreatedAt, _ = _helper.TimeFormatter(res.Webhook.CreatedAt)
+	res.Webhook.UpdatedAt, _ = _helper.TimeFormatter(res.Webhook.UpdatedAt)
+	code, message = http.StatusOK, "success get webhook"
+
+	return
+}
+
+func (wuc WebhookUseCase) UpdateWebhook(webhookId uint, req _model.UpdateWebhookRequest) (res _model.UpdateWebhookResponse, code int, message string) {
+	// check webhook existence
+	webhook, err := wuc.repository.GetWebhookById(webhookId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if webhook.URL == "" {
+		log.Println("webhook not found")
+		code, message = http.StatusNotFound, "webhook not found"
+		return
+	}
+
+	flag := true
+
+	// prepare input string
+	rawURL := strings.TrimSpace(req.URL)
+
+	if rawURL != "" && rawURL != webhook.URL {
+		if err := checkURL(rawURL); err != nil {
+			code, message = http.StatusBadRequest, "invalid url"
+			return
+		}
+
+		webhook.URL = rawURL
+		flag = false
+	}
+
+	if len(req.Events) > 0 {
+		events, ok := checkEvents(req.Events)
+
+		if !ok {
+			log.Println("unsupported event")
+			code, message = http.StatusBadRequest, "unsupported event"
+			return
+		}
+
+		if strings.Join(events, ",") != strings.Join(webhook.Events, ",") {
+			webhook.Events = events
+			flag = false
+		}
+	}
+
+	if req.Active != nil && *req.Active != webhook.Active {
+		webhook.Active = *req.Active
+		flag = false
+	}
+
+	// check if no field is updated
+	if flag {
+		log.Println("no update was performed")
+		code, message = http.StatusBadRequest, "no update was performed"
+		return
+	}
+
+	// calling repository
+	webhook.UpdatedAt = time.Now()
+	res.Webhook, err = wuc.repository.UpdateWebhook(webhook)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// formatting response
+	res.Webhook.Secret = ""
+	res.Webhook.CreatedAt, _ = _helper.TimeFormatter(res.Webhook.CreatedAt)
+	res.Webhook.UpdatedAt, _ = _helper.TimeFormatter(res.Webhook.UpdatedAt)
+	code, message = http.StatusOK, "success update webhook"
+
+	return
+}
+
+func (wuc WebhookUseCase) DeleteWebhook(webhookId uint) (code int, message string) {
+	// check webhook existence
+	webhook, err := wuc.repository.GetWebhookById(webhookId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if webhook.URL == "" {
+		log.Println("webhook not found")
+		code, message = http.StatusNotFound, "webhook not found"
+		return
+	}
+
+	// calling repository
+	if err = wuc.repository.DeleteWebhook(webhookId); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	code, message = http.StatusOK, "success delete webhook"
+
+	return
+}
+
+func (wuc WebhookUseCase) GetAllDeliveries(webhookId uint) (res _model.GetAllWebhookDeliveriesResponse, code int, message string) {
+	// check webhook existence
+	webhook, err := wuc.repository.GetWebhookById(webhookId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if webhook.URL == "" {
+		log.Println("webhook not found")
+		code, message = http.StatusNotFound, "webhook not found"
+		return
+	}
+
+	// calling repository
+	deliveries, err := wuc.repository.GetDeliveriesByWebhookId(webhookId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// formatting response
+	for _, delivery := range deliveries {
+		delivery.CreatedAt, _ = _helper.TimeFormatter(delivery.CreatedAt)
+		res.Deliveries = append(res.Deliveries, delivery)
+	}
+
+	code, message = http.StatusOK, "success get all webhook deliveries"
+
+	return
+}
+
+func (wuc WebhookUseCase) ReplayDelivery(webhookId uint, deliveryId uint) (res _model.ReplayWebhookDeliveryResponse, code int, message string) {
+	// check webhook existence
+	webhook, err := wuc.repository.GetWebhookById(webhookId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if webhook.URL == "" {
+		log.Println("webhook not found")
+		code, message = http.StatusNotFound, "webhook not found"
+		return
+	}
+
+	// check delivery existence
+	delivery, err := wuc.repository.GetDeliveryById(deliveryId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if delivery.WebhookId != webhookId {
+		log.Println("delivery not found")
+		code, message = http.StatusNotFound, "delivery not found"
+		return
+	}
+
+	// replay is a single synchronous attempt with the original payload, recorded as a new delivery
+	replay := _entity.WebhookDelivery{WebhookId: webhook.Id, EventId: delivery.EventId, EventType: delivery.EventType, Payload: delivery.Payload, Attempt: 1}
+	res.Delivery, err = wuc.repository.CreateDelivery(wuc.send(webhook, replay))
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	// formatting response
+	res.Delivery.CreatedAt, _ = _helper.TimeFormatter(res.Delivery.CreatedAt)
+	code, message = http.StatusOK, "success replay webhook delivery"
+
+	return
+}
+
+// HandleEvent queues the event for every subscribed webhook, it never calls the webhook itself
+// so a slow or dead endpoint cannot hold back the outbox and the other subscribers
+func (wuc WebhookUseCase) HandleEvent(event _entity.Event) (err error) {
+	// calling repository
+	webhooks, err := wuc.repository.GetActiveWebhooksByEvent(event.Type)
+
+	if err != nil || len(webhooks) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	now := time.Now()
+
+	for _, webhook := range webhooks {
+		// event retried by the outbox is queued only once per webhook
+		attempts, _, err := wuc.repository.CountDeliveriesByEvent(webhook.Id, event.Id)
+
+		if err != nil {
+			return err
+		}
+
+		if attempts > 0 {
+			continue
+		}
+
+		delivery := _entity.WebhookDelivery{WebhookId: webhook.Id, EventId: event.Id, EventType: event.Type, Payload: string(payload), Attempt: 1, NextAttemptAt: now, CreatedAt: now}
+
+		if _, err = wuc.repository.CreateDelivery(delivery); err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// Run sends queued deliveries periodically
+func (wuc WebhookUseCase) Run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		wuc.deliverDue()
+	}
+}
+
+// deliverDue sends due deliveries, every webhook in its own goroutine so a dead endpoint only delays itself,
+// webhook still sending from previous run is skipped, failed attempt is queued again with backoff
+// until maximum attempts, then it can still be replayed by librarian
+func (wuc WebhookUseCase) deliverDue() {
+	// calling repository
+	deliveries, err := wuc.repository.GetDueDeliveries(100)
+
+	if err != nil {
+		return
+	}
+
+	byWebhook := map[uint][]_entity.WebhookDelivery{}
+
+	for _, delivery := range deliveries {
+		byWebhook[delivery.WebhookId] = append(byWebhook[delivery.WebhookId], delivery)
+	}
+
+	for webhookId, deliveries := range byWebhook {
+		if _, busy := wuc.sending.LoadOrStore(webhookId, nil); busy {
+			continue
+		}
+
+		go func(webhookId uint, deliveries []_entity.WebhookDelivery) {
+			defer wuc.sending.Delete(webhookId)
+
+			webhook, err := wuc.repository.GetWebhookById(webhookId)
+
+			if err != nil {
+				return
+			}
+
+			for _, delivery := range deliveries {
+				wuc.attempt(webhook, delivery)
+			}
+		}(webhookId, deliveries)
+	}
+}
+
+func (wuc WebhookUseCase) attempt(webhook _entity.Webhook, delivery _entity.WebhookDelivery) {
+	// webhook deleted or deactivated after the event was queued
+	if webhook.URL == "" || !webhook.Active {
+		delivery.Error = "webhook is not active"
+		delivery.CreatedAt = time.Now()
+		wuc.repository.CompleteDelivery(delivery)
+		return
+	}
+
+	delivery, err := wuc.repository.CompleteDelivery(wuc.send(webhook, delivery))
+
+	if err != nil || delivery.Success {
+		return
+	}
+
+	if delivery.Attempt >= maxAttempts {
+		log.Println("webhook delivery failed after maximum attempts:", webhook.URL, delivery.EventId)
+		return
+	}
+
+	now := time.Now()
+	next := _entity.WebhookDelivery{WebhookId: delivery.WebhookId, EventId: delivery.EventId, EventType: delivery.EventType, Payload: delivery.Payload, Attempt: delivery.Attempt + 1, CreatedAt: now}
+	next.NextAttemptAt = now.Add(firstBackoff << (delivery.Attempt - 1))
+
+	wuc.repository.CreateDelivery(next)
+}
+
+// send performs one signed POST and fills its outcome into delivery, it does not record anything
+func (wuc WebhookUseCase) send(webhook _entity.Webhook, delivery _entity.WebhookDelivery) _entity.WebhookDelivery {
+	delivery.ResponseCode, delivery.Error, delivery.Success = 0, "", false
+	delivery.CreatedAt = time.Now()
+	payload := []byte(delivery.Payload)
+
+	// sign payload with webhook secret
+	mac := hmac.New(sha256.New, []byte(webhook.Secret))
+	mac.Write(payload)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(payload))
+
+	if err != nil {
+		log.Println(err)
+		delivery.Error = err.Error()
+		return delivery
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "public-library-webhook")
+	req.Header.Set("X-Library-Event", delivery.EventType)
+	req.Header.Set("X-Library-Event-Id", delivery.EventId)
+	req.Header.Set("X-Library-Signature", "sha256="+signature)
+
+	response, err := wuc.client.Do(req)
+
+	if err != nil {
+		log.Println(err)
+		delivery.Error = err.Error()
+		return delivery
+	}
+
+	response.Body.Close()
+
+	delivery.ResponseCode = response.StatusCode
+	delivery.Success = response.StatusCode >= 200 && response.StatusCode < 300
+
+	if !delivery.Success {
+		delivery.Error = response.Status
+	}
+
+	return delivery
+}
@@ -5,6 +5,7 @@ import (
 	// "net/http"
 	"log"
 	"net/http"
+	_acquisitionRepository "plain-go/public-library/datastore/acquisition"
 	_bookRepository "plain-go/public-library/datastore/book"
 	_userRepository "plain-go/public-library/datastore/user"
 	_entity "plain-go/public-library/entity"
@@ -14,15 +15,78 @@ import (
 
 	// _entity "plain-go/public-library/entity"
 	_model "plain-go/public-library/model"
+	_duplicate "plain-go/public-library/usecase/duplicate"
 )
 
 type WishUseCase struct {
-	bookRepo _bookRepository.Book
-	userRepo _userRepository.User
+	bookRepo        _bookRepository.Book
+	userRepo        _userRepository.User
+	acquisitionRepo _acquisitionRepository.Acquisition
+	detector        _duplicate.Detector
 }
 
-func New(book _bookRepository.Book, user _userRepository.User) *WishUseCase {
-	return &WishUseCase{bookRepo: book, userRepo: user}
+func New(book _bookRepository.Book, user _userRepository.User, acquisition _acquisitionRepository.Acquisition, detector _duplicate.Detector) *WishUseCase {
+	return &WishUseCase{bookRepo: book, userRepo: user, acquisitionRepo: acquisition, detector: detector}
+}
+
+// groupWish attaches wish to the acquisition candidate of the same title and authors
+func (wuc WishUseCase) groupWish(wish _entity.Wish) (err error) {
+	// calling repository
+	authors, err := wuc.bookRepo.GetWishAuthors(wish.Id)
+
+	if err != nil {
+		return
+	}
+
+	names := []string{}
+
+	for _, author := range authors {
+		names = append(names, author.Name)
+	}
+
+	key := _helper.CandidateKey(wish.Title, names)
+	candidate, err := wuc.acquisitionRepo.GetCandidateByKey(key)
+
+	if err != nil {
+		return
+	}
+
+	// if candidate does not exist, create new
+	if candidate.Id == 0 {
+		now := time.Now()
+		candidate.Key = key
+		candidate.Title = wish.Title
+		candidate.Authors = strings.Join(names, ", ")
+		candidate.Category = wish.Category
+		candidate.Status = "under_review"
+		candidate.CreatedAt = now
+		candidate.UpdatedAt = now
+
+		if candidate, err = wuc.acquisitionRepo.CreateCandidate(candidate); err != nil {
+			return
+		}
+	}
+
+	return wuc.acquisitionRepo.AttachWish(wish.Id, candidate.Id)
+}
+
+// GroupWishes attaches wishes made before grouping existed to their candidate,
+// wishes created or updated afterwards are grouped as they are written
+func (wuc WishUseCase) GroupWishes() (err error) {
+	// calling repository
+	wishes, err := wuc.acquisitionRepo.GetUngroupedWishes()
+
+	if err != nil {
+		return
+	}
+
+	for _, wish := range wishes {
+		if err = wuc.groupWish(_entity.Wish{Id: wish.Id, Title: wish.Title, Category: wish.Category}); err != nil {
+			return
+		}
+	}
+
+	return
 }
 
 func (wuc WishUseCase) AddBookToWishlist(userId uint, req _model.AddBookToWishlistRequest) (res _model.AddBookToWishlistResponse, code int, message string) {
@@ -105,6 +169,36 @@ func (wuc WishUseCase) AddBookToWishlist(userId uint, req _model.AddBookToWishli
 		return
 	}
 
+	// check if the same book is already exist under different spelling
+	names := []string{}
+
+	for _, _author := range req.Author {
+		names = append(names, _author.Name)
+	}
+
+	exact, similarBooks, err := wuc.detector.FindBooks(title, names)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if exact.Id != 0 {
+		log.Println("book already exist")
+		code, message = http.StatusConflict, "book already exist"
+		return
+	}
+
+	// similar suggestion may be upvoted instead
+	similarSuggestions, err := wuc.detector.FindSuggestions(title)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
 	// check if book already in wishlist
 	wishes, err := wuc.bookRepo.GetWishesByUserId(userId)
 
@@ -115,7 +209,7 @@ func (wuc WishUseCase) AddBookToWishlist(userId uint, req _model.AddBookToWishli
 	}
 
 	for _, wish := range wishes {
-		if wish.Title == title {
+		if _helper.NormalizeTitle(wish.Title) == _helper.NormalizeTitle(title) {
 			code, message = http.StatusConflict, "book already in wishlist"
 			return
 		}
@@ -130,8 +224,15 @@ func (wuc WishUseCase) AddBookToWishlist(userId uint, req _model.AddBookToWishli
 	newWish.CreatedAt = now
 	newWish.UpdatedAt = now
 
+	// record wish creation as event
+	event := _entity.Event{}
+	event.Type = "wish.created"
+	event.AggregateType = "wish"
+	event.UserId = userId
+	event.Payload = map[string]interface{}{"title": newWish.Title, "category": newWish.Category}
+
 	// calling repository
-	res.Wish, err = wuc.bookRepo.AddBookToWishlist(userId, newWish)
+	res.Wish, err = wuc.bookRepo.AddBookToWishlist(userId, newWish, event)
 
 	// detect failure in repository
 	if err != nil {
@@ -139,10 +240,12 @@ func (wuc WishUseCase) AddBookToWishlist(userId uint, req _model.AddBookToWishli
 		return
 	}
 
+	res.PossibleDuplicates = append(similarBooks, similarSuggestions...)
+
 	// create author
 	for _, _author := range req.Author {
-		// calling repository
-		author, err := wuc.bookRepo.GetAuthorByName(_author.Name)
+		// reuse author of the same normalized name, otherwise create new
+		author, similar, err := wuc.detector.ResolveAuthor(_author.Name)
 
 		// detect failure in repository
 		if err != nil {
@@ -150,22 +253,10 @@ func (wuc WishUseCase) AddBookToWishlist(userId uint, req _model.AddBookToWishli
 			return
 		}
 
-		// if author does not exist, create new
-		if author.Name == "" {
-			author.Name = _author.Name
-
-			// calling repository
-			author, err = wuc.bookRepo.CreateNewAuthor(author)
-
-			// detect failure in repository
-			if err != nil {
-				code, message = http.StatusInternalServerError, "internal server error"
-				return
-			}
-		}
+		res.PossibleDuplicates = append(res.PossibleDuplicates, similar...)
 
 		// if author exist or after author created, create book author junction
-		if err = wuc.bookRepo.CreateWishAuthorJunction(newWish, author); err != nil {
+		if err = wuc.bookRepo.CreateWishAuthorJunction(res.Wish, author); err != nil {
 			code, message = http.StatusInternalServerError, "internal server error"
 			return
 		}
@@ -173,6 +264,12 @@ func (wuc WishUseCase) AddBookToWishlist(userId uint, req _model.AddBookToWishli
 		res.Wish.Author = append(res.Wish.Author, author)
 	}
 
+	// group wish for acquisition
+	if err = wuc.groupWish(res.Wish); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
 	res.Wish.CreatedAt, _ = _helper.TimeFormatter(res.Wish.CreatedAt)
 	res.Wish.UpdatedAt, _ = _helper.TimeFormatter(res.Wish.UpdatedAt)
 	code, message = http.StatusCreated, "success add book to wishlist"
@@ -425,8 +522,8 @@ func (wuc WishUseCase) UpdateWish(req _model.UpdateWishRequest, userId uint, wis
 
 	// create author if len(createdAuthors) > 0
 	for _, _author := range createdAuthors {
-		// calling repository
-		author, err := wuc.bookRepo.GetAuthorByName(_author.Name)
+		// reuse author of the same normalized name, otherwise create new
+		author, similar, err := wuc.detector.ResolveAuthor(_author.Name)
 
 		// detect failure in repository
 		if err != nil {
@@ -434,19 +531,7 @@ func (wuc WishUseCase) UpdateWish(req _model.UpdateWishRequest, userId uint, wis
 			return
 		}
 
-		// if author does not exist, create new
-		if author.Name == "" {
-			author.Name = _author.Name
-
-			// calling repository
-			author, err = wuc.bookRepo.CreateNewAuthor(author)
-
-			// detect failure in repository
-			if err != nil {
-				code, message = http.StatusInternalServerError, "internal server error"
-				return
-			}
-		}
+		res.PossibleDuplicates = append(res.PossibleDuplicates, similar...)
 
 		// if author exist or after author created, create book author junction
 		if err = wuc.bookRepo.CreateWishAuthorJunction(wish, author); err != nil {
@@ -479,6 +564,12 @@ func (wuc WishUseCase) UpdateWish(req _model.UpdateWishRequest, userId uint, wis
 		return
 	}
 
+	// regroup wish, title or authors may have changed
+	if err = wuc.groupWish(wish); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
 	// formatting response
 	res.Wish = _wish
 	res.Wish.Id = wish.Id
@@ -0,0 +1,22 @@
+package work
+
+import (
+	"net/url"
+	_model "plain-go/public-library/model"
+)
+
+type Work interface {
+	GetAllWorks(query url.Values) (res _model.GetAllWorksResponse, code int, message string)
+	CreateWork(req _model.CreateWorkRequest) (res _model.CreateWorkResponse, code int, message string)
+	GetWorkById(workId uint) (res _model.GetWorkByIdResponse, code int, message string)
+	UpdateWork(workId uint, req _model.UpdateWorkRequest) (res _model.UpdateWorkResponse, code int, message string)
+	AddEdition(workId uint, bookId uint) (res _model.GetWorkByIdResponse, code int, message string)
+	RemoveEdition(workId uint, bookId uint) (code int, message string)
+	GetWorkReviews(workId uint, query url.Values) (res _model.GetWorkReviewsResponse, code int, message string)
+	GetAllSeries() (res _model.GetAllSeriesResponse, code int, message string)
+	CreateSeries(req _model.CreateSeriesRequest) (res _model.CreateSeriesResponse, code int, message string)
+	GetSeriesById(seriesId uint) (res _model.GetSeriesByIdResponse, code int, message string)
+	UpdateSeries(seriesId uint, req _model.UpdateSeriesRequest) (res _model.UpdateSeriesResponse, code int, message string)
+	SaveSeriesWork(seriesId uint, workId uint, req _model.SaveSeriesWorkRequest) (res _model.SaveSeriesWorkResponse, code int, message string)
+	RemoveSeriesWork(seriesId uint, workId uint) (code int, message string)
+}
@@ -0,0 +1,654 @@
+package work
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	_bookRepository "plain-go/public-library/datastore/book"
+	_userRepository "plain-go/public-library/datastore/user"
+	_workRepository "plain-go/public-library/datastore/work"
+	_entity "plain-go/public-library/entity"
+	_helper "plain-go/public-library/helper"
+	_model "plain-go/public-library/model"
+	_reviewUseCase "plain-go/public-library/usecase/review"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type WorkUseCase struct {
+	workRepo _workRepository.Work
+	bookRepo _bookRepository.Book
+	userRepo _userRepository.User
+}
+
+func New(work _workRepository.Work, book _bookRepository.Book, user _userRepository.User) *WorkUseCase {
+	return &WorkUseCase{workRepo: work, bookRepo: book, userRepo: user}
+}
+
+// validateText checks title of work or name of series with its description
+func validateText(title string, description string) (code int, message string) {
+	// check if required input is empty
+	if title == "" {
+		log.Println("empty input")
+		code, message = http.StatusBadRequest, "empty input"
+		return
+	}
+
+	for _, s := range []string{title, description} {
+		// check if there is any forbidden character
+		if strings.Contains(strings.ReplaceAll(s, " ", ""), ";--") {
+			log.Println("forbidden character")
+			code, message = http.StatusBadRequest, "forbidden character"
+			return
+		}
+	}
+
+	if len(title) > 255 || len(description) > 2000 {
+		log.Println("input too long")
+		code, message = http.StatusBadRequest, "title must be at most 255 and description at most 2000 characters"
+		return
+	}
+
+	return
+}
+
+func formatWork(work *_entity.Work) {
+	work.CreatedAt, _ = _helper.TimeFormatter(work.CreatedAt)
+	work.UpdatedAt, _ = _helper.TimeFormatter(work.UpdatedAt)
+}
+
+func (wuc WorkUseCase) formatEdition(book *_entity.Book) {
+	book.CreatedAt, _ = _helper.TimeFormatter(book.CreatedAt)
+	book.UpdatedAt, _ = _helper.TimeFormatter(book.UpdatedAt)
+	book.Quantity, _ = wuc.bookRepo.CountBookById(book.Id)
+	book.Author, _ = wuc.bookRepo.GetBookAuthors(book.Id)
+	book.FavoriteCount, _ = wuc.bookRepo.CountFavoritesByBookId(book.Id)
+	averageStar, _ := wuc.bookRepo.CountStarsByBookId(book.Id)
+	book.AverageStar = _helper.NilHandler(averageStar)
+}
+
+// getWork returns work with its editions and the series it belongs to
+func (wuc WorkUseCase) getWork(workId uint) (work _entity.Work, code int, message string) {
+	// calling repository
+	work, err := wuc.workRepo.GetWorkById(workId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if work.Title == "" {
+		log.Println("work not found")
+		code, message = http.StatusNotFound, "work not found"
+		return
+	}
+
+	work.Editions, err = wuc.workRepo.GetEditions(workId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	for i := range work.Editions {
+		wuc.formatEdition(&work.Editions[i])
+	}
+
+	work.Series, err = wuc.workRepo.GetSeriesByWorkId(workId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	formatWork(&work)
+
+	return
+}
+
+func (wuc WorkUseCase) GetAllWorks(query url.Values) (res _model.GetAllWorksResponse, code int, message string) {
+	// default parameters
+	params := _model.GetAllWorksRequest{}
+	params.Page = 1
+	params.Records = 10
+
+	if value, exist := query["page"]; exist {
+		page, err := strconv.Atoi(value[0])
+
+		if err != nil {
+			log.Println(err)
+			code, message = http.StatusBadRequest, "invalid page"
+			return
+		}
+
+		if page < 1 {
+			log.Println("invalid page")
+			code, message = http.StatusBadRequest, "invalid page"
+			return
+		}
+
+		params.Page = page
+	}
+
+	mapRecords := map[int]interface{}{10: nil, 20: nil, 50: nil}
+
+	if value, exist := query["records"]; exist {
+		records, err := strconv.Atoi(value[0])
+
+		if err != nil {
+			log.Println(err)
+			code, message = http.StatusBadRequest, "invalid number of records"
+			return
+		}
+
+		if _, exist := mapRecords[records]; !exist {
+			log.Println("unaccepted number of records")
+			code, message = http.StatusBadRequest, "unaccepted number of records"
+			return
+		}
+
+		params.Records = records
+	}
+
+	if value, exist := query["keyword"]; exist {
+		params.Keyword = strings.TrimSpace(value[0])
+	}
+
+	// calling repository
+	works, err := wuc.workRepo.GetAllWorks(params)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	res.Count, err = wuc.workRepo.CountWorks(params)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	for i := range works {
+		formatWork(&works[i])
+	}
+
+	res.Works = works
+	code, message = http.StatusOK, "success get all works"
+
+	return
+}
+
+func (wuc WorkUseCase) CreateWork(req _model.CreateWorkRequest) (res _model.CreateWorkResponse, code int, message string) {
+	// prepare input string
+	title := strings.TrimSpace(req.Title)
+	description := strings.TrimSpace(req.Description)
+
+	if code, message = validateText(title, description); code != 0 {
+		return
+	}
+
+	// check editions existence, edition of another work is moved to this one
+	for _, bookId := range req.BookIds {
+		book, err := wuc.bookRepo.GetBookById(bookId)
+
+		// detect failure in repository
+		if err != nil {
+			code, message = http.StatusInternalServerError, "internal server error"
+			return
+		}
+
+		if book.Title == "" {
+			log.Println("book not found")
+			code, message = http.StatusNotFound, "book not found"
+			return
+		}
+	}
+
+	// prepare input to repository
+	now := time.Now()
+	newWork := _entity.Work{}
+	newWork.Title = title
+	newWork.Description = description
+	newWork.CreatedAt = now
+	newWork.UpdatedAt = now
+
+	// calling repository
+	work, err := wuc.workRepo.CreateWork(newWork, req.BookIds)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	res.Work, code, message = wuc.getWork(work.Id)
+
+	if code != 0 {
+		return
+	}
+
+	code, message = http.StatusCreated, "success create work"
+
+	return
+}
+
+func (wuc WorkUseCase) GetWorkById(workId uint) (res _model.GetWorkByIdResponse, code int, message string) {
+	res.Work, code, message = wuc.getWork(workId)
+
+	if code != 0 {
+		return
+	}
+
+	code, message = http.StatusOK, "success get work"
+
+	return
+}
+
+func (wuc WorkUseCase) UpdateWork(workId uint, req _model.UpdateWorkRequest) (res _model.UpdateWorkResponse, code int, message string) {
+	// check work existence
+	work, err := wuc.workRepo.GetWorkById(workId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if work.Title == "" {
+		log.Println("work not found")
+		code, message = http.StatusNotFound, "work not found"
+		return
+	}
+
+	// empty field keeps its current value
+	if title := strings.TrimSpace(req.Title); title != "" {
+		work.Title = title
+	}
+
+	if description := strings.TrimSpace(req.Description); description != "" {
+		work.Description = description
+	}
+
+	if code, message = validateText(work.Title, work.Description); code != 0 {
+		return
+	}
+
+	// prepare input to repository
+	work.UpdatedAt = time.Now()
+
+	// calling repository
+	res.Work, err = wuc.workRepo.UpdateWork(work)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	formatWork(&res.Work)
+	code, message = http.StatusOK, "success update work"
+
+	return
+}
+
+func (wuc WorkUseCase) AddEdition(workId uint, bookId uint) (res _model.GetWorkByIdResponse, code int, message string) {
+	// check work existence
+	work, err := wuc.workRepo.GetWorkById(workId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if work.Title == "" {
+		log.Println("work not found")
+		code, message = http.StatusNotFound, "work not found"
+		return
+	}
+
+	// check book existence
+	book, err := wuc.bookRepo.GetBookById(bookId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if book.Title == "" {
+		log.Println("book not found")
+		code, message = http.StatusNotFound, "book not found"
+		return
+	}
+
+	// calling repository
+	if err = wuc.workRepo.SetBookWork(bookId, workId); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	res.Work, code, message = wuc.getWork(workId)
+
+	if code != 0 {
+		return
+	}
+
+	code, message = http.StatusOK, "success add edition"
+
+	return
+}
+
+func (wuc WorkUseCase) RemoveEdition(workId uint, bookId uint) (code int, message string) {
+	// check if book is an edition of the work
+	currentWorkId, err := wuc.workRepo.GetWorkIdByBookId(bookId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if currentWorkId == 0 || currentWorkId != workId {
+		log.Println("edition not found")
+		code, message = http.StatusNotFound, "edition not found"
+		return
+	}
+
+	// calling repository
+	if err = wuc.workRepo.SetBookWork(bookId, 0); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	code, message = http.StatusOK, "success remove edition"
+
+	return
+}
+
+func (wuc WorkUseCase) GetWorkReviews(workId uint, query url.Values) (res _model.GetWorkReviewsResponse, code int, message string) {
+	params, code, message := _reviewUseCase.ParseReviewParams(query)
+
+	if code != 0 {
+		return
+	}
+
+	// check work existence
+	work, err := wuc.workRepo.GetWorkById(workId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if work.Title == "" {
+		log.Println("work not found")
+		code, message = http.StatusNotFound, "work not found"
+		return
+	}
+
+	// reviews of every edition are listed together
+	reviews, err := wuc.workRepo.GetReviewsByWorkId(workId, params)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if err = _reviewUseCase.FormatReviews(reviews, wuc.bookRepo, wuc.userRepo); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	formatWork(&work)
+	res.Work = work
+	res.Reviews = reviews
+	code, message = http.StatusOK, "success get all reviews"
+
+	return
+}
+
+func (wuc WorkUseCase) GetAllSeries() (res _model.GetAllSeriesResponse, code int, message string) {
+	// calling repository
+	series, err := wuc.workRepo.GetAllSeries()
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	for i := range series {
+		series[i].CreatedAt, _ = _helper.TimeFormatter(series[i].CreatedAt)
+		series[i].UpdatedAt, _ = _helper.TimeFormatter(series[i].UpdatedAt)
+	}
+
+	res.Series = series
+	code, message = http.StatusOK, "success get all series"
+
+	return
+}
+
+func (wuc WorkUseCase) CreateSeries(req _model.CreateSeriesRequest) (res _model.CreateSeriesResponse, code int, message string) {
+	// prepare input string
+	name := strings.TrimSpace(req.Name)
+	description := strings.TrimSpace(req.Description)
+
+	if code, message = validateText(name, description); code != 0 {
+		return
+	}
+
+	// prepare input to repository
+	now := time.Now()
+	newSeries := _entity.Series{}
+	newSeries.Name = name
+	newSeries.Description = description
+	newSeries.CreatedAt = now
+	newSeries.UpdatedAt = now
+
+	// calling repository
+	series, err := wuc.workRepo.CreateSeries(newSeries)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	series.CreatedAt, _ = _helper.TimeFormatter(series.CreatedAt)
+	series.UpdatedAt, _ = _helper.TimeFormatter(series.UpdatedAt)
+	res.Series = series
+	code, message = http.StatusCreated, "success create series"
+
+	return
+}
+
+// getSeries returns series with its works in reading order
+func (wuc WorkUseCase) getSeries(seriesId uint) (series _entity.Series, code int, message string) {
+	// calling repository
+	series, err := wuc.workRepo.GetSeriesById(seriesId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if series.Name == "" {
+		log.Println("series not found")
+		code, message = http.StatusNotFound, "series not found"
+		return
+	}
+
+	series.Works, err = wuc.workRepo.GetSeriesWorks(seriesId)
+
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	for i := range series.Works {
+		formatWork(series.Works[i].Work)
+	}
+
+	series.CreatedAt, _ = _helper.TimeFormatter(series.CreatedAt)
+	series.UpdatedAt, _ = _helper.TimeFormatter(series.UpdatedAt)
+
+	return
+}
+
+func (wuc WorkUseCase) GetSeriesById(seriesId uint) (res _model.GetSeriesByIdResponse, code int, message string) {
+	res.Series, code, message = wuc.getSeries(seriesId)
+
+	if code != 0 {
+		return
+	}
+
+	code, message = http.StatusOK, "success get series"
+
+	return
+}
+
+func (wuc WorkUseCase) UpdateSeries(seriesId uint, req _model.UpdateSeriesRequest) (res _model.UpdateSeriesResponse, code int, message string) {
+	// check series existence
+	series, err := wuc.workRepo.GetSeriesById(seriesId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if series.Name == "" {
+		log.Println("series not found")
+		code, message = http.StatusNotFound, "series not found"
+		return
+	}
+
+	// empty field keeps its current value
+	if name := strings.TrimSpace(req.Name); name != "" {
+		series.Name = name
+	}
+
+	if description := strings.TrimSpace(req.Description); description != "" {
+		series.Description = description
+	}
+
+	if code, message = validateText(series.Name, series.Description); code != 0 {
+		return
+	}
+
+	// prepare input to repository
+	series.UpdatedAt = time.Now()
+
+	// calling repository
+	res.Series, err = wuc.workRepo.UpdateSeries(series)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	res.Series.CreatedAt, _ = _helper.TimeFormatter(res.Series.CreatedAt)
+	res.Series.UpdatedAt, _ = _helper.TimeFormatter(res.Series.UpdatedAt)
+	code, message = http.StatusOK, "success update series"
+
+	return
+}
+
+func (wuc WorkUseCase) SaveSeriesWork(seriesId uint, workId uint, req _model.SaveSeriesWorkRequest) (res _model.SaveSeriesWorkResponse, code int, message string) {
+	// check series existence
+	series, err := wuc.workRepo.GetSeriesById(seriesId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if series.Name == "" {
+		log.Println("series not found")
+		code, message = http.StatusNotFound, "series not found"
+		return
+	}
+
+	// check work existence
+	work, err := wuc.workRepo.GetWorkById(workId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	if work.Title == "" {
+		log.Println("work not found")
+		code, message = http.StatusNotFound, "work not found"
+		return
+	}
+
+	// position starts from 1
+	if req.Position < 1 {
+		log.Println("invalid position")
+		code, message = http.StatusBadRequest, "position must be at least 1"
+		return
+	}
+
+	// calling repository
+	if err = wuc.workRepo.SaveSeriesWork(seriesId, workId, req.Position); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	res.Series, code, message = wuc.getSeries(seriesId)
+
+	if code != 0 {
+		return
+	}
+
+	code, message = http.StatusOK, "success save work in series"
+
+	return
+}
+
+func (wuc WorkUseCase) RemoveSeriesWork(seriesId uint, workId uint) (code int, message string) {
+	// check if work is in the series
+	entries, err := wuc.workRepo.GetSeriesByWorkId(workId)
+
+	// detect failure in repository
+	if err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	found := false
+
+	for _, entry := range entries {
+		if entry.SeriesId == seriesId {
+			found = true
+		}
+	}
+
+	if !found {
+		log.Println("work not found in series")
+		code, message = http.StatusNotFound, "work not found in series"
+		return
+	}
+
+	// calling repository
+	if err = wuc.workRepo.RemoveSeriesWork(seriesId, workId); err != nil {
+		code, message = http.StatusInternalServerError, "internal server error"
+		return
+	}
+
+	code, message = http.StatusOK, "success remove work from series"
+
+	return
+}